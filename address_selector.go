@@ -0,0 +1,59 @@
+package esclient
+
+import (
+	"net/url"
+	"sync"
+
+	"github.com/elastic/elastic-transport-go/v8/elastictransport"
+	"github.com/pkg/errors"
+)
+
+// weightedSelector picks node of cluster by smooth weighted round-robin, so node with weight 3
+// gets 3 of every 4 requests interleaved with others instead of in bursts.
+// Transport connection pool passes only live nodes: node failing with connection error is ejected
+// and retried after growing timeout, and request is retried on next node.
+type weightedSelector struct {
+	mu      sync.Mutex
+	weights map[string]int // node host -> weight
+	current map[string]int // node host -> current weight
+}
+
+// newWeightedSelector returns selector of cluster nodes with AddressWeights, nil if weights are not set.
+func newWeightedSelector(cfg ClusterConfig) elastictransport.Selector {
+	if len(cfg.AddressWeights) == 0 {
+		return nil
+	}
+	s := &weightedSelector{
+		weights: make(map[string]int, len(cfg.Addresses)),
+		current: make(map[string]int, len(cfg.Addresses)),
+	}
+	for i, address := range cfg.Addresses {
+		if u, err := url.Parse(address); err == nil {
+			s.weights[u.Host] = cfg.AddressWeights[i]
+		}
+	}
+	return s
+}
+
+// Select returns live node with highest current weight.
+func (s *weightedSelector) Select(conns []*elastictransport.Connection) (*elastictransport.Connection, error) {
+	if len(conns) == 0 {
+		return nil, errors.New("no connection available")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var best *elastictransport.Connection
+	total := 0
+	for _, conn := range conns {
+		weight := max(s.weights[conn.URL.Host], 1)
+		s.current[conn.URL.Host] += weight
+		total += weight
+		if best == nil || s.current[conn.URL.Host] > s.current[best.URL.Host] {
+			best = conn
+		}
+	}
+	s.current[best.URL.Host] -= total
+	return best, nil
+}
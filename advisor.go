@@ -0,0 +1,262 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const defaultMaxShardSizeBytes = 50 << 30 // 50GB, recommended upper bound for ES shards
+
+// ShardAction represents action recommended by shard size advisor.
+type ShardAction string
+
+const (
+	ShardActionRollover ShardAction = "rollover" // Roll write alias over to a new index
+	ShardActionSplit    ShardAction = "split"    // Split index into more primary shards
+)
+
+// ShardAdvisorConfig configures shard size advisor.
+type ShardAdvisorConfig struct {
+	MaxShardSizeBytes int64 // Max primary shard size before action is recommended (default: 50GB)
+
+	// Approve is called for every recommendation; action is executed only if it returns true.
+	// If nil, advisor only recommends and never executes.
+	Approve func(ctx context.Context, advice ShardAdvice) bool
+
+	// SplitTargetName returns name of split target index.
+	// Default prefixes source name with "split<N>-" so per-company UUID suffix is preserved.
+	SplitTargetName func(index string, shards int) string
+}
+
+// ShardAdvice represents recommendation for a single oversized index.
+type ShardAdvice struct {
+	Cluster           string      // Cluster name in registry
+	Index             string      // Index name
+	Alias             string      // Write alias pointing to index (rollover only)
+	PrimaryShards     int         // Current number of primary shards
+	PrimarySizeBytes  int64       // Total size of primary shards
+	AvgShardSizeBytes int64       // Average primary shard size
+	Action            ShardAction // Recommended action
+	TargetShards      int         // Target number of primary shards (split only)
+	Target            string      // Split target index, set when executed (split only)
+	Executed          bool        // Action was approved and executed successfully
+}
+
+// catIndex is subset of _cat/indices response.
+type catIndex struct {
+	Index         string `json:"index"`
+	Primaries     string `json:"pri"`
+	PriStoreBytes string `json:"pri.store.size"`
+}
+
+// AdviseShardSizes inspects indices of every registered cluster and recommends
+// rollover (for write indices behind alias) or split for indices whose average
+// primary shard size exceeds configured threshold. System and hidden indices are skipped.
+// Approved actions are executed; split moves aliases of index to split target.
+func (r *Registry) AdviseShardSizes(ctx context.Context, cfg ShardAdvisorConfig) ([]ShardAdvice, error) {
+	if cfg.MaxShardSizeBytes <= 0 {
+		cfg.MaxShardSizeBytes = defaultMaxShardSizeBytes
+	}
+	if cfg.SplitTargetName == nil {
+		cfg.SplitTargetName = func(index string, shards int) string {
+			return fmt.Sprintf("split%d-%s", shards, index)
+		}
+	}
+
+	names := r.ListClusters()
+	sort.Strings(names)
+
+	var result []ShardAdvice
+	for _, name := range names {
+		client, err := r.GetTypedClient(name)
+		if IsDegraded(err) {
+			continue
+		}
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to get client for cluster %q", name)
+		}
+
+		advices, err := client.adviseShardSizes(ctx, cfg)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to advise cluster %q", name)
+		}
+
+		for i := range advices {
+			advices[i].Cluster = name
+			if cfg.Approve == nil || !cfg.Approve(ctx, advices[i]) {
+				continue
+			}
+			if err := client.executeShardAdvice(ctx, &advices[i], cfg); err != nil {
+				return append(result, advices[:i+1]...), errors.Wrapf(err, "failed to %s index %q on cluster %q", advices[i].Action, advices[i].Index, name)
+			}
+			advices[i].Executed = true
+		}
+		result = append(result, advices...)
+	}
+
+	return result, nil
+}
+
+// adviseShardSizes builds recommendations for oversized indices of a single cluster.
+func (c *Client) adviseShardSizes(ctx context.Context, cfg ShardAdvisorConfig) ([]ShardAdvice, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	query.Set("bytes", "b")
+	query.Set("h", "index,pri,pri.store.size")
+	query.Set("expand_wildcards", "open") // hidden indices are managed by their owners
+
+	var indices []catIndex
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, "/_cat/indices", query, nil, &indices)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "cat_indices", StatusCode: status, body: errBody}
+	}
+
+	aliases, err := c.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	writeAliases := writeAliasesByIndex(aliases)
+
+	var advices []ShardAdvice
+	for _, idx := range indices {
+		if strings.HasPrefix(idx.Index, ".") {
+			continue // system index
+		}
+		var primaries int
+		var size int64
+		if _, err := fmt.Sscan(idx.Primaries, &primaries); err != nil || primaries == 0 {
+			continue
+		}
+		if _, err := fmt.Sscan(idx.PriStoreBytes, &size); err != nil {
+			continue
+		}
+
+		avg := size / int64(primaries)
+		if avg <= cfg.MaxShardSizeBytes {
+			continue
+		}
+
+		advice := ShardAdvice{
+			Index:             idx.Index,
+			PrimaryShards:     primaries,
+			PrimarySizeBytes:  size,
+			AvgShardSizeBytes: avg,
+		}
+		if alias, ok := writeAliases[idx.Index]; ok {
+			advice.Action = ShardActionRollover
+			advice.Alias = alias
+		} else {
+			// Split factor must be power of two to fit default number_of_routing_shards
+			target := primaries
+			for size/int64(target) > cfg.MaxShardSizeBytes {
+				target *= 2
+			}
+			advice.Action = ShardActionSplit
+			advice.TargetShards = target
+		}
+		advices = append(advices, advice)
+	}
+
+	return advices, nil
+}
+
+// executeShardAdvice performs recommended rollover or split.
+func (c *Client) executeShardAdvice(ctx context.Context, advice *ShardAdvice, cfg ShardAdvisorConfig) error {
+	switch advice.Action {
+	case ShardActionRollover:
+		if _, err := c.Rollover(ctx, &RolloverRequest{Alias: advice.Alias}); err != nil {
+			return err
+		}
+
+	case ShardActionSplit:
+		target := cfg.SplitTargetName(advice.Index, advice.TargetShards)
+		if err := c.splitIndex(ctx, advice.Index, target, advice.TargetShards); err != nil {
+			return err
+		}
+		advice.Target = target
+
+	default:
+		return errors.Errorf("unknown shard action %q", advice.Action)
+	}
+
+	return nil
+}
+
+// splitIndex splits index into target and moves aliases of index to target atomically.
+// Source is write-blocked only while split runs: the block is removed afterwards, and on failure,
+// so index is never left read-only. Clients addressing source by name keep using it until
+// their routing is moved to target.
+func (c *Client) splitIndex(ctx context.Context, index, target string, shards int) (err error) {
+	meta, err := c.GetIndex(ctx, index)
+	if err != nil {
+		return err
+	}
+
+	// Source index must be write-blocked before split
+	if err := c.PutSettings(ctx, index, map[string]any{"index.blocks.write": true}); err != nil {
+		return err
+	}
+	defer func() {
+		if unblockErr := c.PutSettings(ctx, index, map[string]any{"index.blocks.write": nil}); unblockErr != nil {
+			if err == nil {
+				err = errors.Wrapf(unblockErr, "failed to remove write block of index %q", index)
+			} else {
+				err = &MultiError{Errors: []error{err, errors.Wrapf(unblockErr, "failed to remove write block of index %q", index)}}
+			}
+		}
+	}()
+
+	_, err = c.Split(ctx, &ResizeRequest{
+		Index:  index,
+		Target: target,
+		Settings: map[string]any{
+			"index.number_of_shards": shards,
+			"index.blocks.write":     nil,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	aliases := make([]string, 0, len(meta.Aliases))
+	for alias := range meta.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	actions := make([]AliasAction, 0, 2*len(aliases))
+	for _, alias := range aliases {
+		actions = append(actions,
+			AliasAction{Action: AliasActionRemove, Index: index, Alias: alias},
+			AliasAction{Action: AliasActionAdd, Index: target, Alias: alias, Props: meta.Aliases[alias]},
+		)
+	}
+	if err := c.UpdateAliases(ctx, actions); err != nil {
+		return errors.Wrapf(err, "failed to move aliases of index %q to %q", index, target)
+	}
+	return nil
+}
+
+// writeAliasesByIndex returns index -> alias mapping for indices that are write targets of an alias.
+func writeAliasesByIndex(aliases []AliasInfo) map[string]string {
+	count := make(map[string]int)
+	for _, a := range aliases {
+		count[a.Alias]++
+	}
+
+	result := make(map[string]string)
+	for _, a := range aliases {
+		if a.IsWriteIndex == "true" || (a.IsWriteIndex != "false" && count[a.Alias] == 1) {
+			result[a.Index] = a.Alias
+		}
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+package esclient
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// AggregationTable is tabular form of nested bucket aggregations.
+// Every row corresponds to a leaf bucket: one column per bucket aggregation level
+// (aggregation name -> bucket key), "doc_count" of leaf bucket and its metric values.
+// Bucket level columns come first in nesting order, followed by other columns sorted by name.
+type AggregationTable struct {
+	Columns []string         // Column names in order of first appearance
+	Rows    []map[string]any // Rows; missing columns are absent from map
+}
+
+// aggregation bucket fields that are not sub-aggregations
+var bucketMetaFields = map[string]bool{
+	"key": true, "key_as_string": true, "doc_count": true, "from": true, "from_as_string": true,
+	"to": true, "to_as_string": true, "doc_count_error_upper_bound": true, "bg_count": true, "score": true,
+}
+
+// FlattenAggregations flattens nested bucket aggregations (terms, date_histogram, histogram, range, ...)
+// into rows. Sibling bucket aggregations produce separate sets of rows.
+// Metric aggregations become columns: single-value metrics are named after aggregation,
+// multi-value metrics (stats, percentiles) as "<name>.<value>".
+func FlattenAggregations(aggs map[string]interface{}) *AggregationTable {
+	table := &AggregationTable{}
+	seen := make(map[string]bool)
+	table.flatten(aggs, map[string]any{}, seen)
+	return table
+}
+
+// FlattenAggregations flattens aggregations of search response. See FlattenAggregations.
+func (r *SearchResponse) FlattenAggregations() *AggregationTable {
+	return FlattenAggregations(r.Aggregations)
+}
+
+// flatten appends rows produced by aggregations at one level to table.
+func (t *AggregationTable) flatten(aggs map[string]interface{}, row map[string]any, seen map[string]bool) {
+	names := slices.Sorted(maps.Keys(aggs))
+
+	var bucketAggs []string
+	for _, name := range names {
+		agg, ok := aggs[name].(map[string]interface{})
+		if !ok || bucketMetaFields[name] {
+			continue
+		}
+		if _, isBucket := agg["buckets"]; isBucket {
+			bucketAggs = append(bucketAggs, name)
+			continue
+		}
+		addMetric(row, name, agg)
+	}
+
+	if len(bucketAggs) == 0 {
+		t.addRow(row, seen)
+		return
+	}
+
+	for _, name := range bucketAggs {
+		// Bucket levels go first, in nesting order
+		if !seen[name] {
+			seen[name] = true
+			t.Columns = append(t.Columns, name)
+		}
+		for _, bucket := range aggBuckets(aggs[name].(map[string]interface{})["buckets"]) {
+			bucketRow := maps.Clone(row)
+			bucketRow[name] = bucketKey(bucket)
+			if docCount, ok := bucket["doc_count"]; ok {
+				bucketRow["doc_count"] = docCount
+			}
+			t.flatten(bucket, bucketRow, seen)
+		}
+	}
+}
+
+// addMetric adds metric aggregation values to row.
+func addMetric(row map[string]any, name string, agg map[string]interface{}) {
+	if val, ok := agg["value"]; ok {
+		row[name] = val
+		return
+	}
+
+	values := agg
+	if nested, ok := agg["values"].(map[string]interface{}); ok {
+		values = nested // percentiles
+	}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		switch values[key].(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		}
+		row[name+"."+key] = values[key]
+	}
+}
+
+// addRow appends row and registers its new columns.
+func (t *AggregationTable) addRow(row map[string]any, seen map[string]bool) {
+	if len(row) == 0 {
+		return
+	}
+
+	// Deterministic order for doc_count and metric columns first seen in this row
+	var added []string
+	for col := range row {
+		if !seen[col] {
+			seen[col] = true
+			added = append(added, col)
+		}
+	}
+	sort.Strings(added)
+	t.Columns = append(t.Columns, added...)
+	t.Rows = append(t.Rows, row)
+}
+
+// aggBuckets returns buckets of bucket aggregation, which are array or keyed object.
+func aggBuckets(raw interface{}) []map[string]interface{} {
+	var buckets []map[string]interface{}
+	switch b := raw.(type) {
+	case []interface{}:
+		for _, item := range b {
+			if bucket, ok := item.(map[string]interface{}); ok {
+				buckets = append(buckets, bucket)
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range slices.Sorted(maps.Keys(b)) {
+			if bucket, ok := b[key].(map[string]interface{}); ok {
+				bucket = maps.Clone(bucket)
+				if _, hasKey := bucket["key"]; !hasKey {
+					bucket["key"] = key
+				}
+				buckets = append(buckets, bucket)
+			}
+		}
+	}
+	return buckets
+}
+
+// bucketKey returns human-readable bucket key, preferring key_as_string (e.g., formatted dates).
+func bucketKey(bucket map[string]interface{}) any {
+	if s, ok := bucket["key_as_string"].(string); ok {
+		return s
+	}
+	return bucket["key"]
+}
+
+// WriteCSV writes table as CSV with header row.
+func (t *AggregationTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return errors.Wrap(err, "failed to write CSV header")
+	}
+
+	record := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, col := range t.Columns {
+			record[i] = ""
+			if val, ok := row[col]; ok && val != nil {
+				record[i] = fmt.Sprint(val)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return errors.Wrap(err, "failed to write CSV row")
+		}
+	}
+
+	cw.Flush()
+	return errors.Wrap(cw.Error(), "failed to flush CSV")
+}
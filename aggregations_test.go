@@ -0,0 +1,55 @@
+package esclient
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenAggregations(t *testing.T) {
+	var resp SearchResponse
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"aggregations": {
+			"by_store": {
+				"buckets": [
+					{"key": "s1", "doc_count": 3, "by_day": {"buckets": [
+						{"key": 1704067200000, "key_as_string": "2024-01-01", "doc_count": 2, "revenue": {"value": 20}},
+						{"key": 1704153600000, "key_as_string": "2024-01-02", "doc_count": 1, "revenue": {"value": 5}}
+					]}},
+					{"key": "s2", "doc_count": 1, "by_day": {"buckets": [
+						{"key": 1704067200000, "key_as_string": "2024-01-01", "doc_count": 1, "revenue": {"value": 7}}
+					]}}
+				]
+			}
+		}
+	}`), &resp))
+
+	table := resp.FlattenAggregations()
+	assert.Equal(t, []string{"by_store", "by_day", "doc_count", "revenue"}, table.Columns)
+	require.Len(t, table.Rows, 3)
+	assert.Equal(t, map[string]any{"by_store": "s1", "by_day": "2024-01-01", "doc_count": float64(2), "revenue": float64(20)}, table.Rows[0])
+	assert.Equal(t, map[string]any{"by_store": "s2", "by_day": "2024-01-01", "doc_count": float64(1), "revenue": float64(7)}, table.Rows[2])
+
+	var buf strings.Builder
+	require.NoError(t, table.WriteCSV(&buf))
+	assert.Equal(t, "by_store,by_day,doc_count,revenue\ns1,2024-01-01,2,20\ns1,2024-01-02,1,5\ns2,2024-01-01,1,7\n", buf.String())
+}
+
+func TestFlattenAggregations_MultiValueMetrics(t *testing.T) {
+	table := FlattenAggregations(map[string]interface{}{
+		"price_stats": map[string]interface{}{"count": 2.0, "min": 1.0, "max": 3.0},
+		"latency":     map[string]interface{}{"values": map[string]interface{}{"50.0": 10.0, "99.0": 40.0}},
+	})
+
+	require.Len(t, table.Rows, 1)
+	assert.Equal(t, map[string]any{
+		"latency.50.0":      10.0,
+		"latency.99.0":      40.0,
+		"price_stats.count": 2.0,
+		"price_stats.max":   3.0,
+		"price_stats.min":   1.0,
+	}, table.Rows[0])
+}
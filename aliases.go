@@ -0,0 +1,218 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AliasInfo represents a single alias-to-index binding from _cat/aliases.
+type AliasInfo struct {
+	Alias         string `json:"alias"`
+	Index         string `json:"index"`
+	Filter        string `json:"filter"`
+	RoutingIndex  string `json:"routing.index"`
+	RoutingSearch string `json:"routing.search"`
+	IsWriteIndex  string `json:"is_write_index"` // "true", "false" or "-" when not set
+}
+
+// ListAliases returns all aliases of the cluster.
+func (c *Client) ListAliases(ctx context.Context) ([]AliasInfo, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+
+	u := newURL(c.baseURL, "/_cat/aliases", query)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cat aliases request")
+	}
+
+	var aliases []AliasInfo
+	status, errBody, err := c.doJSON(ctx, httpReq, &aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "cat_aliases", StatusCode: status, body: errBody}
+	}
+
+	return aliases, nil
+}
+
+// AliasCache provides cached alias -> indices view of the cluster.
+// Cache is reloaded lazily when TTL expires or explicitly via Refresh.
+type AliasCache struct {
+	client *Client
+	ttl    time.Duration
+
+	mu       sync.RWMutex
+	indices  map[string][]string // alias -> indices
+	write    map[string]string   // alias -> write index
+	loadedAt time.Time
+}
+
+// NewAliasCache creates alias cache on top of client.
+// If ttl is zero, default of 1 minute is used.
+func NewAliasCache(client *Client, ttl time.Duration) *AliasCache {
+	if ttl == 0 {
+		ttl = time.Minute
+	}
+	return &AliasCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Refresh reloads aliases from the cluster.
+func (ac *AliasCache) Refresh(ctx context.Context) error {
+	aliases, err := ac.client.ListAliases(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list aliases")
+	}
+
+	indices := make(map[string][]string)
+	write := make(map[string]string)
+	for _, a := range aliases {
+		indices[a.Alias] = append(indices[a.Alias], a.Index)
+		if a.IsWriteIndex == "true" {
+			write[a.Alias] = a.Index
+		}
+	}
+
+	// Alias pointing to a single index is implicitly a write alias
+	for alias, idx := range indices {
+		if _, ok := write[alias]; !ok && len(idx) == 1 {
+			write[alias] = idx[0]
+		}
+	}
+
+	ac.mu.Lock()
+	ac.indices = indices
+	ac.write = write
+	ac.loadedAt = ac.client.clock.Now()
+	ac.mu.Unlock()
+
+	return nil
+}
+
+// Indices returns indices the alias points to.
+// Returns nil if alias does not exist.
+func (ac *AliasCache) Indices(ctx context.Context, alias string) ([]string, error) {
+	if err := ac.ensureFresh(ctx); err != nil {
+		return nil, err
+	}
+
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	idx := ac.indices[alias]
+	result := make([]string, len(idx))
+	copy(result, idx)
+	return result, nil
+}
+
+// WriteIndex returns current write index of the alias.
+// Returns empty string if alias has no write index.
+func (ac *AliasCache) WriteIndex(ctx context.Context, alias string) (string, error) {
+	if err := ac.ensureFresh(ctx); err != nil {
+		return "", err
+	}
+
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	return ac.write[alias], nil
+}
+
+// Snapshot returns copy of cached alias -> indices mapping.
+func (ac *AliasCache) Snapshot(ctx context.Context) (map[string][]string, error) {
+	if err := ac.ensureFresh(ctx); err != nil {
+		return nil, err
+	}
+
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	result := make(map[string][]string, len(ac.indices))
+	for alias, idx := range ac.indices {
+		result[alias] = append([]string(nil), idx...)
+	}
+	return result, nil
+}
+
+// ensureFresh reloads cache if it was never loaded or TTL expired.
+func (ac *AliasCache) ensureFresh(ctx context.Context) error {
+	ac.mu.RLock()
+	fresh := ac.indices != nil && ac.client.clock.Now().Sub(ac.loadedAt) < ac.ttl
+	ac.mu.RUnlock()
+
+	if fresh {
+		return nil
+	}
+	return ac.Refresh(ctx)
+}
+
+// Alias actions.
+const (
+	AliasActionAdd    = "add"
+	AliasActionRemove = "remove"
+)
+
+// AliasAction represents single action of atomic alias update.
+type AliasAction struct {
+	Action string     // AliasActionAdd or AliasActionRemove
+	Index  string     // Index name
+	Alias  string     // Alias name
+	Props  IndexAlias // Alias properties, used by add action
+}
+
+// UpdateAliases applies alias actions atomically.
+func (c *Client) UpdateAliases(ctx context.Context, actions []AliasAction) error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	items := make([]map[string]any, 0, len(actions))
+	for _, a := range actions {
+		if a.Index == "" || a.Alias == "" {
+			return errors.New("index and alias names are required")
+		}
+
+		params := map[string]any{"index": a.Index, "alias": a.Alias}
+		switch a.Action {
+		case AliasActionAdd:
+			if len(a.Props.Filter) > 0 {
+				params["filter"] = a.Props.Filter
+			}
+			if a.Props.IndexRouting != "" {
+				params["index_routing"] = a.Props.IndexRouting
+			}
+			if a.Props.SearchRouting != "" {
+				params["search_routing"] = a.Props.SearchRouting
+			}
+			if a.Props.IsWriteIndex != nil {
+				params["is_write_index"] = *a.Props.IsWriteIndex
+			}
+		case AliasActionRemove:
+		default:
+			return errors.Errorf("unknown alias action %q", a.Action)
+		}
+		items = append(items, map[string]any{a.Action: params})
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, "/_aliases", nil, map[string]any{"actions": items}, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "update_aliases", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
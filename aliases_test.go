@@ -0,0 +1,59 @@
+package esclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasCache(t *testing.T) {
+	es := &fakeES{
+		response: `[
+			{"alias": "orders", "index": "orders_2024", "is_write_index": "false"},
+			{"alias": "orders", "index": "orders_2025", "is_write_index": "true"},
+			{"alias": "products", "index": "products_v2", "is_write_index": "-"}
+		]`,
+	}
+	cache := NewAliasCache(newTestClient(t, es), time.Hour)
+	ctx := context.Background()
+
+	indices, err := cache.Indices(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders_2024", "orders_2025"}, indices)
+
+	writeIndex, err := cache.WriteIndex(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "orders_2025", writeIndex)
+
+	writeIndex, err = cache.WriteIndex(ctx, "products")
+	require.NoError(t, err)
+	assert.Equal(t, "products_v2", writeIndex)
+
+	// Cached within TTL
+	assert.Len(t, es.requests, 1)
+	assert.Equal(t, "/_cat/aliases", es.requests[0].URL.Path)
+}
+
+func TestAliasCache_TTL(t *testing.T) {
+	es := &fakeES{response: `[{"alias": "orders", "index": "orders_2025", "is_write_index": "true"}]`}
+	clock := NewFakeClock(time.Now())
+	client, err := NewClient(es, "http://localhost:9200", WithClock(clock))
+	require.NoError(t, err)
+	cache := NewAliasCache(client, time.Minute)
+	ctx := context.Background()
+
+	_, err = cache.Indices(ctx, "orders")
+	require.NoError(t, err)
+	clock.Advance(59 * time.Second)
+	_, err = cache.Indices(ctx, "orders")
+	require.NoError(t, err)
+	assert.Len(t, es.requests, 1)
+
+	clock.Advance(time.Second)
+	_, err = cache.Indices(ctx, "orders")
+	require.NoError(t, err)
+	assert.Len(t, es.requests, 2)
+}
@@ -0,0 +1,105 @@
+package esclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BodyLimits configures size guard and compression of request bodies.
+type BodyLimits struct {
+	MaxSize       int  // Max request body size in bytes before compression, 0 disables guard
+	WarnOnly      bool // Log warning instead of rejecting bodies above MaxSize
+	GzipThreshold int  // Gzip bodies of at least this size in bytes, 0 disables compression
+}
+
+// WithBodyLimits sets size guard and compression threshold of request bodies.
+// Large generated queries (e.g., terms filter with thousands of IDs) can exceed
+// http.max_content_length of cluster or proxies in between.
+func WithBodyLimits(limits BodyLimits) ClientOption {
+	return func(c *Client) {
+		c.bodyLimits = limits
+	}
+}
+
+// defaultGzipThreshold is compression threshold of WithCompression when not specified.
+const defaultGzipThreshold = 16 << 10
+
+// WithCompression gzips request bodies of at least threshold bytes (default: 16KB) and asks
+// for gzip-compressed responses, trading CPU for bandwidth on multi-MB bulk and search payloads.
+func WithCompression(threshold int) ClientOption {
+	return func(c *Client) {
+		if threshold <= 0 {
+			threshold = defaultGzipThreshold
+		}
+		c.bodyLimits.GzipThreshold = threshold
+		c.acceptGzip = true
+	}
+}
+
+// gunzipResponse replaces gzip-encoded response body with decompressed one.
+// Transport decompresses transparently only if it set Accept-Encoding itself.
+func gunzipResponse(res *http.Response) error {
+	if !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read gzip response body")
+	}
+	res.Body = &gzipBody{Reader: zr, body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.ContentLength = -1
+	return nil
+}
+
+// gzipBody closes both gzip reader and underlying response body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	_ = b.Reader.Close()
+	return b.body.Close()
+}
+
+// limitBody checks request body size against limits and compresses it above threshold.
+// Returns body to send, which is compressed if Content-Encoding is set on request.
+func (c *Client) limitBody(ctx context.Context, req *http.Request, body []byte) ([]byte, error) {
+	limits := c.bodyLimits
+	if limits.MaxSize > 0 && len(body) > limits.MaxSize {
+		fields := map[string]interface{}{
+			"method":   req.Method,
+			"path":     req.URL.Path,
+			"size":     len(body),
+			"max_size": limits.MaxSize,
+		}
+		if !limits.WarnOnly {
+			return nil, errors.Wrapf(ErrBodyTooLarge, "%s %s body is %d bytes, limit %d", req.Method, req.URL.Path, len(body), limits.MaxSize)
+		}
+		logWarn(ctx, c.log, "elasticsearch request body above size limit", fields)
+	}
+
+	if limits.GzipThreshold <= 0 || len(body) < limits.GzipThreshold {
+		return body, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, errors.Wrap(err, "failed to gzip request body")
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to gzip request body")
+	}
+	req.Header.Set("Content-Encoding", "gzip")
+	req.ContentLength = int64(buf.Len())
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,104 @@
+package esclient
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// BootstrapSpec describes cluster resources created by Client.Bootstrap.
+type BootstrapSpec struct {
+	ILMPolicies    map[string]*ILMPolicy     `json:"ilm_policies,omitempty"`    // Policy name -> policy
+	Pipelines      map[string]*Pipeline      `json:"pipelines,omitempty"`       // Pipeline id -> pipeline
+	IndexTemplates map[string]*IndexTemplate `json:"index_templates,omitempty"` // Template name -> composable index template
+	Indices        []BootstrapIndex          `json:"indices,omitempty"`         // Initial shared indices
+}
+
+// BootstrapIndex describes index created by Client.Bootstrap if it does not exist.
+type BootstrapIndex struct {
+	Name     string         `json:"name"`               // Index name
+	Template string         `json:"template,omitempty"` // Template name in client template registry, optional
+	Settings map[string]any `json:"settings,omitempty"` // Index settings, optional
+	Mappings map[string]any `json:"mappings,omitempty"` // Index mappings, optional
+	Aliases  map[string]any `json:"aliases,omitempty"`  // Index aliases, optional
+}
+
+// BootstrapResult reports what Client.Bootstrap applied.
+type BootstrapResult struct {
+	ILMPolicies     []string // Applied policies
+	Pipelines       []string // Applied pipelines
+	IndexTemplates  []string // Applied index templates
+	CreatedIndices  []string // Indices created by this run
+	ExistingIndices []string // Indices that already existed
+}
+
+// Bootstrap applies ILM policies, ingest pipelines, index templates and initial indices of spec, in that order,
+// so templates can reference policies and pipelines. Policies, pipelines and templates are overwritten,
+// indices are only created if missing, so repeated runs are idempotent.
+// Stops at first error, returning result of steps applied so far.
+func (c *Client) Bootstrap(ctx context.Context, spec *BootstrapSpec) (*BootstrapResult, error) {
+	if spec == nil {
+		return nil, errors.New("bootstrap spec is required")
+	}
+
+	result := &BootstrapResult{}
+
+	for _, name := range sortedKeys(spec.ILMPolicies) {
+		if err := c.PutILMPolicy(ctx, name, spec.ILMPolicies[name]); err != nil {
+			return result, errors.Wrapf(err, "put ILM policy %q", name)
+		}
+		result.ILMPolicies = append(result.ILMPolicies, name)
+	}
+
+	for _, id := range sortedKeys(spec.Pipelines) {
+		if err := c.PutPipeline(ctx, id, spec.Pipelines[id]); err != nil {
+			return result, errors.Wrapf(err, "put pipeline %q", id)
+		}
+		result.Pipelines = append(result.Pipelines, id)
+	}
+
+	for _, name := range sortedKeys(spec.IndexTemplates) {
+		if err := c.PutIndexTemplate(ctx, name, spec.IndexTemplates[name]); err != nil {
+			return result, errors.Wrapf(err, "put index template %q", name)
+		}
+		result.IndexTemplates = append(result.IndexTemplates, name)
+	}
+
+	for _, index := range spec.Indices {
+		created, err := c.EnsureIndex(ctx, &CreateIndexRequest{
+			Index:    index.Name,
+			Template: index.Template,
+			Settings: index.Settings,
+			Mappings: index.Mappings,
+			Aliases:  index.Aliases,
+		})
+		if err != nil {
+			return result, errors.Wrapf(err, "ensure index %q", index.Name)
+		}
+		if created {
+			result.CreatedIndices = append(result.CreatedIndices, index.Name)
+		} else {
+			result.ExistingIndices = append(result.ExistingIndices, index.Name)
+		}
+	}
+
+	c.log.DebugWithCtx(ctx, "cluster bootstrapped", map[string]interface{}{
+		"ilm_policies":    len(result.ILMPolicies),
+		"pipelines":       len(result.Pipelines),
+		"index_templates": len(result.IndexTemplates),
+		"created_indices": result.CreatedIndices,
+	})
+
+	return result, nil
+}
+
+// sortedKeys returns map keys in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Bootstrap(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{},                            // ILM policy
+		{},                            // pipeline
+		{},                            // index template
+		{status: http.StatusNotFound}, // orders does not exist
+		{},                            // create orders
+		{},                            // products exists
+	}}
+	client := newTestClient(t, es)
+
+	result, err := client.Bootstrap(context.Background(), &BootstrapSpec{
+		ILMPolicies: map[string]*ILMPolicy{
+			"orders_retention": {Phases: map[string]ILMPhase{"delete": {MinAge: "90d", Actions: map[string]any{"delete": map[string]any{}}}}},
+		},
+		Pipelines: map[string]*Pipeline{
+			"orders_enrich": {Processors: []map[string]any{{"set": map[string]any{"field": "indexed_at", "value": "{{_ingest.timestamp}}"}}}},
+		},
+		IndexTemplates: map[string]*IndexTemplate{
+			"orders": {
+				IndexPatterns: []string{"orders_*"},
+				Template:      &IndexBody{Settings: map[string]any{"index.default_pipeline": "orders_enrich"}},
+			},
+		},
+		Indices: []BootstrapIndex{{Name: "orders_shared"}, {Name: "products_shared"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"PUT /_ilm/policy/orders_retention",
+		"PUT /_ingest/pipeline/orders_enrich",
+		"PUT /_index_template/orders",
+		"HEAD /orders_shared",
+		"PUT /orders_shared",
+		"HEAD /products_shared",
+	}, es.paths)
+	assert.JSONEq(t, `{"index_patterns": ["orders_*"], "template": {"settings": {"index.default_pipeline": "orders_enrich"}}}`, es.bodies[2])
+	assert.Equal(t, []string{"orders_shared"}, result.CreatedIndices)
+	assert.Equal(t, []string{"products_shared"}, result.ExistingIndices)
+}
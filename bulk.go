@@ -0,0 +1,270 @@
+package esclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const defaultBulkChunkSize = 1000
+
+// DeleteByIDsOptions configures DeleteByIDs.
+type DeleteByIDsOptions struct {
+	ChunkSize int    // Max number of IDs per bulk request (default: 1000)
+	CompanyID string // Company of documents; deletes in shared index are routed by it
+	Routing   string // Routing value; defaults to CompanyID for shared indices
+}
+
+// DeleteByIDsResult contains per-ID results of DeleteByIDs.
+type DeleteByIDsResult struct {
+	Deleted  []string          // IDs successfully deleted
+	NotFound []string          // IDs not present in index
+	Failed   map[string]string // ID -> failure reason
+}
+
+// ConflictStrategy defines how UpsertMany handles documents that already exist.
+type ConflictStrategy string
+
+const (
+	ConflictReplace ConflictStrategy = "replace" // Overwrite existing document
+	ConflictMerge   ConflictStrategy = "merge"   // Partially update existing document (doc_as_upsert)
+	ConflictSkip    ConflictStrategy = "skip"    // Keep existing document untouched
+)
+
+// UpsertDocument represents a document written by UpsertMany.
+type UpsertDocument struct {
+	ID   string // Document ID
+	Body any    // Document source, marshalled to JSON
+}
+
+// UpsertManyResult contains per-ID results of UpsertMany.
+type UpsertManyResult struct {
+	Created []string          // IDs of newly created documents
+	Updated []string          // IDs of replaced or merged documents
+	Skipped []string          // IDs left untouched (existing with skip strategy, or merge noop)
+	Failed  map[string]string // ID -> failure reason
+
+	// Quarantined are IDs rejected with mapping errors and written to quarantine index
+	// (see WithQuarantine) -> failure reason.
+	Quarantined map[string]string
+}
+
+// DeleteByIDs deletes documents by IDs using chunked bulk requests.
+// Chunks rejected with 413 are split in half and retried; working size is remembered per cluster.
+// Per-ID failures are reported in result; error is returned only if a bulk request fails as a whole.
+func (c *Client) DeleteByIDs(ctx context.Context, index string, ids []string, opts *DeleteByIDsOptions) (*DeleteByIDsResult, error) {
+	if index == "" {
+		return nil, errors.New("index name is required")
+	}
+
+	chunkSize := defaultBulkChunkSize
+	var companyID, routing string
+	if opts != nil {
+		if opts.ChunkSize > 0 {
+			chunkSize = opts.ChunkSize
+		}
+		companyID, routing = opts.CompanyID, opts.Routing
+	}
+
+	result := &DeleteByIDsResult{
+		Failed: make(map[string]string),
+	}
+
+	err := bulkChunked(ctx, c, ids, chunkSize, func(start int, chunk []string) error {
+		body, err := bulkDeleteBody(chunk)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.Bulk(ctx, &BulkRequest{
+			Index:     index,
+			Body:      body,
+			CompanyID: companyID,
+			Routing:   routing,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "bulk delete failed for chunk starting at %d", start)
+		}
+
+		for _, item := range resp.Items {
+			collectDeleteResult(result, item)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// bulkDeleteBody builds NDJSON body with delete actions for IDs.
+func bulkDeleteBody(ids []string) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, id := range ids {
+		action := map[string]any{
+			"delete": map[string]any{"_id": id},
+		}
+		if err := enc.Encode(action); err != nil {
+			return nil, errors.Wrap(err, "failed to encode bulk delete action")
+		}
+	}
+	return &buf, nil
+}
+
+// collectDeleteResult classifies a single bulk delete item.
+func collectDeleteResult(result *DeleteByIDsResult, item map[string]interface{}) {
+	action, ok := item["delete"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	id, _ := action["_id"].(string)
+	if errVal, hasErr := action["error"]; hasErr && errVal != nil {
+		result.Failed[id] = bulkItemErrorReason(errVal)
+		return
+	}
+
+	if r, _ := action["result"].(string); r == "not_found" {
+		result.NotFound = append(result.NotFound, id)
+		return
+	}
+
+	result.Deleted = append(result.Deleted, id)
+}
+
+// bulkItemErrorReason returns human-readable reason of bulk item error.
+func bulkItemErrorReason(errVal interface{}) string {
+	if m, ok := errVal.(map[string]interface{}); ok {
+		errType, _ := m["type"].(string)
+		reason, _ := m["reason"].(string)
+		if errType != "" {
+			return fmt.Sprintf("%s: %s", errType, reason)
+		}
+		return reason
+	}
+	return fmt.Sprint(errVal)
+}
+
+// UpsertMany writes documents using chunked bulk requests with given conflict strategy.
+// Chunks rejected with 413 are split in half and retried; working size is remembered per cluster.
+// Per-ID failures are reported in result; error is returned only if a bulk request fails as a whole.
+// Writes to shared index are routed by companyID (empty for none), so company-routed reads find them.
+// With WithQuarantine, documents rejected with mapping errors are moved to quarantine index.
+func (c *Client) UpsertMany(ctx context.Context, index, companyID string, docs []UpsertDocument, strategy ConflictStrategy) (*UpsertManyResult, error) {
+	result, err := c.upsertMany(ctx, index, companyID, docs, strategy)
+	if err != nil || c.quarantinePrefix == "" {
+		return result, err
+	}
+	return result, c.quarantine(ctx, index, docs, result)
+}
+
+// upsertMany writes documents without quarantine.
+func (c *Client) upsertMany(ctx context.Context, index, companyID string, docs []UpsertDocument, strategy ConflictStrategy) (*UpsertManyResult, error) {
+	if index == "" {
+		return nil, errors.New("index name is required")
+	}
+	switch strategy {
+	case ConflictReplace, ConflictMerge, ConflictSkip:
+	default:
+		return nil, errors.Errorf("unknown conflict strategy %q", strategy)
+	}
+
+	result := &UpsertManyResult{
+		Failed: make(map[string]string),
+	}
+
+	err := bulkChunked(ctx, c, docs, defaultBulkChunkSize, func(start int, chunk []UpsertDocument) error {
+		body, err := bulkUpsertBody(chunk, strategy)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.Bulk(ctx, &BulkRequest{
+			Index:     index,
+			Body:      body,
+			CompanyID: companyID,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "bulk upsert failed for chunk starting at %d", start)
+		}
+
+		for _, item := range resp.Items {
+			collectUpsertResult(result, item)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// bulkUpsertBody builds NDJSON body with write actions matching the strategy.
+func bulkUpsertBody(docs []UpsertDocument, strategy ConflictStrategy) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return nil, errors.New("document ID is required")
+		}
+
+		meta := map[string]any{"_id": doc.ID}
+		var action, source map[string]any
+		switch strategy {
+		case ConflictReplace:
+			action = map[string]any{"index": meta}
+		case ConflictSkip:
+			action = map[string]any{"create": meta}
+		case ConflictMerge:
+			action = map[string]any{"update": meta}
+			source = map[string]any{"doc": doc.Body, "doc_as_upsert": true}
+		}
+
+		if err := enc.Encode(action); err != nil {
+			return nil, errors.Wrap(err, "failed to encode bulk action")
+		}
+
+		var err error
+		if source != nil {
+			err = enc.Encode(source)
+		} else {
+			err = enc.Encode(doc.Body)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode document %q", doc.ID)
+		}
+	}
+	return &buf, nil
+}
+
+// collectUpsertResult classifies a single bulk write item.
+func collectUpsertResult(result *UpsertManyResult, item map[string]interface{}) {
+	for op, val := range item {
+		action, ok := val.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		id, _ := action["_id"].(string)
+		status, _ := action["status"].(float64)
+		if errVal, hasErr := action["error"]; hasErr && errVal != nil {
+			// create conflicts mean document exists and must be skipped
+			if op == "create" && int(status) == http.StatusConflict {
+				result.Skipped = append(result.Skipped, id)
+			} else {
+				result.Failed[id] = bulkItemErrorReason(errVal)
+			}
+			continue
+		}
+
+		switch action["result"] {
+		case "created":
+			result.Created = append(result.Created, id)
+		case "noop":
+			result.Skipped = append(result.Skipped, id)
+		default:
+			result.Updated = append(result.Updated, id)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package esclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// bulkGrowAfter is number of chunks accepted at remembered size before it is doubled,
+// so chunk size recovers once cluster limit is raised or oversized documents are gone.
+const bulkGrowAfter = 10
+
+// bulkSizer remembers largest bulk chunk size accepted by cluster after
+// 413 Request Entity Too Large responses. Shared by all clients of cluster.
+type bulkSizer struct {
+	size     atomic.Int64 // 0 until first 413
+	accepted atomic.Int64 // chunks accepted at remembered size since it last changed
+}
+
+// chunkSize returns requested chunk size capped by remembered working size.
+func (s *bulkSizer) chunkSize(requested int) int {
+	if n := int(s.size.Load()); n > 0 && n < requested {
+		return n
+	}
+	return requested
+}
+
+// shrink remembers size as working chunk size if it is smaller than current one.
+func (s *bulkSizer) shrink(size int) {
+	for {
+		current := s.size.Load()
+		if current > 0 && current <= int64(size) {
+			return
+		}
+		if s.size.CompareAndSwap(current, int64(size)) {
+			s.accepted.Store(0)
+			return
+		}
+	}
+}
+
+// grow records chunk of sent items accepted by cluster. After bulkGrowAfter chunks of
+// remembered size, it is doubled up to requested maximum.
+func (s *bulkSizer) grow(sent, maximum int) {
+	current := s.size.Load()
+	if current == 0 || int64(sent) != current || current >= int64(maximum) {
+		return
+	}
+	if s.accepted.Add(1) < bulkGrowAfter {
+		return
+	}
+	if s.size.CompareAndSwap(current, min(current*2, int64(maximum))) {
+		s.accepted.Store(0)
+	}
+}
+
+// withBulkSizer sets bulk chunk size memory shared by clients of the same cluster.
+func withBulkSizer(sizer *bulkSizer) ClientOption {
+	return func(c *Client) {
+		c.bulkSizes = sizer
+	}
+}
+
+// isTooLarge reports whether request was rejected for body size, by cluster (413)
+// or by client body limit.
+func isTooLarge(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusRequestEntityTooLarge {
+		return true
+	}
+	return errors.Is(err, ErrBodyTooLarge)
+}
+
+// bulkChunked sends items in chunks of at most chunkSize. When a chunk is rejected as too large,
+// chunk size is halved, remembered for the cluster and the same items are sent again,
+// until a single-item chunk fails. Remembered size grows back as chunks are accepted.
+func bulkChunked[T any](ctx context.Context, c *Client, items []T, chunkSize int, send func(start int, chunk []T) error) error {
+	size := c.bulkSizes.chunkSize(chunkSize)
+	for start := 0; start < len(items); {
+		end := min(start+size, len(items))
+
+		err := send(start, items[start:end])
+		if err != nil && isTooLarge(err) && end-start > 1 {
+			size = (end - start) / 2
+			c.bulkSizes.shrink(size)
+			c.log.DebugWithCtx(ctx, "elasticsearch bulk chunk too large, halving", map[string]interface{}{
+				"chunk_size": size,
+			})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		c.bulkSizes.grow(end-start, chunkSize)
+		size = c.bulkSizes.chunkSize(chunkSize)
+		start = end
+	}
+	return nil
+}
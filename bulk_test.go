@@ -0,0 +1,287 @@
+package esclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkES answers bulk delete request with item of every action in its body.
+// Items have status of document ID from statuses, 200 if not listed.
+type bulkES struct {
+	statuses map[string]int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (b *bulkES) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	b.requests = append(b.requests, req)
+	b.bodies = append(b.bodies, string(body))
+
+	var items []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+		var action map[string]struct {
+			ID string `json:"_id"`
+		}
+		if err := json.Unmarshal([]byte(line), &action); err != nil {
+			return nil, err
+		}
+		for op, meta := range action {
+			status := http.StatusOK
+			if s, ok := b.statuses[meta.ID]; ok {
+				status = s
+			}
+			item := map[string]any{"_id": meta.ID, "status": status}
+			switch status {
+			case http.StatusOK:
+				item["result"] = "deleted"
+			case http.StatusNotFound:
+				item["result"] = "not_found"
+			case http.StatusTooManyRequests:
+				item["error"] = map[string]any{"type": "es_rejected_execution_exception", "reason": "queue full"}
+			}
+			items = append(items, map[string]any{op: item})
+		}
+	}
+
+	resp, _ := json.Marshal(map[string]any{"errors": len(b.statuses) > 0, "items": items})
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(resp)),
+	}, nil
+}
+
+func TestClient_DeleteByIDs(t *testing.T) {
+	es := &bulkES{statuses: map[string]int{"2": http.StatusNotFound, "3": http.StatusTooManyRequests}}
+	client := newTestClient(t, es)
+
+	result, err := client.DeleteByIDs(context.Background(), "orders", []string{"1", "2", "3"}, &DeleteByIDsOptions{ChunkSize: 2})
+	require.NoError(t, err)
+
+	// 3 IDs with chunk size 2 -> 2 bulk requests
+	require.Len(t, es.bodies, 2)
+	assert.Equal(t, 2, strings.Count(es.bodies[0], "\n"))
+	assert.Equal(t, 1, strings.Count(es.bodies[1], "\n"))
+	assert.Equal(t, "/orders/_bulk", es.requests[0].URL.Path)
+
+	// Every ID is reported exactly once
+	assert.Equal(t, []string{"1"}, result.Deleted)
+	assert.Equal(t, []string{"2"}, result.NotFound)
+	assert.Equal(t, map[string]string{"3": "es_rejected_execution_exception: queue full"}, result.Failed)
+}
+
+func TestClient_UpsertMany_Skip(t *testing.T) {
+	es := &fakeES{
+		response: `{
+			"errors": true,
+			"items": [
+				{"create": {"_id": "1", "status": 201, "result": "created"}},
+				{"create": {"_id": "2", "status": 409, "error": {"type": "version_conflict_engine_exception", "reason": "document already exists"}}}
+			]
+		}`,
+	}
+	client := newTestClient(t, es)
+
+	docs := []UpsertDocument{
+		{ID: "1", Body: map[string]any{"name": "a"}},
+		{ID: "2", Body: map[string]any{"name": "b"}},
+	}
+	result, err := client.UpsertMany(context.Background(), "products", "", docs, ConflictSkip)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(es.bodies[0]), "\n")
+	require.Len(t, lines, 4)
+	assert.JSONEq(t, `{"create": {"_id": "1"}}`, lines[0])
+	assert.JSONEq(t, `{"name": "a"}`, lines[1])
+
+	assert.Equal(t, []string{"1"}, result.Created)
+	assert.Equal(t, []string{"2"}, result.Skipped)
+	assert.Empty(t, result.Failed)
+}
+
+func TestClient_UpsertMany_Merge(t *testing.T) {
+	es := &fakeES{response: `{"items": [{"update": {"_id": "1", "status": 200, "result": "updated"}}]}`}
+	client := newTestClient(t, es)
+
+	docs := []UpsertDocument{{ID: "1", Body: map[string]any{"price": 10}}}
+	result, err := client.UpsertMany(context.Background(), "products", "", docs, ConflictMerge)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(es.bodies[0]), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"update": {"_id": "1"}}`, lines[0])
+	assert.JSONEq(t, `{"doc": {"price": 10}, "doc_as_upsert": true}`, lines[1])
+	assert.Equal(t, []string{"1"}, result.Updated)
+}
+
+func TestClient_Bulk_CompanyRouting(t *testing.T) {
+	const perCompany = "orders_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87"
+	es := &fakeES{response: `{"items": []}`}
+	client := newTestClient(t, es)
+	ctx := context.Background()
+
+	_, err := client.Bulk(ctx, &BulkRequest{Index: "orders_shared", Body: strings.NewReader("{}\n"), CompanyID: "c1"})
+	require.NoError(t, err)
+	_, err = client.UpsertMany(ctx, "orders_shared", "c1", []UpsertDocument{{ID: "1", Body: map[string]any{}}}, ConflictReplace)
+	require.NoError(t, err)
+	_, err = client.DeleteByIDs(ctx, "orders_shared", []string{"1"}, &DeleteByIDsOptions{CompanyID: "c1"})
+	require.NoError(t, err)
+	_, err = client.Bulk(ctx, &BulkRequest{Index: "orders_shared", Body: strings.NewReader("{}\n"), CompanyID: "c1", Routing: "r1"})
+	require.NoError(t, err)
+	// Per-company indices and bulks without default index are not routed by company
+	_, err = client.UpsertMany(ctx, perCompany, "c1", []UpsertDocument{{ID: "1", Body: map[string]any{}}}, ConflictReplace)
+	require.NoError(t, err)
+	_, err = client.Bulk(ctx, &BulkRequest{Body: strings.NewReader("{}\n"), CompanyID: "c1"})
+	require.NoError(t, err)
+
+	var routings []string
+	for _, req := range es.requests {
+		routings = append(routings, req.URL.Query().Get("routing"))
+	}
+	assert.Equal(t, []string{"c1", "c1", "c1", "r1", "", ""}, routings)
+}
+
+func TestClient_DeleteByIDs_TooLarge(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{status: http.StatusRequestEntityTooLarge},
+		{body: `{"items": [{"delete": {"_id": "1", "status": 200}}, {"delete": {"_id": "2", "status": 200}}]}`},
+		{body: `{"items": [{"delete": {"_id": "3", "status": 200}}, {"delete": {"_id": "4", "status": 200}}]}`},
+		{body: `{"items": [{"delete": {"_id": "5", "status": 200}}, {"delete": {"_id": "6", "status": 200}}]}`},
+	}}
+	registry := NewRegistry("main")
+	registry.byName["main"] = Entry{Name: "main", Version: 8, BaseURL: "http://localhost:9200", ES: es}
+	client, err := registry.GetTypedClient("main")
+	require.NoError(t, err)
+
+	result, err := client.DeleteByIDs(context.Background(), "orders", []string{"1", "2", "3", "4"}, &DeleteByIDsOptions{ChunkSize: 4})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3", "4"}, result.Deleted)
+	assert.Equal(t, 4, strings.Count(es.bodies[0], "\n"))
+	assert.Equal(t, 2, strings.Count(es.bodies[1], "\n"))
+
+	// Working size is remembered for other clients of the cluster
+	client, err = registry.GetTypedClient("main")
+	require.NoError(t, err)
+	_, err = client.DeleteByIDs(context.Background(), "orders", []string{"5", "6"}, &DeleteByIDsOptions{ChunkSize: 4})
+	require.NoError(t, err)
+	assert.Len(t, es.bodies, 4)
+	assert.Equal(t, 2, strings.Count(es.bodies[3], "\n"))
+}
+
+func TestClient_DeleteByIDs_SizeRecovers(t *testing.T) {
+	es := &bulkES{}
+	client := newTestClient(t, es)
+	client.bulkSizes.shrink(2)
+
+	ids := make([]string, 2*bulkGrowAfter+4*bulkGrowAfter+8)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	_, err := client.DeleteByIDs(context.Background(), "orders", ids, &DeleteByIDsOptions{ChunkSize: 8})
+	require.NoError(t, err)
+
+	sizes := make([]int, len(es.bodies))
+	for i, body := range es.bodies {
+		sizes[i] = strings.Count(body, "\n")
+	}
+	var want []int
+	for _, size := range []int{2, 4} {
+		for range bulkGrowAfter {
+			want = append(want, size)
+		}
+	}
+	assert.Equal(t, append(want, 8), sizes)
+	// Size grows up to requested chunk size only
+	assert.Equal(t, 8, client.bulkSizes.chunkSize(100))
+}
+
+func TestClient_UpsertMany_Quarantine(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"errors": true, "items": [
+			{"index": {"_id": "1", "status": 201, "result": "created"}},
+			{"index": {"_id": "2", "status": 400, "error": {"type": "document_parsing_exception", "reason": "failed to parse field [price]"}}},
+			{"index": {"_id": "3", "status": 429, "error": {"type": "es_rejected_execution_exception", "reason": "queue full"}}}
+		]}`},
+		{status: http.StatusNotFound}, // quarantine index does not exist
+		{},                            // quarantine index created
+		{body: `{"items": [{"index": {"_id": "2", "status": 201, "result": "created"}}]}`},
+	}}
+	client, err := NewClient(es, "http://localhost:9200", WithQuarantine(""))
+	require.NoError(t, err)
+
+	docs := []UpsertDocument{
+		{ID: "1", Body: map[string]any{"price": 10}},
+		{ID: "2", Body: map[string]any{"price": "ten"}},
+		{ID: "3", Body: map[string]any{"price": 30}},
+	}
+	result, err := client.UpsertMany(context.Background(), "products", "", docs, ConflictReplace)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"POST /products/_bulk",
+		"HEAD /quarantine_products",
+		"PUT /quarantine_products",
+		"POST /quarantine_products/_bulk",
+	}, es.paths)
+	assert.Contains(t, es.bodies[2], `"source":{"enabled":false,"type":"object"}`)
+
+	lines := strings.Split(strings.TrimSpace(es.bodies[3]), "\n")
+	require.Len(t, lines, 2)
+	var quarantined QuarantinedDocument
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &quarantined))
+	assert.Equal(t, "products", quarantined.Index)
+	assert.Equal(t, "2", quarantined.DocumentID)
+	assert.JSONEq(t, `{"price": "ten"}`, string(quarantined.Source))
+
+	assert.Equal(t, []string{"1"}, result.Created)
+	assert.Equal(t, map[string]string{"2": "document_parsing_exception: failed to parse field [price]"}, result.Quarantined)
+	assert.Equal(t, map[string]string{"3": "es_rejected_execution_exception: queue full"}, result.Failed)
+}
+
+func TestClient_WriteGroup_Rollback(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{status: http.StatusNotFound, body: `{"found": false}`},
+		{body: `{"_id": "i1", "found": true, "_seq_no": 3, "_primary_term": 1, "_source": {"company_id": "c1", "qty": 1}}`},
+		{status: http.StatusNotFound, body: `{"found": false}`},
+		{body: `{"errors": true, "items": [
+			{"index": {"_id": "o1", "status": 201, "_seq_no": 10, "_primary_term": 1}},
+			{"index": {"_id": "i1", "status": 200, "_seq_no": 4, "_primary_term": 1}},
+			{"index": {"_id": "i2", "status": 400, "error": {"type": "document_parsing_exception", "reason": "bad qty"}}}
+		]}`},
+		{body: `{"items": [{"index": {"_id": "i1", "status": 200}}, {"delete": {"_id": "o1", "status": 200}}]}`},
+	}}
+	client, err := NewClient(es, "http://localhost:9200")
+	require.NoError(t, err)
+
+	result, err := client.WriteGroup(context.Background(), &WriteGroupRequest{
+		CompanyID: "c1",
+		Rollback:  true,
+		Operations: []GroupOperation{
+			{Type: GroupIndex, Index: "orders", ID: "o1", Body: map[string]any{"total": 10}},
+			{Type: GroupIndex, Index: "order_items", ID: "i1", Body: map[string]any{"qty": 2}},
+			{Type: GroupIndex, Index: "order_items", ID: "i2", Body: map[string]any{"qty": "x"}},
+		},
+	})
+	require.ErrorIs(t, err, ErrGroupWriteFailed)
+	assert.Equal(t, "GET /orders/_doc/o1", es.paths[0])
+	assert.Equal(t, map[int]string{2: "document_parsing_exception: bad qty"}, result.Failed)
+	assert.Len(t, result.Succeeded, 2)
+	assert.True(t, result.RolledBack)
+
+	// Replaced item is restored and created order deleted, both only if unchanged since group write
+	lines := strings.Split(strings.TrimSpace(es.bodies[4]), "\n")
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"index": {"_index": "order_items", "_id": "i1", "routing": "c1", "if_seq_no": 4, "if_primary_term": 1}}`, lines[0])
+	assert.JSONEq(t, `{"company_id": "c1", "qty": 1}`, lines[1])
+	assert.JSONEq(t, `{"delete": {"_index": "orders", "_id": "o1", "routing": "c1", "if_seq_no": 10, "if_primary_term": 1}}`, lines[2])
+}
@@ -86,3 +86,26 @@ func NewESClientV9WithLogger(c *elasticV9.Client, baseURL *url.URL, log Logger)
 		log:     safeLogger(log),
 	}
 }
+
+// headerClient applies static headers to every request before delegating to ESClient.
+type headerClient struct {
+	es     ESClient
+	header http.Header
+}
+
+// Do sets static headers on cloned request and executes it.
+func (hc *headerClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	r := req.Clone(ctx)
+	for k, v := range hc.header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+	return hc.es.Do(ctx, r)
+}
+
+// withHeaders wraps ESClient to apply static headers; returns es as is if headers are empty.
+func withHeaders(es ESClient, header http.Header) ESClient {
+	if len(header) == 0 {
+		return es
+	}
+	return &headerClient{es: es, header: header.Clone()}
+}
@@ -0,0 +1,187 @@
+package esclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ClientOption customizes Client.
+type ClientOption func(*Client)
+
+// WithLogger sets client logger.
+func WithLogger(log Logger) ClientOption {
+	return func(c *Client) {
+		c.log = safeLogger(log)
+	}
+}
+
+// WithTimeout sets timeout of every request made by client; 0 disables it.
+// Shorter context deadline of a call still applies.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithHeaders adds static headers to every request made by client,
+// overriding previously set headers with the same name.
+func WithHeaders(headers http.Header) ClientOption {
+	return func(c *Client) {
+		merged := c.headers.Clone()
+		if merged == nil {
+			merged = make(http.Header)
+		}
+		for k, v := range headers {
+			merged[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+		}
+		c.headers = merged
+	}
+}
+
+// WithRefresh sets default refresh policy ("true", "false" or "wait_for") of writes
+// without explicit refresh (CreateDocument, Bulk).
+func WithRefresh(policy string) ClientOption {
+	return func(c *Client) {
+		c.refresh = policy
+	}
+}
+
+// WithRetry enables retries of requests failed with connection errors or 429/502/503/504 responses.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = &policy
+	}
+}
+
+// WithMetrics sets receiver of per-request measurements.
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithCrossTenantAccess allows client to run audited searches bypassing company filter on shared
+// indices (e.g., TopHitsPerCompany). Use it only for clients of admin and analytics services.
+func WithCrossTenantAccess() ClientOption {
+	return func(c *Client) {
+		c.crossTenant = true
+	}
+}
+
+// WithTracer sets tracer starting span for every request.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// WithCodec sets codec of request and response bodies (default: encoding/json).
+func WithCodec(codec Codec) ClientOption {
+	return func(c *Client) {
+		if codec == nil {
+			codec = jsonCodec{}
+		}
+		c.codec = codec
+	}
+}
+
+// WithErrorBodyLimit sets how many bytes of error response body are read and retained
+// on *StatusError (default 16 KB); rest of body, e.g. huge HTML page of proxy, is discarded.
+func WithErrorBodyLimit(limit int) ClientOption {
+	return func(c *Client) {
+		c.errorBodyLimit = limit
+	}
+}
+
+// With returns shallow clone of client with options applied.
+// Underlying connection is shared, so clones are cheap to create per request.
+func (c *Client) With(opts ...ClientOption) *Client {
+	clone := *c
+	for _, opt := range opts {
+		opt(&clone)
+	}
+	clone.build()
+	return &clone
+}
+
+// newClient creates client with parsed base URL and options applied.
+func newClient(es ESClient, baseURL *url.URL, opts ...ClientOption) *Client {
+	c := &Client{
+		base:      es,
+		baseURL:   baseURL,
+		log:       noopLogger{},
+		codec:     jsonCodec{},
+		clock:     systemClock{},
+		bulkSizes: &bulkSizer{},
+		stats:     &requestStats{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.build()
+	return c
+}
+
+// build wraps underlying ESClient with configured middleware.
+func (c *Client) build() {
+	c.es = c.withMiddleware(c.base)
+}
+
+// optionsLogger returns logger set by options, or no-op logger.
+func optionsLogger(opts []ClientOption) Logger {
+	c := &Client{log: noopLogger{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c.log
+}
+
+// optionsClock returns clock set by options, system clock if none.
+func optionsClock(opts []ClientOption) Clock {
+	c := &Client{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c.clock
+}
+
+// timeoutClient applies timeout to every request before delegating to ESClient.
+type timeoutClient struct {
+	es      ESClient
+	timeout time.Duration
+}
+
+// Do executes request with timeout. Timeout covers reading response body,
+// so context is cancelled when body is closed.
+func (tc *timeoutClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, tc.timeout)
+	resp, err := tc.es.Do(ctx, req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose cancels request context when response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// withTimeout wraps ESClient to apply timeout; returns es as is if timeout is not set.
+func withTimeout(es ESClient, timeout time.Duration) ESClient {
+	if timeout <= 0 {
+		return es
+	}
+	return &timeoutClient{es: es, timeout: timeout}
+}
@@ -0,0 +1,207 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Cluster health statuses.
+const (
+	ClusterStatusGreen  = "green"
+	ClusterStatusYellow = "yellow"
+	ClusterStatusRed    = "red"
+)
+
+// ClusterHealth represents cluster health response.
+type ClusterHealth struct {
+	ClusterName                 string  `json:"cluster_name"`
+	Status                      string  `json:"status"` // "green", "yellow" or "red"
+	TimedOut                    bool    `json:"timed_out"`
+	NumberOfNodes               int     `json:"number_of_nodes"`
+	NumberOfDataNodes           int     `json:"number_of_data_nodes"`
+	ActivePrimaryShards         int     `json:"active_primary_shards"`
+	ActiveShards                int     `json:"active_shards"`
+	RelocatingShards            int     `json:"relocating_shards"`
+	InitializingShards          int     `json:"initializing_shards"`
+	UnassignedShards            int     `json:"unassigned_shards"`
+	NumberOfPendingTasks        int     `json:"number_of_pending_tasks"`
+	ActiveShardsPercentAsNumber float64 `json:"active_shards_percent_as_number"`
+}
+
+// ClusterHealth returns cluster health.
+func (c *Client) ClusterHealth(ctx context.Context) (*ClusterHealth, error) {
+	return c.clusterHealth(ctx, nil)
+}
+
+// WaitForClusterStatus blocks until cluster reaches at least status ("yellow" or "green")
+// or timeout elapses. Waiting is done by Elasticsearch; on timeout error is returned
+// together with the last observed health.
+func (c *Client) WaitForClusterStatus(ctx context.Context, status string, timeout time.Duration) (*ClusterHealth, error) {
+	switch status {
+	case ClusterStatusGreen, ClusterStatusYellow, ClusterStatusRed:
+	default:
+		return nil, errors.Errorf("unknown cluster status %q", status)
+	}
+
+	query := url.Values{}
+	query.Set("wait_for_status", status)
+	if timeout > 0 {
+		query.Set("timeout", formatDuration(timeout))
+	}
+
+	health, err := c.clusterHealth(ctx, query)
+	if err != nil {
+		return health, err
+	}
+
+	if health.TimedOut {
+		return health, errors.Errorf("cluster %q did not reach %s status within %s (current: %s)",
+			health.ClusterName, status, timeout, health.Status)
+	}
+
+	return health, nil
+}
+
+// clusterHealth fetches cluster health with query parameters.
+func (c *Client) clusterHealth(ctx context.Context, query url.Values) (*ClusterHealth, error) {
+	var health ClusterHealth
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, "/_cluster/health", query, nil, &health)
+	if err != nil {
+		return nil, err
+	}
+
+	// Elasticsearch responds 408 when wait condition times out; fetch current health to report it
+	if status == http.StatusRequestTimeout && query != nil {
+		current, err := c.clusterHealth(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		current.TimedOut = true
+		return current, nil
+	}
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "cluster_health", StatusCode: status, body: errBody}
+	}
+
+	return &health, nil
+}
+
+// ClusterStats represents subset of cluster stats response.
+type ClusterStats struct {
+	ClusterName string `json:"cluster_name"`
+	Status      string `json:"status"`
+	Indices     struct {
+		Count  int `json:"count"`
+		Shards struct {
+			Total int `json:"total"`
+		} `json:"shards"`
+		Docs struct {
+			Count int64 `json:"count"`
+		} `json:"docs"`
+		Store struct {
+			SizeInBytes int64 `json:"size_in_bytes"`
+		} `json:"store"`
+	} `json:"indices"`
+	Nodes struct {
+		Count struct {
+			Total int `json:"total"`
+			Data  int `json:"data"`
+		} `json:"count"`
+		FS struct {
+			TotalInBytes     int64 `json:"total_in_bytes"`
+			FreeInBytes      int64 `json:"free_in_bytes"`
+			AvailableInBytes int64 `json:"available_in_bytes"`
+		} `json:"fs"`
+		JVM struct {
+			Mem struct {
+				HeapUsedInBytes int64 `json:"heap_used_in_bytes"`
+				HeapMaxInBytes  int64 `json:"heap_max_in_bytes"`
+			} `json:"mem"`
+		} `json:"jvm"`
+	} `json:"nodes"`
+}
+
+// NodeStats represents JVM, filesystem and thread pool statistics of a single node.
+type NodeStats struct {
+	Name  string   `json:"name"`
+	Host  string   `json:"host"`
+	Roles []string `json:"roles"`
+	JVM   struct {
+		Mem struct {
+			HeapUsedInBytes int64 `json:"heap_used_in_bytes"`
+			HeapMaxInBytes  int64 `json:"heap_max_in_bytes"`
+			HeapUsedPercent int   `json:"heap_used_percent"`
+		} `json:"mem"`
+	} `json:"jvm"`
+	FS struct {
+		Total struct {
+			TotalInBytes     int64 `json:"total_in_bytes"`
+			FreeInBytes      int64 `json:"free_in_bytes"`
+			AvailableInBytes int64 `json:"available_in_bytes"`
+		} `json:"total"`
+	} `json:"fs"`
+	ThreadPool map[string]ThreadPoolStats `json:"thread_pool"` // Pool name (e.g., "write", "search") -> stats
+}
+
+// ThreadPoolStats represents statistics of a node thread pool.
+type ThreadPoolStats struct {
+	Threads   int   `json:"threads"`
+	Queue     int   `json:"queue"`
+	Active    int   `json:"active"`
+	Rejected  int64 `json:"rejected"` // Cumulative since node start
+	Largest   int   `json:"largest"`
+	Completed int64 `json:"completed"`
+}
+
+// NodesStatsResponse represents nodes stats response.
+type NodesStatsResponse struct {
+	ClusterName string               `json:"cluster_name"`
+	Nodes       map[string]NodeStats `json:"nodes"` // Node ID -> stats
+}
+
+// DiskUsedPercent returns used disk space of node in percent.
+func (n *NodeStats) DiskUsedPercent() float64 {
+	if n.FS.Total.TotalInBytes == 0 {
+		return 0
+	}
+	return float64(n.FS.Total.TotalInBytes-n.FS.Total.AvailableInBytes) / float64(n.FS.Total.TotalInBytes) * 100
+}
+
+// Rejections returns total rejected tasks of write and search thread pools.
+func (n *NodeStats) Rejections() int64 {
+	return n.ThreadPool["write"].Rejected + n.ThreadPool["search"].Rejected
+}
+
+// ClusterStats returns cluster-wide indices and nodes statistics.
+func (c *Client) ClusterStats(ctx context.Context) (*ClusterStats, error) {
+	var stats ClusterStats
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, "/_cluster/stats", nil, nil, &stats)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "cluster_stats", StatusCode: status, body: errBody}
+	}
+
+	return &stats, nil
+}
+
+// NodesStats returns JVM heap, filesystem and thread pool statistics of every node.
+func (c *Client) NodesStats(ctx context.Context) (*NodesStatsResponse, error) {
+	var stats NodesStatsResponse
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, "/_nodes/stats/jvm,fs,thread_pool", nil, nil, &stats)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "nodes_stats", StatusCode: status, body: errBody}
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,154 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// defaultFallbackThreshold is number of consecutive failed reads of primary before reads fail over.
+const defaultFallbackThreshold = 3
+
+// fallbackProbeInterval is how often a read is sent to primary while reads are failed over.
+const fallbackProbeInterval = 10 * time.Second
+
+// servedByHeader is set on responses of clusters with fallback to name of cluster that served request.
+const servedByHeader = "X-Esclient-Cluster"
+
+// fallbackState counts consecutive read failures of cluster, shared by its typed clients.
+type fallbackState struct {
+	failures  atomic.Int64
+	lastProbe atomic.Int64 // unix nanoseconds of last failed read or probe of primary
+}
+
+// readFallback is fallback cluster of typed client.
+type readFallback struct {
+	primary   string // name of cluster of client
+	cluster   string // name of fallback cluster
+	es        ESClient
+	baseURL   *url.URL
+	threshold int
+	state     *fallbackState
+}
+
+// withFallback sets fallback cluster serving reads when cluster of client is unavailable.
+func withFallback(fallback *readFallback) ClientOption {
+	return func(c *Client) {
+		c.fallback = fallback
+	}
+}
+
+// fallbackClient sends reads to fallback cluster once primary failed threshold reads in a row
+// with connection errors or 5xx. While failed over, one read per probe interval still goes
+// to primary, and first successful one switches reads back.
+type fallbackClient struct {
+	es         ESClient
+	fallback   *readFallback
+	fallbackES ESClient // fallback cluster client with static headers applied
+	log        Logger
+	clock      Clock
+}
+
+// Do executes request on primary, or on fallback cluster if request is read and primary is failing.
+func (fc *fallbackClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	fb := fc.fallback
+	if !isFallbackRead(req) {
+		resp, err := fc.es.Do(ctx, req)
+		return servedBy(resp, fb.primary), err
+	}
+
+	if fb.state.failures.Load() < int64(fb.threshold) || fc.probeDue() {
+		resp, err := fc.es.Do(ctx, req)
+		if !primaryFailed(ctx, resp, err) {
+			fb.state.failures.Store(0)
+			return servedBy(resp, fb.primary), err
+		}
+		fb.state.lastProbe.Store(fc.clock.Now().UnixNano())
+		if fb.state.failures.Add(1) < int64(fb.threshold) || !rewind(req) {
+			return servedBy(resp, fb.primary), err
+		}
+		if resp != nil {
+			resp.Body.Close() //nolint:errcheck
+		}
+
+		fields := map[string]interface{}{
+			"cluster":          fb.primary,
+			"fallback_cluster": fb.cluster,
+			"method":           req.Method,
+			"path":             req.URL.Path,
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status_code"] = resp.StatusCode
+		}
+		logWarn(ctx, fc.log, "elasticsearch read failed over to fallback cluster", fields)
+	}
+
+	fallbackReq := req.Clone(ctx)
+	u := *req.URL
+	u.Scheme = fb.baseURL.Scheme
+	u.Host = fb.baseURL.Host
+	u.User = fb.baseURL.User
+	fallbackReq.URL = &u
+	fallbackReq.Host = ""
+
+	resp, err := fc.fallbackES.Do(ctx, fallbackReq)
+	return servedBy(resp, fb.cluster), err
+}
+
+// probeDue reports whether read should be sent to primary to check if it is back.
+func (fc *fallbackClient) probeDue() bool {
+	now := fc.clock.Now().UnixNano()
+	last := fc.fallback.state.lastProbe.Load()
+	if now-last < int64(fallbackProbeInterval) {
+		return false
+	}
+	return fc.fallback.state.lastProbe.CompareAndSwap(last, now)
+}
+
+// primaryFailed reports whether request failed because primary is unavailable: transport failure
+// or 5xx status. Cancellation of caller's context is not treated as failure.
+func primaryFailed(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// isFallbackRead reports whether request is search or document read that fallback cluster can serve.
+// Scrolls and point-in-time searches are bound to cluster that created them, so they are never
+// failed over. PIT searches can't name indices, so every request without index in path
+// (e.g., "/_search" with "pit" body) stays on primary.
+func isFallbackRead(req *http.Request) bool {
+	if strings.Contains(req.URL.Path, "/_search/scroll") || req.URL.Query().Has("scroll") {
+		return false
+	}
+	if strings.HasPrefix(strings.TrimPrefix(req.URL.Path, "/"), "_") {
+		return false
+	}
+	for _, segment := range strings.Split(req.URL.Path, "/") {
+		switch segment {
+		case "_search", "_msearch", "_count", "_mget":
+			return req.Method == http.MethodGet || req.Method == http.MethodPost
+		case "_doc", "_source":
+			return req.Method == http.MethodGet || req.Method == http.MethodHead
+		}
+	}
+	return false
+}
+
+// servedBy marks response with name of cluster that served it.
+func servedBy(resp *http.Response, cluster string) *http.Response {
+	if resp == nil {
+		return nil
+	}
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+	resp.Header.Set(servedByHeader, cluster)
+	return resp
+}
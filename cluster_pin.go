@@ -0,0 +1,34 @@
+package esclient
+
+import "context"
+
+// clusterPinKey is context key of pinned cluster name.
+type clusterPinKey struct{}
+
+// WithClusterPin returns context forcing every resolution made with it onto named cluster,
+// regardless of routing from cache, sync service or fallback policy. Index names are resolved
+// as usual. Resolver and repositories built on it honor the pin, so a whole request flow
+// (e.g., replay job) can be moved to a cluster without changing call sites.
+func WithClusterPin(ctx context.Context, clusterName string) context.Context {
+	return context.WithValue(ctx, clusterPinKey{}, clusterName)
+}
+
+// ClusterPin returns cluster name pinned by WithClusterPin.
+func ClusterPin(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(clusterPinKey{}).(string)
+	return name, ok && name != ""
+}
+
+// pinned returns copy of info with reads and writes moved to cluster.
+// Dual writes are dropped, as pinned flow writes to one cluster only.
+func (ci *ClusterInfo) pinned(clusterName string) *ClusterInfo {
+	result := *ci
+	result.ClusterName = clusterName
+	if ci.Read != nil {
+		read := *ci.Read
+		read.ClusterName = clusterName
+		result.Read = &read
+	}
+	result.DualWrites = nil
+	return &result
+}
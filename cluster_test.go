@@ -0,0 +1,64 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_WaitForClusterStatus(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{status: http.StatusRequestTimeout},
+		{body: `{"cluster_name": "gold", "status": "red", "unassigned_shards": 3}`},
+	}}
+	client := newTestClient(t, es)
+
+	health, err := client.WaitForClusterStatus(context.Background(), ClusterStatusYellow, 30*time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "current: red")
+	assert.True(t, health.TimedOut)
+	assert.Equal(t, 3, health.UnassignedShards)
+	assert.Equal(t, "GET /_cluster/health", es.paths[0])
+
+	_, err = client.WaitForClusterStatus(context.Background(), "blue", time.Second)
+	require.Error(t, err)
+}
+
+func TestClient_ClusterHealth(t *testing.T) {
+	es := &fakeES{response: `{"cluster_name": "gold", "status": "green", "number_of_nodes": 3}`}
+	client := newTestClient(t, es)
+
+	health, err := client.ClusterHealth(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, ClusterStatusGreen, health.Status)
+	assert.Equal(t, 3, health.NumberOfNodes)
+}
+
+func TestClient_NodesStats(t *testing.T) {
+	es := &fakeES{response: `{
+		"cluster_name": "gold",
+		"nodes": {
+			"n1": {
+				"name": "es-1",
+				"jvm": {"mem": {"heap_used_percent": 87}},
+				"fs": {"total": {"total_in_bytes": 1000, "available_in_bytes": 250}},
+				"thread_pool": {"write": {"rejected": 5, "queue": 200}, "search": {"rejected": 2}}
+			}
+		}
+	}`}
+	client := newTestClient(t, es)
+
+	stats, err := client.NodesStats(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "/_nodes/stats/jvm,fs,thread_pool", es.requests[0].URL.Path)
+	node := stats.Nodes["n1"]
+	assert.Equal(t, 87, node.JVM.Mem.HeapUsedPercent)
+	assert.Equal(t, 75.0, node.DiskUsedPercent())
+	assert.Equal(t, int64(7), node.Rejections())
+	assert.Equal(t, 200, node.ThreadPool["write"].Queue)
+}
@@ -0,0 +1,150 @@
+// Command esloadgen replays captured queries against one cluster of config at fixed rate
+// and reports latency percentiles, using the same typed client stack as services.
+//
+// Usage:
+//
+//	esloadgen -config clusters.json -queries queries.jsonl [-cluster name] [-rate 50] [-duration 1m] [-requests n] [-concurrency 16]
+//
+// Config file holds esclient.Config, queries file holds one esclient.LoadQuery per line.
+// Exits with status 1 if any request fails.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	esclient "github.com/billz-2/elasticsearch-cluster"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run generates load and returns exit status.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("esloadgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "", "path to clusters config JSON (esclient.Config)")
+	queriesPath := flags.String("queries", "", "path to captured queries, one esclient.LoadQuery JSON per line")
+	cluster := flags.String("cluster", "", "cluster under load (default: default cluster)")
+	rate := flags.Float64("rate", 50, "requests started per second")
+	duration := flags.Duration("duration", time.Minute, "how long requests are started")
+	requests := flags.Int("requests", 0, "max number of requests, 0 for no limit")
+	concurrency := flags.Int("concurrency", 16, "max requests in flight")
+	seed := flags.Uint64("seed", 0, "seed of query picking, 0 for random")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *configPath == "" || *queriesPath == "" {
+		fmt.Fprintln(stderr, "usage: esloadgen -config clusters.json -queries queries.jsonl [-cluster name] [-rate 50] [-duration 1m]")
+		return 2
+	}
+
+	var cfg esclient.Config
+	if err := readJSON(*configPath, &cfg); err != nil {
+		fmt.Fprintf(stderr, "esloadgen: %v\n", err)
+		return 2
+	}
+	queries, err := readQueries(*queriesPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "esloadgen: %v\n", err)
+		return 2
+	}
+
+	registry, err := esclient.NewRegistryFromConfig(&cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "esloadgen: %v\n", err)
+		return 1
+	}
+
+	report, err := registry.RunLoad(ctx, esclient.LoadConfig{
+		Cluster:     *cluster,
+		Queries:     queries,
+		Rate:        *rate,
+		Duration:    *duration,
+		Requests:    *requests,
+		Concurrency: *concurrency,
+		Seed:        *seed,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "esloadgen: %v\n", err)
+		return 1
+	}
+	printReport(stdout, report)
+
+	if report.Errors > 0 {
+		fmt.Fprintf(stderr, "esloadgen: %d requests failed, last error: %s\n", report.Errors, report.LastError)
+		return 1
+	}
+	return 0
+}
+
+// printReport prints totals and per-query latency table.
+func printReport(w io.Writer, report *esclient.LoadReport) {
+	fmt.Fprintf(w, "cluster=%s elapsed=%s requests=%d errors=%d missed=%d throughput=%.1f/s\n",
+		report.Cluster, report.Elapsed.Round(time.Millisecond), report.Requests, report.Errors, report.Missed, report.Throughput)
+
+	names := make([]string, 0, len(report.Queries))
+	for name := range report.Queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "QUERY\tREQUESTS\tERRORS\tMEAN\tP50\tP90\tP99\tMAX")
+	for _, name := range names {
+		q := report.Queries[name]
+		printRow(tw, name, q.Requests, q.Errors, q.Latency)
+	}
+	printRow(tw, "total", report.Requests, report.Errors, report.Latency)
+	tw.Flush() //nolint:errcheck
+}
+
+// printRow prints latency row of report table.
+func printRow(w io.Writer, name string, requests, failed int, l esclient.LatencyStats) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n", name, requests, failed,
+		l.Mean.Round(time.Microsecond), l.P50.Round(time.Microsecond), l.P90.Round(time.Microsecond),
+		l.P99.Round(time.Microsecond), l.Max.Round(time.Microsecond))
+}
+
+// readQueries reads captured queries file.
+func readQueries(path string) ([]esclient.LoadQuery, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	queries, err := esclient.ReadLoadQueries(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return queries, nil
+}
+
+// readJSON decodes JSON file into v, rejecting unknown fields to catch typos.
+func readJSON(path string, v any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
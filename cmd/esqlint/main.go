@@ -0,0 +1,80 @@
+// Command esqlint lints saved Elasticsearch query bodies for tenant isolation issues,
+// deprecated syntax and costly constructs.
+//
+// Usage:
+//
+//	esqlint [-target shared|per_company] [-strict] file.json...
+//
+// Exits with status 1 if any error is found (or any warning with -strict).
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	esclient "github.com/billz-2/elasticsearch-cluster"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run lints files and returns exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("esqlint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	target := flags.String("target", string(esclient.IndexTargetShared), "index target of queries: shared or per_company")
+	strict := flags.Bool("strict", false, "fail on warnings too")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	indexTarget := esclient.IndexTarget(*target)
+	if indexTarget != esclient.IndexTargetShared && indexTarget != esclient.IndexTargetPerCompany {
+		fmt.Fprintf(stderr, "esqlint: unknown target %q\n", *target)
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: esqlint [-target shared|per_company] [-strict] file.json...")
+		return 2
+	}
+
+	failed := false
+	for _, path := range flags.Args() {
+		issues, err := lintFile(path, indexTarget)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+			failed = true
+			continue
+		}
+		for _, issue := range issues {
+			fmt.Fprintf(stdout, "%s: %s\n", path, issue)
+			if issue.Severity == esclient.LintError || *strict {
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// lintFile reads query body from JSON file and lints it.
+func lintFile(path string, target esclient.IndexTarget) ([]esclient.LintIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("invalid query JSON: %w", err)
+	}
+
+	return esclient.LintQuery(body, target), nil
+}
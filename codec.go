@@ -0,0 +1,16 @@
+package esclient
+
+import "encoding/json"
+
+// Codec encodes request bodies and decodes response bodies of typed Client operations.
+// Can be replaced with faster JSON implementation (e.g., sonic, go-json) via WithCodec.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// jsonCodec is default Codec based on encoding/json.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
@@ -0,0 +1,213 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// redacted replaces secrets in DebugSnapshot.
+const redacted = "REDACTED"
+
+// DebugSources are components collected by DebugSnapshot. Only Registry is required.
+type DebugSources struct {
+	Registry    *Registry                       // Registry of clusters
+	Resolver    *Resolver                       // Resolver of company routing, optional
+	Failovers   map[string]*FailoverCoordinator // Failover coordinators by name, optional
+	SlowQueries *SlowQueryLog                   // Slow query log passed to WithMetrics, optional
+	Clock       Clock                           // Time source of snapshot time (default: system clock)
+}
+
+// DebugReport is content of DebugSnapshot.
+type DebugReport struct {
+	At        time.Time                 `json:"at"`
+	Clusters  map[string]DebugCluster   `json:"clusters"`
+	Resolver  *DebugResolver            `json:"resolver,omitempty"`
+	Failovers map[string]DebugFailover  `json:"failovers,omitempty"`
+	Slow      []SlowQuery               `json:"slow_queries,omitempty"`
+	Config    map[string]RedactedConfig `json:"config"`
+	Timeouts  OperationTimeouts         `json:"timeouts"`
+}
+
+// DebugCluster is health of registered cluster.
+type DebugCluster struct {
+	Version  int            `json:"version"`
+	Degraded string         `json:"degraded,omitempty"` // Client creation error of degraded cluster
+	Health   *ClusterHealth `json:"health,omitempty"`
+	Latency  time.Duration  `json:"latency,omitempty"` // Duration of health request
+	Error    string         `json:"error,omitempty"`   // Health request error
+}
+
+// DebugResolver is state of resolver.
+type DebugResolver struct {
+	Resolutions    map[ResolutionSource]int64 `json:"resolutions"`
+	FallbackCounts map[string]int64           `json:"fallback_counts,omitempty"`
+}
+
+// DebugFailover is state of failover coordinator.
+type DebugFailover struct {
+	Down         bool `json:"down"`
+	QueuedWrites int  `json:"queued_writes"`
+}
+
+// RedactedConfig is cluster config without credentials.
+type RedactedConfig struct {
+	Name            string              `json:"name"`
+	Version         int                 `json:"version"`
+	Addresses       []string            `json:"addresses"`
+	AddressWeights  []int               `json:"address_weights,omitempty"`
+	Username        string              `json:"username,omitempty"`
+	Password        string              `json:"password,omitempty"`
+	APIKey          string              `json:"api_key,omitempty"`
+	ServiceToken    string              `json:"service_token,omitempty"`
+	Headers         map[string][]string `json:"headers,omitempty"`
+	ProxyURL        string              `json:"proxy_url,omitempty"`
+	SigV4Region     string              `json:"sigv4_region,omitempty"` // Region of SigV4 signing; credentials are omitted
+	GzipThreshold   int                 `json:"gzip_threshold,omitempty"`
+	Timeouts        OperationTimeouts   `json:"timeouts"`
+	FallbackCluster string              `json:"fallback_cluster,omitempty"`
+}
+
+// DebugSnapshot collects registry health, resolver counters, failover states, recent slow queries
+// and redacted config into one JSON document for support tickets. Failure of a single cluster
+// is recorded in the report, so snapshot can be taken during incident.
+func DebugSnapshot(ctx context.Context, src DebugSources) ([]byte, error) {
+	if src.Registry == nil {
+		return nil, errors.New("registry is required")
+	}
+	clock := src.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	report := DebugReport{
+		At:       clock.Now(),
+		Clusters: make(map[string]DebugCluster),
+		Config:   make(map[string]RedactedConfig),
+		Timeouts: src.Registry.timeouts,
+	}
+
+	names := src.Registry.ListClusters()
+	sort.Strings(names)
+	for _, name := range names {
+		report.Clusters[name] = debugCluster(ctx, src.Registry, name, clock)
+		if cfg, ok := src.Registry.config(name); ok {
+			report.Config[name] = redactConfig(cfg)
+		}
+	}
+
+	if src.Resolver != nil {
+		report.Resolver = &DebugResolver{
+			Resolutions:    src.Resolver.ResolutionCounts(),
+			FallbackCounts: src.Resolver.FallbackCounts(),
+		}
+	}
+	if len(src.Failovers) > 0 {
+		report.Failovers = make(map[string]DebugFailover, len(src.Failovers))
+		for name, f := range src.Failovers {
+			report.Failovers[name] = DebugFailover{Down: f.Down(), QueuedWrites: f.QueuedWrites()}
+		}
+	}
+	if src.SlowQueries != nil {
+		report.Slow = src.SlowQueries.Recent()
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode debug snapshot")
+	}
+	return data, nil
+}
+
+// debugCluster requests health of registered cluster.
+func debugCluster(ctx context.Context, reg *Registry, name string, clock Clock) DebugCluster {
+	entry, _ := reg.entry(name)
+	result := DebugCluster{Version: entry.Version}
+
+	client, err := reg.GetTypedClient(name)
+	if IsDegraded(err) {
+		result.Degraded = err.Error()
+		return result
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	start := clock.Now()
+	health, err := client.ClusterHealth(ctx)
+	result.Latency = clock.Now().Sub(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Health = health
+	return result
+}
+
+// redactConfig returns cluster config with password, credential headers and URL passwords redacted.
+func redactConfig(cfg ClusterConfig) RedactedConfig {
+	result := RedactedConfig{
+		Name:            cfg.Name,
+		Version:         cfg.Version,
+		Username:        cfg.Username,
+		AddressWeights:  cfg.AddressWeights,
+		GzipThreshold:   cfg.GzipThreshold,
+		Timeouts:        cfg.Timeouts,
+		FallbackCluster: cfg.FallbackCluster,
+	}
+	if cfg.Password != "" {
+		result.Password = redacted
+	}
+	if cfg.APIKey != "" {
+		result.APIKey = redacted
+	}
+	if cfg.ServiceToken != "" {
+		result.ServiceToken = redacted
+	}
+	for _, address := range cfg.Addresses {
+		result.Addresses = append(result.Addresses, redactURL(address))
+	}
+	if cfg.SigV4 != nil {
+		result.SigV4Region = cfg.SigV4.Region
+	}
+	if cfg.ProxyURL != "" {
+		result.ProxyURL = redactURL(cfg.ProxyURL)
+	}
+	if len(cfg.Headers) > 0 {
+		result.Headers = make(map[string][]string, len(cfg.Headers))
+		for name, values := range cfg.Headers {
+			if sensitiveHeader(name) {
+				values = []string{redacted}
+			}
+			result.Headers[name] = append([]string(nil), values...)
+		}
+	}
+	return result
+}
+
+// redactURL hides password of URL user info.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	return u.Redacted()
+}
+
+// sensitiveHeader reports whether header may carry credentials.
+func sensitiveHeader(name string) bool {
+	name = strings.ToLower(http.CanonicalHeaderKey(name))
+	for _, marker := range []string{"authorization", "token", "key", "secret", "cookie"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	esclient "github.com/billz-2/elasticsearch-cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outageES simulates cluster outage by failing requests while down is set.
+type outageES struct {
+	esclient.ESClient
+	down atomic.Bool
+}
+
+func (o *outageES) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if o.down.Load() {
+		return nil, errors.New("connection refused (simulated outage)")
+	}
+	return o.ESClient.Do(ctx, req)
+}
+
+func TestFailover(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping E2E test in short mode")
+	}
+
+	indexName := "products_failover"
+
+	gold := newTestDeps(t, "tier-gold")
+	silver := newTestDeps(t, "tier-silver")
+	gold.createTestIndex(t, indexName)
+	silver.createTestIndex(t, indexName)
+
+	goldES, err := registry.GetClient("tier-gold")
+	require.NoError(t, err)
+	primaryES := &outageES{ESClient: goldES}
+	primary, err := esclient.NewClient(primaryES, esV9Addr)
+	require.NoError(t, err)
+
+	var failovers, replayed int
+	coordinator, err := esclient.NewFailoverCoordinator(esclient.FailoverConfig{
+		Primary:    primary,
+		Standby:    silver.Client,
+		OnFailover: func(ctx context.Context, err error) { failovers++ },
+		OnRecovery: func(ctx context.Context, n int) { replayed = n },
+	})
+	require.NoError(t, err)
+
+	writeDoc := func(id string) esclient.WriteFunc {
+		return func(ctx context.Context, c *esclient.Client) error {
+			_, err := c.CreateDocument(ctx, &esclient.CreateDocumentRequest{
+				Index:      indexName,
+				DocumentID: id,
+				Body:       bytes.NewReader([]byte(fmt.Sprintf(`{"title":%q,"company_id":"company-1"}`, id))),
+				Refresh:    "true",
+			})
+			return err
+		}
+	}
+
+	t.Run("primary_outage", func(t *testing.T) {
+		primaryES.down.Store(true)
+
+		_, err := coordinator.Search(ctx, &esclient.SearchRequest{Index: indexName, CompanyID: "company-1"})
+		require.NoError(t, err)
+		assert.True(t, coordinator.Down())
+		assert.Equal(t, 1, failovers)
+
+		for _, id := range []string{"doc-1", "doc-2"} {
+			queued, err := coordinator.Write(ctx, writeDoc(id))
+			require.NoError(t, err)
+			assert.True(t, queued)
+		}
+		assert.Equal(t, 2, coordinator.QueuedWrites())
+		require.Error(t, coordinator.Recover(ctx))
+	})
+
+	t.Run("primary_recovery", func(t *testing.T) {
+		primaryES.down.Store(false)
+
+		recoverCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		require.NoError(t, coordinator.Recover(recoverCtx))
+		assert.False(t, coordinator.Down())
+		assert.Equal(t, 2, replayed)
+
+		count, err := primary.Count(ctx, &esclient.CountRequest{Index: indexName, CompanyID: "company-1"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count.Count)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -72,6 +73,44 @@ func TestResolverCaching(t *testing.T) {
 		assert.Contains(t, err.Error(), "redis: nil")
 	})
 
+	t.Run("cache_expiration_clock", func(t *testing.T) {
+		var syncCalls atomic.Int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			syncCalls.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(esclient.ClusterInfo{ClusterName: "tier-gold", IndexName: "products_clock"})
+		}))
+		defer mockServer.Close()
+
+		clock := esclient.NewFakeClock(time.Now())
+		resolver, err := esclient.NewResolver(esclient.ResolverConfig{
+			Registry: registry,
+			Redis:    redisClient,
+			SyncURL:  mockServer.URL,
+			CacheTTL: time.Hour,
+			Clock:    clock,
+		})
+		require.NoError(t, err)
+
+		companyID := "company_clock"
+		_, _, err = resolver.Resolve(ctx, companyID, "products")
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return redisClient.Exists(ctx, "es_settings_company_clock_products").Val() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// Served from cache within TTL
+		_, _, err = resolver.Resolve(ctx, companyID, "products")
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), syncCalls.Load())
+
+		// Expired by clock, no real sleep
+		clock.Advance(time.Hour)
+		_, _, err = resolver.Resolve(ctx, companyID, "products")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), syncCalls.Load())
+	})
+
 	t.Run("invalidate_cache", func(t *testing.T) {
 		companyID := "company_456"
 		indexType := "products"
@@ -404,5 +443,7 @@ func TestResolverFallbackToDefault(t *testing.T) {
 		expectedIndexName := fmt.Sprintf("%s_%s", indexType, companyID)
 		assert.Equal(t, expectedIndexName, info.IndexName)
 		assert.NotEmpty(t, info.ClusterName) // Should have default cluster name
+		assert.Equal(t, esclient.ResolutionSourceFallback, info.Source)
+		assert.Equal(t, int64(1), resolver.FallbackCounts()[companyID])
 	})
 }
@@ -1,6 +1,11 @@
 package esclient
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 // Configuration errors
 var (
@@ -10,6 +15,37 @@ var (
 	ErrEmptyClusterName       = fmt.Errorf("cluster name is empty")
 )
 
+// Resolution errors
+var (
+	ErrNoRouting                = fmt.Errorf("no routing for index and fallback is disabled")
+	ErrUnsupportedSchemaVersion = fmt.Errorf("unsupported sync service settings schema version")
+)
+
+// Export errors
+var (
+	ErrCheckpointExpired = fmt.Errorf("export checkpoint PIT expired and export must restart")
+)
+
+// Pagination errors
+var (
+	ErrInvalidPageToken = fmt.Errorf("invalid page token")
+	ErrPageTokenExpired = fmt.Errorf("page token expired")
+)
+
+// Request errors
+var (
+	ErrBodyTooLarge          = fmt.Errorf("request body exceeds size limit")
+	ErrGroupWriteFailed      = fmt.Errorf("write group partially failed")
+	ErrUnsafeQuery           = fmt.Errorf("query is unsafe for tenant-scoped access")
+	ErrCrossTenantNotAllowed = fmt.Errorf("cross-tenant access is not enabled for client (see WithCrossTenantAccess)")
+	ErrDocumentNotOwned      = fmt.Errorf("document ID belongs to other company")
+)
+
+// Failover errors
+var (
+	ErrWriteQueueFull = fmt.Errorf("failover write queue is full")
+)
+
 // ErrEmptyClusterAddresses returns error for cluster with no addresses.
 func ErrEmptyClusterAddresses(clusterName string) error {
 	return fmt.Errorf("cluster %q has no addresses", clusterName)
@@ -20,6 +56,11 @@ func ErrInvalidESVersion(clusterName string, version int) error {
 	return fmt.Errorf("cluster %q has invalid ES version %d (must be 8 or 9)", clusterName, version)
 }
 
+// ErrVersionMismatch returns error for cluster reporting ES version other than configured.
+func ErrVersionMismatch(clusterName string, configured, detected int) error {
+	return fmt.Errorf("cluster %q is configured as ES version %d but reports version %d", clusterName, configured, detected)
+}
+
 // ErrClusterNotFound returns error when cluster is not found in registry.
 func ErrClusterNotFound(clusterName string) error {
 	return fmt.Errorf("cluster %q not found in registry", clusterName)
@@ -30,9 +71,98 @@ func ErrInvalidBaseURL(clusterName, address string) error {
 	return fmt.Errorf("cluster %q has invalid base URL %q (must be absolute URL)", clusterName, address)
 }
 
+// ErrInvalidProxyURL returns error for invalid cluster proxy URL caused by cause.
+// URL itself is left out, since it may carry proxy credentials.
+func ErrInvalidProxyURL(clusterName string, cause error) error {
+	return fmt.Errorf("cluster %q has invalid proxy URL (must be http, https, socks5 or socks5h URL): %w", clusterName, cause)
+}
+
+// ErrInvalidAddressWeights returns error for cluster whose address weights don't match addresses.
+func ErrInvalidAddressWeights(clusterName string) error {
+	return fmt.Errorf("cluster %q has invalid address weights (must be positive, one per address)", clusterName)
+}
+
+// ErrConflictingAuth returns error for cluster with more than one authentication method.
+func ErrConflictingAuth(clusterName string) error {
+	return fmt.Errorf("cluster %q has more than one of username/password, API key, service token and SigV4 set", clusterName)
+}
+
+// ErrRemoteReindexSigV4 returns error for remote reindex from cluster that requires SigV4 signing.
+func ErrRemoteReindexSigV4(clusterName string) error {
+	return fmt.Errorf("cluster %q uses SigV4, which remote reindex does not support", clusterName)
+}
+
+// ErrInvalidFallbackCluster returns error for cluster whose fallback cluster is itself or not configured.
+func ErrInvalidFallbackCluster(clusterName, fallback string) error {
+	return fmt.Errorf("cluster %q has invalid fallback cluster %q (must be other configured cluster)", clusterName, fallback)
+}
+
+// DegradedClusterError is returned when accessing a cluster whose client
+// could not be created at registry construction.
+type DegradedClusterError struct {
+	Cluster string
+	Err     error
+}
+
+func (e *DegradedClusterError) Error() string {
+	return fmt.Sprintf("cluster %q is degraded: %v", e.Cluster, e.Err)
+}
+
+// Unwrap returns the underlying client creation error.
+func (e *DegradedClusterError) Unwrap() error {
+	return e.Err
+}
+
+// IsDegraded reports whether err is caused by accessing a degraded cluster.
+func IsDegraded(err error) bool {
+	var degradedErr *DegradedClusterError
+	return errors.As(err, &degradedErr)
+}
+
+// MultiError aggregates multiple errors, e.g. every invalid cluster in config.
+// Supports errors.Is/errors.As against each aggregated error.
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns aggregated errors.
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// errOrNil returns nil if no errors were collected, otherwise *MultiError.
+func (e *MultiError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
 type StatusError struct {
 	Op         string
 	StatusCode int
+	body       *bodySnippet // start of response body, nil if not read
+}
+
+// Body returns start of error response body (at most the client error body limit), empty if not read.
+func (e *StatusError) Body() string {
+	if e.body == nil {
+		return ""
+	}
+	return string(e.body.data)
+}
+
+// BodyTruncated reports whether error response body was longer than retained by Body.
+func (e *StatusError) BodyTruncated() bool {
+	return e.body != nil && e.body.truncated
 }
 
 func (e *StatusError) Error() string {
@@ -41,3 +171,36 @@ func (e *StatusError) Error() string {
 	}
 	return fmt.Sprintf("%s returned status code %d", e.Op, e.StatusCode)
 }
+
+// IndexBlockError is returned when request is rejected by index or cluster block
+// (cluster_block_exception), e.g. read-only-allow-delete block set by flood-stage disk watermark.
+// Unwraps to *StatusError.
+type IndexBlockError struct {
+	StatusCode          int
+	Reason              string   // Reason reported by Elasticsearch
+	Indices             []string // Blocked indices mentioned in reason
+	Blocks              []string // Block descriptions (e.g., "TOO_MANY_REQUESTS/12/disk usage exceeded flood-stage watermark, ...")
+	ReadOnlyAllowDelete bool     // Block is read_only_allow_delete; see Client.RemoveReadOnlyBlock
+}
+
+func (e *IndexBlockError) Error() string {
+	return fmt.Sprintf("elasticsearch index block (status %d): %s", e.StatusCode, e.Reason)
+}
+
+// Unwrap returns status error of blocked request.
+func (e *IndexBlockError) Unwrap() error {
+	return &StatusError{StatusCode: e.StatusCode}
+}
+
+// IsIndexBlock reports whether err is caused by index or cluster block.
+func IsIndexBlock(err error) bool {
+	var blockErr *IndexBlockError
+	return errors.As(err, &blockErr)
+}
+
+// IsConflict reports whether err is a version conflict (HTTP 409) returned by Elasticsearch,
+// e.g. create with op_type=create on existing ID or failed if_seq_no check.
+func IsConflict(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusConflict
+}
@@ -0,0 +1,287 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultExportPageSize  = 1000
+	defaultExportKeepAlive = 5 * time.Minute
+)
+
+// ExportCheckpoint is persisted progress of export.
+type ExportCheckpoint struct {
+	PITID       string `json:"pit_id"`
+	SearchAfter []any  `json:"search_after,omitempty"` // Sort values of last exported hit
+	Exported    int64  `json:"exported"`               // Number of hits exported so far
+}
+
+// CheckpointStore persists export progress, so export can resume after crash.
+type CheckpointStore interface {
+	// Load returns checkpoint by key, or nil if there is none.
+	Load(ctx context.Context, key string) (*ExportCheckpoint, error)
+	Save(ctx context.Context, key string, cp *ExportCheckpoint) error
+	Delete(ctx context.Context, key string) error
+}
+
+// ExportRequest represents export of all documents matching query.
+type ExportRequest struct {
+	Index     string         // Index name or pattern
+	Query     map[string]any // Query body (JSON), optional
+	CompanyID string         // Company ID for per-company index
+	PageSize  int            // Hits per page (default: 1000)
+	KeepAlive time.Duration  // PIT keep alive between pages (default: 5m)
+
+	// Sort must end with unique tiebreaker field. Default is "_shard_doc", which is valid only
+	// within one PIT, so export can resume only while checkpointed PIT is alive.
+	// With custom sort export resumes on a new PIT if checkpointed one has expired.
+	Sort []SortClause
+
+	Store         CheckpointStore // Checkpoint store, optional
+	CheckpointKey string          // Checkpoint key, required with Store
+}
+
+// ExportIterator pages through all documents matching query using PIT and search_after.
+type ExportIterator struct {
+	client  *Client
+	req     ExportRequest
+	pitID   string
+	after   []any
+	count   int64
+	resumed bool
+	done    bool
+}
+
+// Export starts export or resumes it from checkpoint stored under req.CheckpointKey.
+func (c *Client) Export(ctx context.Context, req *ExportRequest) (*ExportIterator, error) {
+	if req.Index == "" {
+		return nil, errors.New("index name is required")
+	}
+	if req.Store != nil && req.CheckpointKey == "" {
+		return nil, errors.New("checkpoint key is required with checkpoint store")
+	}
+
+	it := &ExportIterator{
+		client: c,
+		req:    *req,
+	}
+	if it.req.PageSize <= 0 {
+		it.req.PageSize = defaultExportPageSize
+	}
+	if it.req.KeepAlive <= 0 {
+		it.req.KeepAlive = defaultExportKeepAlive
+	}
+	if len(it.req.Sort) == 0 {
+		it.req.Sort = []SortClause{{Field: "_shard_doc"}}
+	}
+
+	if req.Store != nil {
+		cp, err := req.Store.Load(ctx, req.CheckpointKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load export checkpoint")
+		}
+		if cp != nil {
+			it.pitID = cp.PITID
+			it.after = cp.SearchAfter
+			it.count = cp.Exported
+			it.resumed = true
+		}
+	}
+
+	if it.pitID == "" {
+		if err := it.openPIT(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return it, nil
+}
+
+// Exported returns number of hits exported so far, including ones before resume.
+func (it *ExportIterator) Exported() int64 {
+	return it.count
+}
+
+// Resumed reports whether export was resumed from checkpoint.
+func (it *ExportIterator) Resumed() bool {
+	return it.resumed
+}
+
+// Next returns next page of hits and saves checkpoint. Returns io.EOF when export is complete,
+// after which checkpoint is deleted and PIT is closed.
+// Checkpoint is saved before page is returned, so page being processed at crash time is not repeated;
+// callers needing at-least-once delivery should write page before calling Next again and use
+// idempotent writes.
+func (it *ExportIterator) Next(ctx context.Context) ([]map[string]interface{}, error) {
+	if it.done {
+		return nil, io.EOF
+	}
+
+	resp, err := it.search(ctx)
+	if isPITExpired(err) && it.resumed && !it.defaultSort() {
+		// Checkpointed PIT expired; custom sort values stay valid on a new PIT
+		if err := it.openPIT(ctx); err != nil {
+			return nil, err
+		}
+		resp, err = it.search(ctx)
+	}
+	if isPITExpired(err) {
+		return nil, errors.Wrapf(ErrCheckpointExpired, "PIT of export %q expired", it.req.CheckpointKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.PitID != "" {
+		it.pitID = resp.PitID
+	}
+
+	hits := resp.Hits.Hits
+	if len(hits) == 0 {
+		it.done = true
+		return nil, it.finish(ctx)
+	}
+
+	sortValues, ok := hits[len(hits)-1]["sort"].([]interface{})
+	if !ok {
+		return nil, errors.New("export hit has no sort values")
+	}
+	it.after = sortValues
+	it.count += int64(len(hits))
+
+	if it.req.Store != nil {
+		cp := &ExportCheckpoint{PITID: it.pitID, SearchAfter: it.after, Exported: it.count}
+		if err := it.req.Store.Save(ctx, it.req.CheckpointKey, cp); err != nil {
+			return nil, errors.Wrap(err, "failed to save export checkpoint")
+		}
+	}
+
+	return hits, nil
+}
+
+// Close closes PIT without deleting checkpoint, so export can be resumed later
+// while PIT is alive (or with custom sort).
+func (it *ExportIterator) Close(ctx context.Context) error {
+	if it.done || it.pitID == "" {
+		return nil
+	}
+	it.done = true
+	return it.client.ClosePIT(ctx, it.pitID)
+}
+
+// openPIT opens new PIT for export.
+func (it *ExportIterator) openPIT(ctx context.Context) error {
+	pit, err := it.client.OpenPIT(ctx, &OpenPITRequest{
+		Index:     it.req.Index,
+		KeepAlive: formatDuration(it.req.KeepAlive),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to open export PIT")
+	}
+	it.pitID = pit.ID
+	return nil
+}
+
+// search fetches next page after last sort values.
+func (it *ExportIterator) search(ctx context.Context) (*SearchResponse, error) {
+	pitID := it.pitID
+	req := &SearchRequest{
+		Index:        it.req.Index,
+		Query:        it.req.Query,
+		CompanyID:    it.req.CompanyID,
+		Size:         &it.req.PageSize,
+		Sort:         it.req.Sort,
+		PointInTime:  &pitID,
+		PITKeepAlive: it.req.KeepAlive,
+	}
+	if it.after != nil {
+		req.SearchAfter = it.after
+	}
+	return it.client.Search(ctx, req)
+}
+
+// finish deletes checkpoint and closes PIT of completed export.
+func (it *ExportIterator) finish(ctx context.Context) error {
+	if it.req.Store != nil {
+		if err := it.req.Store.Delete(ctx, it.req.CheckpointKey); err != nil {
+			return errors.Wrap(err, "failed to delete export checkpoint")
+		}
+	}
+	if err := it.client.ClosePIT(ctx, it.pitID); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// defaultSort reports whether export uses default PIT-bound "_shard_doc" sort.
+func (it *ExportIterator) defaultSort() bool {
+	return len(it.req.Sort) == 1 && it.req.Sort[0].Field == "_shard_doc"
+}
+
+// isPITExpired reports whether search failed because PIT no longer exists.
+func isPITExpired(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// RedisCheckpointStore stores export checkpoints in Redis.
+type RedisCheckpointStore struct {
+	redis *redis.Client
+	ttl   time.Duration
+}
+
+// NewRedisCheckpointStore creates Redis checkpoint store. Checkpoints expire after ttl (0 means no expiration).
+func NewRedisCheckpointStore(client *redis.Client, ttl time.Duration) *RedisCheckpointStore {
+	return &RedisCheckpointStore{redis: client, ttl: ttl}
+}
+
+// Load returns checkpoint by key, or nil if there is none.
+func (s *RedisCheckpointStore) Load(ctx context.Context, key string) (*ExportCheckpoint, error) {
+	val, err := s.redis.Get(ctx, s.key(key)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "redis get failed")
+	}
+
+	var cp ExportCheckpoint
+	if err := json.Unmarshal([]byte(val), &cp); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal checkpoint")
+	}
+	return &cp, nil
+}
+
+// Save stores checkpoint under key.
+func (s *RedisCheckpointStore) Save(ctx context.Context, key string, cp *ExportCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal checkpoint")
+	}
+
+	if err := s.redis.Set(ctx, s.key(key), data, s.ttl).Err(); err != nil {
+		return errors.Wrap(err, "redis set failed")
+	}
+	return nil
+}
+
+// Delete removes checkpoint by key.
+func (s *RedisCheckpointStore) Delete(ctx context.Context, key string) error {
+	if err := s.redis.Del(ctx, s.key(key)).Err(); err != nil {
+		return errors.Wrap(err, "redis del failed")
+	}
+	return nil
+}
+
+// key returns Redis key of checkpoint.
+func (s *RedisCheckpointStore) key(key string) string {
+	return fmt.Sprintf("es_export_checkpoint_%s", key)
+}
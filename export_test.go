@@ -0,0 +1,129 @@
+package esclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedES replies with responses in order, recording request bodies.
+type scriptedES struct {
+	responses []scriptedResponse
+	bodies    []string
+	paths     []string
+	queries   []string
+}
+
+type scriptedResponse struct {
+	status int
+	body   string
+}
+
+func (s *scriptedES) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	s.bodies = append(s.bodies, string(body))
+	s.paths = append(s.paths, req.Method+" "+req.URL.Path)
+	s.queries = append(s.queries, req.URL.RawQuery)
+
+	resp := scriptedResponse{status: http.StatusOK, body: "{}"}
+	if len(s.responses) > 0 {
+		resp = s.responses[0]
+		s.responses = s.responses[1:]
+	}
+	if resp.status == 0 {
+		resp.status = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: resp.status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader([]byte(resp.body))),
+	}, nil
+}
+
+// memoryCheckpointStore keeps checkpoints in memory.
+type memoryCheckpointStore map[string]ExportCheckpoint
+
+func (m memoryCheckpointStore) Load(ctx context.Context, key string) (*ExportCheckpoint, error) {
+	cp, ok := m[key]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+func (m memoryCheckpointStore) Save(ctx context.Context, key string, cp *ExportCheckpoint) error {
+	m[key] = *cp
+	return nil
+}
+
+func (m memoryCheckpointStore) Delete(ctx context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+func TestClient_Export_Resume(t *testing.T) {
+	ctx := context.Background()
+	store := memoryCheckpointStore{
+		"export-1": {PITID: "pit-old", SearchAfter: []any{float64(100), "a"}, Exported: 10},
+	}
+	es := &scriptedES{responses: []scriptedResponse{
+		{status: http.StatusNotFound, body: `{"error": {"type": "search_context_missing_exception"}}`},
+		{body: `{"id": "pit-new"}`},
+		{body: `{"pit_id": "pit-new", "hits": {"hits": [{"_id": "1", "sort": [200, "b"]}]}}`},
+		{body: `{"pit_id": "pit-new", "hits": {"hits": []}}`},
+	}}
+	client := newTestClient(t, es)
+
+	it, err := client.Export(ctx, &ExportRequest{
+		Index:         "orders",
+		CompanyID:     "c1",
+		Sort:          []SortClause{{Field: "created_at"}, {Field: "order_id"}},
+		Store:         store,
+		CheckpointKey: "export-1",
+	})
+	require.NoError(t, err)
+	assert.True(t, it.Resumed())
+
+	hits, err := it.Next(ctx)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, int64(11), it.Exported())
+	assert.Equal(t, ExportCheckpoint{PITID: "pit-new", SearchAfter: []any{float64(200), "b"}, Exported: 11}, store["export-1"])
+
+	// Search after checkpointed sort values on a new PIT
+	var body map[string]any
+	require.NoError(t, json.Unmarshal([]byte(es.bodies[2]), &body))
+	assert.Equal(t, []any{float64(100), "a"}, body["search_after"])
+	assert.Equal(t, "pit-new", body["pit"].(map[string]any)["id"])
+
+	_, err = it.Next(ctx)
+	assert.Equal(t, io.EOF, err)
+	assert.Empty(t, store)
+	assert.Equal(t, "DELETE /_pit", es.paths[len(es.paths)-1])
+}
+
+func TestClient_Export_ExpiredShardDocCheckpoint(t *testing.T) {
+	store := memoryCheckpointStore{"export-1": {PITID: "pit-old", SearchAfter: []any{float64(5)}}}
+	es := &scriptedES{responses: []scriptedResponse{{status: http.StatusNotFound}}}
+	client := newTestClient(t, es)
+
+	it, err := client.Export(context.Background(), &ExportRequest{
+		Index:         "orders",
+		CompanyID:     "c1",
+		Store:         store,
+		CheckpointKey: "export-1",
+	})
+	require.NoError(t, err)
+
+	_, err = it.Next(context.Background())
+	assert.ErrorIs(t, err, ErrCheckpointExpired)
+}
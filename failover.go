@@ -0,0 +1,255 @@
+package esclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultMaxQueuedWrites = 10000
+
+// WriteFunc is a write operation executed against primary cluster.
+// During primary outage it is queued and replayed on recovery, so it must be safe to run later
+// (e.g., idempotent index with explicit document ID).
+type WriteFunc func(ctx context.Context, c *Client) error
+
+// FailoverConfig configures failover coordinator.
+type FailoverConfig struct {
+	Primary         *Client // Primary cluster client
+	Standby         *Client // Standby cluster client serving reads during primary outage
+	MaxQueuedWrites int     // Max writes queued during outage (default: 10000)
+
+	OnFailover     func(ctx context.Context, err error)    // Called when primary is marked down
+	OnRecovery     func(ctx context.Context, replayed int) // Called when primary is back and queue is replayed
+	OnWriteDropped func(ctx context.Context, err error)    // Called when write is dropped (queue full or rejected on replay)
+}
+
+// FailoverCoordinator serves an index type from primary cluster with standby for reads.
+// After primary failure reads go to standby and writes are queued until Recover
+// replays them on primary in original order.
+type FailoverCoordinator struct {
+	cfg      FailoverConfig
+	mu       sync.Mutex
+	down     bool
+	queue    []WriteFunc
+	replayMu sync.Mutex // serializes Recover calls
+}
+
+// NewFailoverCoordinator creates a new failover coordinator.
+func NewFailoverCoordinator(cfg FailoverConfig) (*FailoverCoordinator, error) {
+	if cfg.Primary == nil {
+		return nil, errors.New("primary client is required")
+	}
+	if cfg.Standby == nil {
+		return nil, errors.New("standby client is required")
+	}
+	if cfg.MaxQueuedWrites <= 0 {
+		cfg.MaxQueuedWrites = defaultMaxQueuedWrites
+	}
+
+	return &FailoverCoordinator{cfg: cfg}, nil
+}
+
+// Down reports whether primary is currently marked down.
+func (f *FailoverCoordinator) Down() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.down
+}
+
+// QueuedWrites returns number of writes waiting for replay.
+func (f *FailoverCoordinator) QueuedWrites() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.queue)
+}
+
+// Search searches primary cluster, retrying on standby if primary is unavailable.
+// While primary is marked down, searches go to standby directly.
+func (f *FailoverCoordinator) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	if !f.Down() {
+		resp, err := f.cfg.Primary.Search(ctx, req)
+		if err == nil || !isUnavailable(ctx, err) {
+			return resp, err
+		}
+		f.markDown(ctx, err)
+	}
+
+	resp, err := f.cfg.Standby.Search(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "standby search failed")
+	}
+	return resp, nil
+}
+
+// Write executes write on primary cluster. If primary is unavailable, write is queued
+// for replay and queued is true. Errors not caused by unavailability (e.g., 4xx) are returned as is.
+func (f *FailoverCoordinator) Write(ctx context.Context, op WriteFunc) (queued bool, err error) {
+	if !f.Down() {
+		err := op(ctx, f.cfg.Primary)
+		if err == nil || !isUnavailable(ctx, err) {
+			return false, err
+		}
+		f.markDown(ctx, err)
+	}
+
+	if err := f.enqueue(ctx, op); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Recover checks primary availability and replays queued writes in order.
+// Writes rejected by primary on replay are dropped and reported via OnWriteDropped.
+// If primary fails again, replay stops and remaining writes stay queued.
+func (f *FailoverCoordinator) Recover(ctx context.Context) error {
+	f.replayMu.Lock()
+	defer f.replayMu.Unlock()
+
+	if !f.Down() {
+		return nil
+	}
+
+	if err := f.cfg.Primary.ping(ctx); err != nil {
+		return errors.Wrap(err, "primary is still unavailable")
+	}
+
+	replayed := 0
+	for {
+		f.mu.Lock()
+		if len(f.queue) == 0 {
+			// Mark up under the same lock so no write is queued after replay finished
+			f.down = false
+			f.mu.Unlock()
+			break
+		}
+		op := f.queue[0]
+		f.mu.Unlock()
+
+		if err := op(ctx, f.cfg.Primary); err != nil {
+			if isUnavailable(ctx, err) {
+				return errors.Wrapf(err, "replay stopped after %d writes", replayed)
+			}
+			f.dropped(ctx, errors.Wrap(err, "queued write rejected on replay"))
+		}
+
+		f.mu.Lock()
+		f.queue[0] = nil
+		f.queue = f.queue[1:]
+		f.mu.Unlock()
+		replayed++
+	}
+
+	logWarn(ctx, f.cfg.Primary.log, "elasticsearch failover primary recovered", map[string]interface{}{
+		"replayed": replayed,
+	})
+	if f.cfg.OnRecovery != nil {
+		f.cfg.OnRecovery(ctx, replayed)
+	}
+
+	return nil
+}
+
+// Run calls Recover every interval while primary is down, until ctx is done.
+func (f *FailoverCoordinator) Run(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.cfg.Primary.clock.After(interval):
+			if f.Down() {
+				_ = f.Recover(ctx)
+			}
+		}
+	}
+}
+
+// markDown marks primary down and fires OnFailover once per outage.
+func (f *FailoverCoordinator) markDown(ctx context.Context, err error) {
+	f.mu.Lock()
+	wasDown := f.down
+	f.down = true
+	f.mu.Unlock()
+
+	if wasDown {
+		return
+	}
+
+	logWarn(ctx, f.cfg.Primary.log, "elasticsearch failover primary marked down", map[string]interface{}{
+		"error": err.Error(),
+	})
+	if f.cfg.OnFailover != nil {
+		f.cfg.OnFailover(ctx, err)
+	}
+}
+
+// enqueue queues write for replay.
+func (f *FailoverCoordinator) enqueue(ctx context.Context, op WriteFunc) error {
+	f.mu.Lock()
+	full := len(f.queue) >= f.cfg.MaxQueuedWrites
+	if !full {
+		f.queue = append(f.queue, op)
+	}
+	f.mu.Unlock()
+
+	if full {
+		err := errors.Wrapf(ErrWriteQueueFull, "max %d writes", f.cfg.MaxQueuedWrites)
+		f.dropped(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// dropped reports dropped write.
+func (f *FailoverCoordinator) dropped(ctx context.Context, err error) {
+	logWarn(ctx, f.cfg.Primary.log, "elasticsearch failover write dropped", map[string]interface{}{
+		"error": err.Error(),
+	})
+	if f.cfg.OnWriteDropped != nil {
+		f.cfg.OnWriteDropped(ctx, err)
+	}
+}
+
+// ping checks that cluster responds to root endpoint.
+func (c *Client) ping(ctx context.Context) error {
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, "/", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return &StatusError{Op: "ping", StatusCode: status, body: errBody}
+	}
+	return nil
+}
+
+// isUnavailable reports whether err means cluster is unavailable: transport failure or 5xx status.
+// Other errors, such as request validation errors of caller bugs, are not unavailability;
+// neither is cancellation of caller's context.
+func isUnavailable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	return isTransportError(err)
+}
+
+// isTransportError reports whether err is failure to reach cluster: network error, request
+// timeout of client, or no live connection left in transport pool.
+func isTransportError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	// Transport reports exhausted connection pool without wrapping
+	return strings.Contains(err.Error(), "cannot get connection")
+}
@@ -0,0 +1,131 @@
+package esclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailoverCoordinator(t *testing.T) {
+	ctx := context.Background()
+	primaryES := &fakeES{status: http.StatusServiceUnavailable}
+	standbyES := &fakeES{response: `{"hits": {"hits": []}}`}
+
+	var failovers, recovered int
+	f, err := NewFailoverCoordinator(FailoverConfig{
+		Primary:    newTestClient(t, primaryES),
+		Standby:    newTestClient(t, standbyES),
+		OnFailover: func(ctx context.Context, err error) { failovers++ },
+		OnRecovery: func(ctx context.Context, replayed int) { recovered = replayed },
+	})
+	require.NoError(t, err)
+
+	// Read falls back to standby and marks primary down
+	_, err = f.Search(ctx, &SearchRequest{Index: "orders", CompanyID: "c1"})
+	require.NoError(t, err)
+	assert.True(t, f.Down())
+	assert.Len(t, standbyES.requests, 1)
+
+	// Write is queued without hitting primary
+	var written []string
+	write := func(id string) WriteFunc {
+		return func(ctx context.Context, c *Client) error {
+			_, err := c.IndexExists(ctx, id)
+			if err == nil {
+				written = append(written, id)
+			}
+			return err
+		}
+	}
+	queued, err := f.Write(ctx, write("a"))
+	require.NoError(t, err)
+	assert.True(t, queued)
+	queued, err = f.Write(ctx, write("b"))
+	require.NoError(t, err)
+	assert.True(t, queued)
+	assert.Equal(t, 2, f.QueuedWrites())
+	assert.Len(t, primaryES.requests, 1)
+
+	// Primary still down
+	require.Error(t, f.Recover(ctx))
+	assert.Equal(t, 2, f.QueuedWrites())
+
+	// Primary back: queue is replayed in order
+	primaryES.status = http.StatusOK
+	require.NoError(t, f.Recover(ctx))
+	assert.False(t, f.Down())
+	assert.Equal(t, []string{"a", "b"}, written)
+	assert.Equal(t, 2, recovered)
+	assert.Equal(t, 1, failovers)
+}
+
+func TestFailoverCoordinator_ClientErrorNotFailover(t *testing.T) {
+	primaryES := &fakeES{status: http.StatusBadRequest}
+	f, err := NewFailoverCoordinator(FailoverConfig{
+		Primary: newTestClient(t, primaryES),
+		Standby: newTestClient(t, &fakeES{}),
+	})
+	require.NoError(t, err)
+
+	_, err = f.Search(context.Background(), &SearchRequest{Index: "orders", CompanyID: "c1"})
+	require.Error(t, err)
+	assert.False(t, f.Down())
+}
+
+// failingES fails every request with err.
+type failingES struct {
+	err error
+}
+
+func (f *failingES) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return nil, f.err
+}
+
+func TestFailoverCoordinator_CallerErrorNotFailover(t *testing.T) {
+	primaryES := &fakeES{}
+	var failovers int
+	f, err := NewFailoverCoordinator(FailoverConfig{
+		Primary:    newTestClient(t, primaryES),
+		Standby:    newTestClient(t, &fakeES{}),
+		OnFailover: func(ctx context.Context, err error) { failovers++ },
+	})
+	require.NoError(t, err)
+
+	// Shared index without CompanyID fails validation before any request
+	_, err = f.Search(context.Background(), &SearchRequest{Index: "orders_shared"})
+	assert.ErrorContains(t, err, "companyID required for shared index")
+	_, err = f.Write(context.Background(), func(ctx context.Context, c *Client) error {
+		_, err := c.Count(ctx, &CountRequest{Index: "orders_shared"})
+		return err
+	})
+	assert.Error(t, err)
+	assert.False(t, f.Down())
+	assert.Zero(t, failovers)
+	assert.Empty(t, primaryES.requests)
+}
+
+func TestIsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	assert.True(t, isUnavailable(ctx, refused))
+	assert.True(t, isUnavailable(ctx, &url.Error{Op: "Post", URL: "http://es:9200/_search", Err: refused}))
+	assert.True(t, isUnavailable(ctx, errors.New("cannot get connection: no connection available")))
+	assert.True(t, isUnavailable(ctx, &StatusError{StatusCode: http.StatusBadGateway}))
+	assert.False(t, isUnavailable(ctx, &StatusError{StatusCode: http.StatusNotFound}))
+	assert.False(t, isUnavailable(ctx, errors.New("index name is required")))
+
+	// Transport failure of client is unavailability
+	primary := newTestClient(t, &failingES{err: refused})
+	f, err := NewFailoverCoordinator(FailoverConfig{Primary: primary, Standby: newTestClient(t, &fakeES{})})
+	require.NoError(t, err)
+	_, err = f.Search(ctx, &SearchRequest{Index: "orders_shared", CompanyID: "c1"})
+	require.NoError(t, err)
+	assert.True(t, f.Down())
+}
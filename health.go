@@ -0,0 +1,147 @@
+package esclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ClusterStatus is result of latest background health check of cluster.
+type ClusterStatus struct {
+	Healthy             bool          // Health request succeeded and cluster status is not red
+	Status              string        // Cluster status: "green", "yellow" or "red"; empty if request failed
+	Latency             time.Duration // Duration of health request
+	CheckedAt           time.Time     // Time check finished
+	Error               string        // Health request or client creation error
+	ConsecutiveFailures int           // Unhealthy checks in a row
+}
+
+// StartHealthChecks checks health of every registered cluster right away and then every interval
+// until ctx is done. Every check is limited by interval. Only one run at a time is allowed;
+// checks can be started again once ctx of previous run is done.
+//
+// Results are only reported by Health and Healthy: Resolver and typed clients keep using
+// unhealthy clusters, so callers that want to skip them must check Healthy themselves.
+// Time source is clock of typed clients (see WithClock).
+func (r *Registry) StartHealthChecks(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("health check interval must be positive")
+	}
+
+	r.healthMu.Lock()
+	if r.healthRunning {
+		r.healthMu.Unlock()
+		return errors.New("health checks already started")
+	}
+	r.healthRunning = true
+	r.healthMu.Unlock()
+
+	clock := optionsClock(r.clientOpts)
+	r.checkHealth(ctx, interval, clock)
+	go func() {
+		defer func() {
+			r.healthMu.Lock()
+			r.healthRunning = false
+			r.healthMu.Unlock()
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-clock.After(interval):
+				r.checkHealth(ctx, interval, clock)
+			}
+		}
+	}()
+	return nil
+}
+
+// Health returns latest health check results by cluster name.
+// Clusters not checked yet are missing.
+func (r *Registry) Health() map[string]ClusterStatus {
+	r.healthMu.RLock()
+	defer r.healthMu.RUnlock()
+
+	result := make(map[string]ClusterStatus, len(r.health))
+	for name, status := range r.health {
+		result[name] = status
+	}
+	return result
+}
+
+// Healthy reports whether latest health check of cluster succeeded.
+// Clusters not checked yet are considered healthy.
+func (r *Registry) Healthy(clusterName string) bool {
+	if clusterName == "" {
+		clusterName = r.defaultName
+	}
+
+	r.healthMu.RLock()
+	defer r.healthMu.RUnlock()
+
+	status, ok := r.health[clusterName]
+	return !ok || status.Healthy
+}
+
+// checkHealth checks all registered clusters concurrently and records results.
+func (r *Registry) checkHealth(ctx context.Context, timeout time.Duration, clock Clock) {
+	names := r.ListClusters()
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			status := r.pingCluster(ctx, name, timeout, clock)
+
+			r.healthMu.Lock()
+			defer r.healthMu.Unlock()
+			if !status.Healthy {
+				status.ConsecutiveFailures = r.health[name].ConsecutiveFailures + 1
+			}
+			if r.health == nil {
+				r.health = make(map[string]ClusterStatus)
+			}
+			r.health[name] = status
+		}(name)
+	}
+	wg.Wait()
+
+	// Drop results of clusters removed meanwhile
+	registered := make(map[string]bool, len(names))
+	for _, name := range r.ListClusters() {
+		registered[name] = true
+	}
+	r.healthMu.Lock()
+	defer r.healthMu.Unlock()
+	for name := range r.health {
+		if !registered[name] {
+			delete(r.health, name)
+		}
+	}
+}
+
+// pingCluster requests cluster health of single cluster.
+func (r *Registry) pingCluster(ctx context.Context, name string, timeout time.Duration, clock Clock) ClusterStatus {
+	client, err := r.GetTypedClient(name)
+	if err != nil {
+		return ClusterStatus{CheckedAt: clock.Now(), Error: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := clock.Now()
+	health, err := client.ClusterHealth(ctx)
+	now := clock.Now()
+	status := ClusterStatus{Latency: now.Sub(start), CheckedAt: now}
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Status = health.Status
+	status.Healthy = health.Status != "red"
+	return status
+}
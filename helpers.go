@@ -7,71 +7,191 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 )
 
-// doJSON executes HTTP request and decodes JSON response.
-// Returns status code and error if any.
-func doJSON(ctx context.Context, c ESClient, req *http.Request, out interface{}, log Logger) (int, error) {
-	// Log request body on debug level
-	if req.Body != nil {
-		reqBodyBytes, err := io.ReadAll(req.Body)
-		if err == nil {
-			req.Body = io.NopCloser(bytes.NewReader(reqBodyBytes))
-			log.DebugWithCtx(ctx, "elasticsearch request body", map[string]interface{}{
-				"method": req.Method,
-				"path":   req.URL.Path,
-				"body":   string(reqBodyBytes),
-			})
-		}
-	}
-
-	res, err := c.Do(ctx, req)
+// doJSON executes HTTP request and decodes JSON response with client codec.
+// Returns status code, start of error response body (see WithErrorBodyLimit) and error if any.
+func (c *Client) doJSON(ctx context.Context, req *http.Request, out interface{}) (int, *bodySnippet, error) {
+	res, err := c.send(ctx, req)
 	if err != nil {
-		return 0, errors.Wrap(err, "http request failed")
+		return 0, nil, err
 	}
 	defer res.Body.Close() //nolint:errcheck
 
 	status := res.StatusCode
+	c.captureMeta(ctx, req, res.Header, out)
 
-	if out == nil {
-		return status, nil
+	// Error body is read even without out to detect index blocks
+	if out == nil && status < http.StatusBadRequest {
+		return status, nil, nil
+	}
+
+	if status >= http.StatusMultipleChoices {
+		// Error pages of proxies may be huge, only start of body is kept
+		body, err := c.readErrorBody(res.Body)
+		if err != nil {
+			return status, nil, err
+		}
+		c.log.DebugWithCtx(ctx, "elasticsearch response body", map[string]interface{}{
+			"status_code": status,
+			"path":        req.URL.Path,
+			"body":        string(body.data),
+			"truncated":   body.truncated,
+		})
+		if blockErr := parseIndexBlockError(status, body.data); blockErr != nil {
+			return status, body, blockErr
+		}
+		return status, body, nil
 	}
 
 	bodyBytes, err := io.ReadAll(res.Body)
 	if err != nil {
-		return status, errors.Wrap(err, "failed to read response body")
+		return status, nil, errors.Wrap(err, "failed to read response body")
 	}
 
 	// Log response body on debug level
-	log.DebugWithCtx(ctx, "elasticsearch response body", map[string]interface{}{
+	c.log.DebugWithCtx(ctx, "elasticsearch response body", map[string]interface{}{
 		"status_code": status,
 		"path":        req.URL.Path,
 		"body":        string(bodyBytes),
 	})
 
-	if status >= http.StatusMultipleChoices {
-		return status, nil
+	if out == nil {
+		return status, nil, nil
+	}
+
+	if err := c.codec.Unmarshal(bodyBytes, out); err != nil {
+		return status, nil, errors.Wrapf(err, "failed to decode JSON response (status %d)", status)
+	}
+
+	return status, nil, nil
+}
+
+// defaultErrorBodyLimit is number of error response body bytes retained without WithErrorBodyLimit.
+const defaultErrorBodyLimit = 16 << 10
+
+// bodySnippet is start of response body retained on *StatusError.
+type bodySnippet struct {
+	data      []byte
+	truncated bool // body was longer than data
+}
+
+// readErrorBody reads at most error body limit bytes of response body.
+func (c *Client) readErrorBody(r io.Reader) (*bodySnippet, error) {
+	limit := c.errorBodyLimit
+	if limit <= 0 {
+		limit = defaultErrorBodyLimit
+	}
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+	if len(data) > limit {
+		return &bodySnippet{data: data[:limit], truncated: true}, nil
+	}
+	return &bodySnippet{data: data}, nil
+}
+
+// send executes HTTP request with buffered and size-checked body and returns response
+// with decompressed body. Caller must close response body.
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	// Log request body on debug level
+	if req.Body != nil {
+		reqBodyBytes, err := io.ReadAll(req.Body)
+		if err == nil {
+			c.log.DebugWithCtx(ctx, "elasticsearch request body", map[string]interface{}{
+				"method": req.Method,
+				"path":   req.URL.Path,
+				"body":   string(reqBodyBytes),
+			})
+
+			sendBytes, err := c.limitBody(ctx, req, reqBodyBytes)
+			if err != nil {
+				return nil, err
+			}
+			req.Body = io.NopCloser(bytes.NewReader(sendBytes))
+			// Body is buffered, so it can be replayed on retry
+			req.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(sendBytes)), nil
+			}
+		}
+	}
+
+	if c.acceptGzip {
+		req.Header.Set("Accept-Encoding", "gzip")
 	}
 
-	if err := json.Unmarshal(bodyBytes, out); err != nil {
-		return status, errors.Wrapf(err, "failed to decode JSON response (status %d)", status)
+	res, err := c.es.Do(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request failed")
+	}
+	if err := gunzipResponse(res); err != nil {
+		res.Body.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// doJSONRequest creates request with optional JSON body against client base URL,
+// executes it and decodes JSON response into out.
+// Returns status code, start of error response body and error if any.
+func (c *Client) doJSONRequest(ctx context.Context, method, path string, q url.Values, body interface{}, out interface{}) (int, *bodySnippet, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		r, err := c.jsonBody(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		bodyReader = r
+	}
+
+	u := newURL(c.baseURL, path, q)
+	httpReq, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to create %s %s request", method, path)
+	}
+	if body != nil {
+		contentTypeJSON(httpReq)
 	}
 
-	return status, nil
+	return c.doJSON(ctx, httpReq, out)
 }
 
 // newURL creates absolute URL from base URL, path and query parameters.
+// Path segments escaped by caller (e.g., document IDs) are sent as is, not escaped twice.
 func newURL(base *url.URL, path string, q url.Values) *url.URL {
 	u := *base
 	u.Path = path
+	if unescaped, err := url.PathUnescape(path); err == nil && unescaped != path {
+		u.Path, u.RawPath = unescaped, path
+	}
 	if q != nil {
 		u.RawQuery = q.Encode()
 	}
 	return &u
 }
 
+// setIndicesOptions sets ignore_unavailable and allow_no_indices query parameters.
+func setIndicesOptions(q url.Values, ignoreUnavailable bool, allowNoIndices *bool) {
+	if ignoreUnavailable {
+		q.Set("ignore_unavailable", "true")
+	}
+	if allowNoIndices != nil {
+		q.Set("allow_no_indices", strconv.FormatBool(*allowNoIndices))
+	}
+}
+
+// setRouting sets routing query parameter if not empty.
+func setRouting(q url.Values, routing string) {
+	if routing != "" {
+		q.Set("routing", routing)
+	}
+}
+
 // jsonBody marshals value to JSON and returns io.Reader.
 func jsonBody(v interface{}) (io.Reader, error) {
 	b, err := json.Marshal(v)
@@ -81,6 +201,30 @@ func jsonBody(v interface{}) (io.Reader, error) {
 	return bytes.NewReader(b), nil
 }
 
+// jsonBody marshals v with client codec into request body reader.
+func (c *Client) jsonBody(v interface{}) (io.Reader, error) {
+	b, err := c.codec.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal JSON")
+	}
+	return bytes.NewReader(b), nil
+}
+
+// createIndexBody builds create index body from settings, mappings and aliases.
+func createIndexBody(req *CreateIndexRequest) (io.Reader, error) {
+	body := make(map[string]any)
+	if len(req.Settings) > 0 {
+		body["settings"] = req.Settings
+	}
+	if len(req.Mappings) > 0 {
+		body["mappings"] = req.Mappings
+	}
+	if len(req.Aliases) > 0 {
+		body["aliases"] = req.Aliases
+	}
+	return jsonBody(body)
+}
+
 // contentTypeJSON sets Content-Type header to application/json.
 func contentTypeJSON(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
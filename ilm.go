@@ -0,0 +1,104 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// ILMPolicy represents index lifecycle management policy.
+type ILMPolicy struct {
+	Phases map[string]ILMPhase `json:"phases"`          // Phase name ("hot", "warm", "cold", "delete") -> phase
+	Meta   map[string]any      `json:"_meta,omitempty"` // Arbitrary metadata, optional
+}
+
+// ILMPhase represents a single phase of ILM policy.
+type ILMPhase struct {
+	MinAge  string         `json:"min_age,omitempty"` // Min index age to enter phase (e.g., "30d")
+	Actions map[string]any `json:"actions"`           // Phase actions (e.g., {"delete": {}})
+}
+
+// ILMPolicyInfo represents stored ILM policy.
+type ILMPolicyInfo struct {
+	Version      int       `json:"version"`
+	ModifiedDate string    `json:"modified_date"`
+	Policy       ILMPolicy `json:"policy"`
+}
+
+// PutILMPolicy creates or updates ILM policy.
+func (c *Client) PutILMPolicy(ctx context.Context, name string, policy *ILMPolicy) error {
+	if name == "" {
+		return errors.New("policy name is required")
+	}
+	if policy == nil {
+		return errors.New("policy is required")
+	}
+
+	body := map[string]any{"policy": policy}
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("/_ilm/policy/%s", name), nil, body, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "put_ilm_policy", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// GetILMPolicy returns ILM policy by name.
+func (c *Client) GetILMPolicy(ctx context.Context, name string) (*ILMPolicyInfo, error) {
+	if name == "" {
+		return nil, errors.New("policy name is required")
+	}
+
+	var resp map[string]ILMPolicyInfo
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("/_ilm/policy/%s", name), nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "get_ilm_policy", StatusCode: status, body: errBody}
+	}
+
+	info, ok := resp[name]
+	if !ok {
+		return nil, errors.Errorf("policy %q not found in response", name)
+	}
+
+	return &info, nil
+}
+
+// DeleteILMPolicy deletes ILM policy by name.
+func (c *Client) DeleteILMPolicy(ctx context.Context, name string) error {
+	if name == "" {
+		return errors.New("policy name is required")
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodDelete, fmt.Sprintf("/_ilm/policy/%s", name), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "delete_ilm_policy", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// ILMSettings returns index settings attaching ILM policy, for use in CreateIndexRequest.Settings.
+// If rolloverAlias is set, it is used by policy rollover action.
+func ILMSettings(policy, rolloverAlias string) map[string]any {
+	settings := map[string]any{
+		"index.lifecycle.name": policy,
+	}
+	if rolloverAlias != "" {
+		settings["index.lifecycle.rollover_alias"] = rolloverAlias
+	}
+	return settings
+}
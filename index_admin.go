@@ -0,0 +1,272 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RolloverConditions defines conditions under which alias is rolled over.
+// Empty conditions roll over unconditionally.
+type RolloverConditions struct {
+	MaxAge              string // Max index age (e.g., "30d")
+	MaxDocs             int64  // Max number of documents
+	MaxSize             string // Max total primary size (e.g., "50gb")
+	MaxPrimaryShardSize string // Max primary shard size (e.g., "50gb")
+}
+
+// RolloverRequest represents rollover request.
+type RolloverRequest struct {
+	Alias      string             // Write alias to roll over
+	NewIndex   string             // New index name, optional (default: increments numeric suffix)
+	Conditions RolloverConditions // Rollover conditions
+	DryRun     bool               // Only check conditions without rolling over
+}
+
+// RolloverResponse represents rollover response.
+type RolloverResponse struct {
+	Acknowledged       bool            `json:"acknowledged"`
+	ShardsAcknowledged bool            `json:"shards_acknowledged"`
+	OldIndex           string          `json:"old_index"`
+	NewIndex           string          `json:"new_index"`
+	RolledOver         bool            `json:"rolled_over"`
+	DryRun             bool            `json:"dry_run"`
+	Conditions         map[string]bool `json:"conditions"` // Condition -> met
+}
+
+// Rollover rolls alias over to a new index when conditions are met.
+func (c *Client) Rollover(ctx context.Context, req *RolloverRequest) (*RolloverResponse, error) {
+	if req.Alias == "" {
+		return nil, errors.New("alias is required")
+	}
+
+	path := fmt.Sprintf("/%s/_rollover", req.Alias)
+	if req.NewIndex != "" {
+		path = fmt.Sprintf("/%s/_rollover/%s", req.Alias, req.NewIndex)
+	}
+
+	query := url.Values{}
+	if req.DryRun {
+		query.Set("dry_run", "true")
+	}
+
+	conditions := make(map[string]any)
+	if req.Conditions.MaxAge != "" {
+		conditions["max_age"] = req.Conditions.MaxAge
+	}
+	if req.Conditions.MaxDocs > 0 {
+		conditions["max_docs"] = req.Conditions.MaxDocs
+	}
+	if req.Conditions.MaxSize != "" {
+		conditions["max_size"] = req.Conditions.MaxSize
+	}
+	if req.Conditions.MaxPrimaryShardSize != "" {
+		conditions["max_primary_shard_size"] = req.Conditions.MaxPrimaryShardSize
+	}
+
+	var body interface{}
+	if len(conditions) > 0 {
+		body = map[string]any{"conditions": conditions}
+	}
+
+	var resp RolloverResponse
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, path, query, body, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "rollover", StatusCode: status, body: errBody}
+	}
+
+	return &resp, nil
+}
+
+// IndexMetadata represents index settings, mappings and aliases.
+type IndexMetadata struct {
+	Name     string                `json:"-"`        // Concrete index name (may differ from requested alias)
+	Aliases  map[string]IndexAlias `json:"aliases"`  // Alias name -> alias properties
+	Mappings map[string]any        `json:"mappings"` // Index mappings
+	Settings map[string]string     `json:"settings"` // Flat settings (e.g., "index.number_of_shards" -> "3")
+}
+
+// IndexAlias represents alias properties of an index.
+type IndexAlias struct {
+	Filter        map[string]any `json:"filter,omitempty"`
+	IndexRouting  string         `json:"index_routing,omitempty"`
+	SearchRouting string         `json:"search_routing,omitempty"`
+	IsWriteIndex  *bool          `json:"is_write_index,omitempty"`
+}
+
+// NumberOfShards returns number of primary shards, or 0 if setting is missing.
+func (m *IndexMetadata) NumberOfShards() int {
+	n, _ := strconv.Atoi(m.Settings["index.number_of_shards"])
+	return n
+}
+
+// NumberOfReplicas returns number of replicas, or 0 if setting is missing.
+func (m *IndexMetadata) NumberOfReplicas() int {
+	n, _ := strconv.Atoi(m.Settings["index.number_of_replicas"])
+	return n
+}
+
+// GetIndex returns settings, mappings and aliases of a single index.
+// Index may be an alias pointing to exactly one index.
+func (c *Client) GetIndex(ctx context.Context, index string) (*IndexMetadata, error) {
+	if index == "" {
+		return nil, errors.New("index name is required")
+	}
+
+	query := url.Values{}
+	query.Set("flat_settings", "true")
+
+	var resp map[string]IndexMetadata
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("/%s", index), query, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "get_index", StatusCode: status, body: errBody}
+	}
+
+	if len(resp) != 1 {
+		return nil, errors.Errorf("index %q matches %d indices, expected 1", index, len(resp))
+	}
+
+	for name, meta := range resp {
+		meta.Name = name
+		return &meta, nil
+	}
+	return nil, nil
+}
+
+// Refresh makes recent writes to indices visible to search.
+// Without indices all indices are refreshed.
+func (c *Client) Refresh(ctx context.Context, indices ...string) error {
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, indicesPath(indices, "_refresh"), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "refresh", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// Flush persists indices data to disk and clears translog.
+// Without indices all indices are flushed.
+func (c *Client) Flush(ctx context.Context, indices ...string) error {
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, indicesPath(indices, "_flush"), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "flush", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// indicesPath builds "/<indices>/<endpoint>" path, or "/<endpoint>" if indices are empty.
+func indicesPath(indices []string, endpoint string) string {
+	if len(indices) == 0 {
+		return "/" + endpoint
+	}
+	return fmt.Sprintf("/%s/%s", strings.Join(indices, ","), endpoint)
+}
+
+// ResizeRequest represents shrink, split or clone request.
+// Source index must be write-blocked (see PutSettings with "index.blocks.write").
+type ResizeRequest struct {
+	Index               string         // Source index
+	Target              string         // Target index
+	Settings            map[string]any // Target index settings (e.g., "index.number_of_shards"), optional
+	Aliases             map[string]any // Target index aliases, optional
+	WaitForActiveShards string         // Active shards to wait for (e.g., "all"), optional
+}
+
+// ResizeResponse represents shrink, split or clone response.
+type ResizeResponse struct {
+	Acknowledged       bool   `json:"acknowledged"`
+	ShardsAcknowledged bool   `json:"shards_acknowledged"`
+	Index              string `json:"index"`
+}
+
+// Shrink shrinks index into target index with fewer primary shards.
+// All source shards must be allocated on a single node.
+func (c *Client) Shrink(ctx context.Context, req *ResizeRequest) (*ResizeResponse, error) {
+	return c.resize(ctx, "shrink", req)
+}
+
+// Split splits index into target index with more primary shards.
+// Target number of shards must be a multiple of source number of shards.
+func (c *Client) Split(ctx context.Context, req *ResizeRequest) (*ResizeResponse, error) {
+	return c.resize(ctx, "split", req)
+}
+
+// Clone clones index into target index with the same number of primary shards.
+func (c *Client) Clone(ctx context.Context, req *ResizeRequest) (*ResizeResponse, error) {
+	return c.resize(ctx, "clone", req)
+}
+
+// resize performs shrink, split or clone operation.
+func (c *Client) resize(ctx context.Context, op string, req *ResizeRequest) (*ResizeResponse, error) {
+	if req.Index == "" {
+		return nil, errors.New("index name is required")
+	}
+	if req.Target == "" {
+		return nil, errors.New("target index name is required")
+	}
+
+	query := url.Values{}
+	if req.WaitForActiveShards != "" {
+		query.Set("wait_for_active_shards", req.WaitForActiveShards)
+	}
+
+	body := make(map[string]any)
+	if len(req.Settings) > 0 {
+		body["settings"] = req.Settings
+	}
+	if len(req.Aliases) > 0 {
+		body["aliases"] = req.Aliases
+	}
+
+	var resp ResizeResponse
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, fmt.Sprintf("/%s/_%s/%s", req.Index, op, req.Target), query, body, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: op, StatusCode: status, body: errBody}
+	}
+
+	return &resp, nil
+}
+
+// PutSettings updates dynamic settings of index (e.g., "index.blocks.write", "index.number_of_replicas").
+func (c *Client) PutSettings(ctx context.Context, index string, settings map[string]any) error {
+	if index == "" {
+		return errors.New("index name is required")
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("/%s/_settings", index), nil, settings, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "put_settings", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
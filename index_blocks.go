@@ -0,0 +1,89 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// defaultUnblockMaxDiskUsedPercent is max disk usage of data nodes at which
+// RemoveReadOnlyBlock lifts block; matches default high disk watermark.
+const defaultUnblockMaxDiskUsedPercent = 90.0
+
+var (
+	blockedIndexRe = regexp.MustCompile(`index \[([^\]]+)\]`)
+	blockRe        = regexp.MustCompile(`blocked by: \[([^\]]+)\]`)
+)
+
+// errorBody is error response of Elasticsearch.
+type errorBody struct {
+	Error struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// parseIndexBlockError returns *IndexBlockError if response body is cluster_block_exception, otherwise nil.
+func parseIndexBlockError(status int, body []byte) *IndexBlockError {
+	var eb errorBody
+	if err := json.Unmarshal(body, &eb); err != nil || eb.Error.Type != "cluster_block_exception" {
+		return nil
+	}
+
+	reason := eb.Error.Reason
+	blockErr := &IndexBlockError{
+		StatusCode: status,
+		Reason:     reason,
+		// ES 7.x+: "index has read-only-allow-delete block"; older: "index read-only / allow delete"
+		ReadOnlyAllowDelete: strings.Contains(reason, "read-only-allow-delete") || strings.Contains(reason, "read-only / allow delete"),
+	}
+	for _, m := range blockedIndexRe.FindAllStringSubmatch(reason, -1) {
+		blockErr.Indices = append(blockErr.Indices, m[1])
+	}
+	for _, m := range blockRe.FindAllStringSubmatch(reason, -1) {
+		blockErr.Blocks = append(blockErr.Blocks, m[1])
+	}
+	return blockErr
+}
+
+// UnblockOptions configures RemoveReadOnlyBlock guard.
+type UnblockOptions struct {
+	MaxDiskUsedPercent float64 // Refuse to unblock while any data node uses more disk (default: 90)
+}
+
+// RemoveReadOnlyBlock removes read_only_allow_delete block set by flood-stage disk watermark.
+// Guarded: refuses while any node disk usage is above MaxDiskUsedPercent, since ES would
+// block indices again right away. Free disk space (delete indices or add nodes) first.
+func (c *Client) RemoveReadOnlyBlock(ctx context.Context, indices []string, opts *UnblockOptions) error {
+	if len(indices) == 0 {
+		return errors.New("at least one index is required")
+	}
+
+	maxUsed := defaultUnblockMaxDiskUsedPercent
+	if opts != nil && opts.MaxDiskUsedPercent > 0 {
+		maxUsed = opts.MaxDiskUsedPercent
+	}
+
+	stats, err := c.NodesStats(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to check disk usage")
+	}
+	for id, node := range stats.Nodes {
+		if used := node.DiskUsedPercent(); used > maxUsed {
+			return errors.Errorf("node %s (%s) disk usage %.1f%% is above %.1f%%, free disk space before removing block",
+				node.Name, id, used, maxUsed)
+		}
+	}
+
+	c.log.DebugWithCtx(ctx, "elasticsearch removing read-only-allow-delete block", map[string]interface{}{
+		"indices": indices,
+	})
+	err = c.PutSettings(ctx, strings.Join(indices, ","), map[string]any{"index.blocks.read_only_allow_delete": nil})
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove block of %s", strings.Join(indices, ","))
+	}
+	return nil
+}
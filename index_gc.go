@@ -0,0 +1,134 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// OrphanScanConfig configures scan of per-company indices of deleted companies.
+// At least one of Tenants and CompanyExists is required, so empty tenant list never marks every index orphaned.
+type OrphanScanConfig struct {
+	Tenants []string // IDs of active companies, optional
+
+	// CompanyExists reports whether company still exists (e.g., lookup in sync service or company database),
+	// optional. With Tenants, it is asked only about companies missing from the list.
+	CompanyExists func(ctx context.Context, companyID string) (bool, error)
+
+	// Confirm is called for every orphaned index; index is deleted only if it returns true.
+	// If nil, scanner only reports and never deletes.
+	Confirm func(ctx context.Context, index OrphanIndex) bool
+}
+
+// OrphanIndex represents per-company index whose company no longer exists.
+type OrphanIndex struct {
+	Cluster   string // Cluster name in registry
+	Index     string // Index name
+	CompanyID string // Company ID from index name suffix
+	SizeBytes int64  // Total size of primary shards
+	Deleted   bool   // Deletion was confirmed and executed successfully
+}
+
+// ScanOrphanIndices lists per-company indices ("<prefix>_<companyID>") of every registered cluster
+// and reports those whose company is not active. Confirmed orphans are deleted.
+// Degraded clusters are skipped.
+func (r *Registry) ScanOrphanIndices(ctx context.Context, cfg OrphanScanConfig) ([]OrphanIndex, error) {
+	if len(cfg.Tenants) == 0 && cfg.CompanyExists == nil {
+		return nil, errors.New("tenant list or company existence check is required")
+	}
+
+	active := make(map[string]bool, len(cfg.Tenants))
+	for _, companyID := range cfg.Tenants {
+		active[companyID] = true
+	}
+	exists := func(companyID string) (bool, error) {
+		if known, ok := active[companyID]; ok || cfg.CompanyExists == nil {
+			return known, nil
+		}
+		known, err := cfg.CompanyExists(ctx, companyID)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check company %q", companyID)
+		}
+		// Same company has indices of several types and on several clusters
+		active[companyID] = known
+		return known, nil
+	}
+
+	names := r.ListClusters()
+	sort.Strings(names)
+
+	var result []OrphanIndex
+	for _, name := range names {
+		client, err := r.GetTypedClient(name)
+		if IsDegraded(err) {
+			continue
+		}
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to get client for cluster %q", name)
+		}
+
+		indices, err := client.companyIndices(ctx)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to list indices of cluster %q", name)
+		}
+
+		for _, orphan := range indices {
+			known, err := exists(orphan.CompanyID)
+			if err != nil {
+				return result, err
+			}
+			if known {
+				continue
+			}
+
+			orphan.Cluster = name
+			if cfg.Confirm != nil && cfg.Confirm(ctx, orphan) {
+				if err := client.DeleteIndex(ctx, orphan.Index); err != nil {
+					return append(result, orphan), errors.Wrapf(err, "failed to delete index %q on cluster %q", orphan.Index, name)
+				}
+				orphan.Deleted = true
+			}
+			result = append(result, orphan)
+		}
+	}
+
+	return result, nil
+}
+
+// companyIndices returns per-company indices of cluster sorted by name. System indices are skipped.
+func (c *Client) companyIndices(ctx context.Context) ([]OrphanIndex, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	query.Set("bytes", "b")
+	query.Set("h", "index,pri,pri.store.size")
+
+	var indices []catIndex
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, "/_cat/indices", query, nil, &indices)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "cat_indices", StatusCode: status, body: errBody}
+	}
+
+	var result []OrphanIndex
+	for _, idx := range indices {
+		if strings.HasPrefix(idx.Index, ".") || DetectIndexTarget(idx.Index) != IndexTargetPerCompany {
+			continue
+		}
+		var size int64
+		_, _ = fmt.Sscan(idx.PriStoreBytes, &size)
+		result = append(result, OrphanIndex{
+			Index:     idx.Index,
+			CompanyID: idx.Index[strings.LastIndex(idx.Index, "_")+1:],
+			SizeBytes: size,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Index < result[j].Index })
+	return result, nil
+}
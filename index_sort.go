@@ -0,0 +1,183 @@
+package esclient
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IndexSortField is field of index sort. Index sort is fixed at index creation, see IndexSortSettings.
+type IndexSortField struct {
+	Field   string // Field name; keyword, numeric, date or boolean field with doc values
+	Order   string // "asc" (default) or "desc"
+	Mode    string // Value of multi-valued field to sort by: "min" (default for asc) or "max" (default for desc)
+	Missing string // Position of documents without field: "_last" (default) or "_first"
+}
+
+// indexSortTypes are field types index can be sorted by.
+var indexSortTypes = map[string]bool{
+	"keyword": true, "boolean": true, "date": true, "date_nanos": true,
+	"long": true, "integer": true, "short": true, "byte": true, "unsigned_long": true,
+	"double": true, "float": true, "half_float": true, "scaled_float": true,
+}
+
+// IndexSortSettings returns index.sort.* settings for use in CreateIndexRequest.Settings, e.g.
+// created_at descending for time-ordered indices, so searches sorted the same way stop early.
+// Order, mode and missing are set for every field if any field sets them.
+func IndexSortSettings(fields ...IndexSortField) map[string]any {
+	settings := map[string]any{}
+	if len(fields) == 0 {
+		return settings
+	}
+
+	var names, orders, modes, missing []string
+	var withOrder, withMode, withMissing bool
+	for _, f := range fields {
+		order := f.Order
+		if order == "" {
+			order = "asc"
+		}
+		mode := f.Mode
+		if mode == "" {
+			mode = "min"
+			if order == "desc" {
+				mode = "max"
+			}
+		}
+		miss := f.Missing
+		if miss == "" {
+			miss = "_last"
+		}
+		names = append(names, f.Field)
+		orders = append(orders, order)
+		modes = append(modes, mode)
+		missing = append(missing, miss)
+		withOrder = withOrder || f.Order != ""
+		withMode = withMode || f.Mode != ""
+		withMissing = withMissing || f.Missing != ""
+	}
+
+	settings["index.sort.field"] = names
+	if withOrder {
+		settings["index.sort.order"] = orders
+	}
+	if withMode {
+		settings["index.sort.mode"] = modes
+	}
+	if withMissing {
+		settings["index.sort.missing"] = missing
+	}
+	return settings
+}
+
+// ValidateIndexSort checks index.sort.* settings against mappings: sort fields must be mapped
+// outside nested objects with sortable type and doc values, and order, mode and missing must be
+// valid and match fields in number. Returns nil if settings have no index sort.
+// Returns *MultiError listing every problem found.
+func ValidateIndexSort(settings, mappings map[string]any) error {
+	fields := indexSortSetting(settings, "field")
+	if len(fields) == 0 {
+		return nil
+	}
+
+	errs := &MultiError{}
+	for _, field := range fields {
+		def, nested := lookupMappingField(mappings, field)
+		switch {
+		case nested:
+			errs.Errors = append(errs.Errors, errors.Errorf("index sort field %q is inside nested field", field))
+		case def == nil:
+			errs.Errors = append(errs.Errors, errors.Errorf("index sort field %q is not mapped", field))
+		case !indexSortTypes[mappingType(def)]:
+			errs.Errors = append(errs.Errors, errors.Errorf("index sort field %q has type %q, must be keyword, numeric, date or boolean", field, mappingType(def)))
+		case def["doc_values"] == false:
+			errs.Errors = append(errs.Errors, errors.Errorf("index sort field %q has doc values disabled", field))
+		}
+	}
+
+	allowed := map[string][]string{
+		"order":   {"asc", "desc"},
+		"mode":    {"min", "max"},
+		"missing": {"_last", "_first"},
+	}
+	for _, key := range []string{"order", "mode", "missing"} {
+		values := indexSortSetting(settings, key)
+		if values == nil {
+			continue
+		}
+		if len(values) != len(fields) {
+			errs.Errors = append(errs.Errors, errors.Errorf("index.sort.%s has %d values for %d sort fields", key, len(values), len(fields)))
+		}
+		for _, v := range values {
+			if !slices.Contains(allowed[key], v) {
+				errs.Errors = append(errs.Errors, errors.Errorf("index.sort.%s value %q must be one of %s", key, v, strings.Join(allowed[key], ", ")))
+			}
+		}
+	}
+
+	return errs.errOrNil()
+}
+
+// indexSortSetting returns values of index.sort.<key> set as flat ("index.sort.field", "sort.field")
+// or nested ({"index": {"sort": {"field": ...}}}) setting, nil if not set.
+func indexSortSetting(settings map[string]any, key string) []string {
+	value, ok := settings["index.sort."+key]
+	if !ok {
+		value, ok = settings["sort."+key]
+	}
+	if !ok {
+		index, _ := settings["index"].(map[string]any)
+		sort, _ := index["sort"].(map[string]any)
+		value, ok = sort[key]
+	}
+	if !ok {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			s, _ := item.(string)
+			result = append(result, s)
+		}
+		return result
+	}
+	return []string{}
+}
+
+// lookupMappingField returns mapping of dotted field path, following object properties and
+// multi-fields. nested reports that path goes through nested field.
+func lookupMappingField(mappings map[string]any, path string) (def map[string]any, nested bool) {
+	current := mappings
+	for _, part := range strings.Split(path, ".") {
+		if def != nil && mappingType(def) == "nested" {
+			return nil, true
+		}
+		props, _ := current["properties"].(map[string]any)
+		if def != nil {
+			if multi, ok := def["fields"].(map[string]any); ok && props == nil {
+				props = multi
+			}
+		}
+		def, _ = props[part].(map[string]any)
+		if def == nil {
+			return nil, false
+		}
+		current = def
+	}
+	return def, false
+}
+
+// mappingType returns type of field mapping; fields with properties and no type are objects.
+func mappingType(def map[string]any) string {
+	if t, ok := def["type"].(string); ok {
+		return t
+	}
+	return "object"
+}
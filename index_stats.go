@@ -0,0 +1,131 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// IndexStats represents primaries or total statistics of an index.
+type IndexStats struct {
+	Docs struct {
+		Count   int64 `json:"count"`
+		Deleted int64 `json:"deleted"`
+	} `json:"docs"`
+	Store struct {
+		SizeInBytes int64 `json:"size_in_bytes"`
+	} `json:"store"`
+	Indexing struct {
+		IndexTotal        int64 `json:"index_total"`
+		IndexTimeInMillis int64 `json:"index_time_in_millis"`
+		IndexFailed       int64 `json:"index_failed"`
+	} `json:"indexing"`
+	Search struct {
+		QueryTotal        int64 `json:"query_total"`
+		QueryTimeInMillis int64 `json:"query_time_in_millis"`
+	} `json:"search"`
+}
+
+// IndexStatsEntry represents statistics of a single index.
+type IndexStatsEntry struct {
+	UUID      string     `json:"uuid"`
+	Primaries IndexStats `json:"primaries"` // Primary shards only
+	Total     IndexStats `json:"total"`     // Primaries and replicas
+}
+
+// IndexStatsResponse represents index stats response.
+type IndexStatsResponse struct {
+	All     IndexStatsEntry            `json:"_all"`    // Aggregated over all matched indices
+	Indices map[string]IndexStatsEntry `json:"indices"` // Index name -> stats
+}
+
+// ClusterIndexStats represents statistics of an index on a registry cluster.
+type ClusterIndexStats struct {
+	Cluster string // Cluster name in registry
+	Index   string // Index name
+	IndexStatsEntry
+}
+
+// IndexingRate returns documents indexed per second since prev sample taken elapsed ago.
+func (s *IndexStats) IndexingRate(prev *IndexStats, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Indexing.IndexTotal-prev.Indexing.IndexTotal) / elapsed.Seconds()
+}
+
+// SearchRate returns queries per second since prev sample taken elapsed ago.
+func (s *IndexStats) SearchRate(prev *IndexStats, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Search.QueryTotal-prev.Search.QueryTotal) / elapsed.Seconds()
+}
+
+// IndexStats returns docs, store, indexing and search statistics of indices matching index
+// (name, pattern or comma-separated list).
+func (c *Client) IndexStats(ctx context.Context, index string) (*IndexStatsResponse, error) {
+	if index == "" {
+		return nil, errors.New("index name is required")
+	}
+
+	var resp IndexStatsResponse
+	path := fmt.Sprintf("/%s/_stats/docs,store,indexing,search", index)
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, path, nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "index_stats", StatusCode: status, body: errBody}
+	}
+
+	return &resp, nil
+}
+
+// IndexStats returns statistics of indices matching index on every registered cluster,
+// sorted by cluster and index name. Clusters without matching indices are skipped.
+func (r *Registry) IndexStats(ctx context.Context, index string) ([]ClusterIndexStats, error) {
+	names := r.ListClusters()
+	sort.Strings(names)
+
+	var result []ClusterIndexStats
+	for _, name := range names {
+		client, err := r.GetTypedClient(name)
+		if IsDegraded(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get client for cluster %q", name)
+		}
+
+		stats, err := client.IndexStats(ctx, index)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get index stats of cluster %q", name)
+		}
+
+		indices := make([]string, 0, len(stats.Indices))
+		for idx := range stats.Indices {
+			indices = append(indices, idx)
+		}
+		sort.Strings(indices)
+
+		for _, idx := range indices {
+			result = append(result, ClusterIndexStats{
+				Cluster:         name,
+				Index:           idx,
+				IndexStatsEntry: stats.Indices[idx],
+			})
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// IndexTemplate represents composable index template applied by Elasticsearch
+// to indices created with matching names.
+type IndexTemplate struct {
+	IndexPatterns []string       `json:"index_patterns"`        // Index name patterns (e.g., ["orders_*"])
+	Template      *IndexBody     `json:"template,omitempty"`    // Settings, mappings and aliases of matching indices
+	ComposedOf    []string       `json:"composed_of,omitempty"` // Component templates, optional
+	Priority      int            `json:"priority,omitempty"`    // Priority among overlapping templates, optional
+	Version       int            `json:"version,omitempty"`     // Template version, optional
+	Meta          map[string]any `json:"_meta,omitempty"`       // Arbitrary metadata, optional
+}
+
+// PutIndexTemplate creates or updates composable index template.
+func (c *Client) PutIndexTemplate(ctx context.Context, name string, template *IndexTemplate) error {
+	if name == "" {
+		return errors.New("template name is required")
+	}
+	if template == nil {
+		return errors.New("template is required")
+	}
+	if len(template.IndexPatterns) == 0 {
+		return errors.New("index patterns are required")
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("/_index_template/%s", name), nil, template, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "put_index_template", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// DeleteIndexTemplate deletes composable index template by name.
+func (c *Client) DeleteIndexTemplate(ctx context.Context, name string) error {
+	if name == "" {
+		return errors.New("template name is required")
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodDelete, fmt.Sprintf("/_index_template/%s", name), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "delete_index_template", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
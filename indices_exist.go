@@ -0,0 +1,104 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// IndexPresence tells on which registered clusters index exists.
+type IndexPresence struct {
+	Exists   bool     // Index exists on at least one cluster
+	Clusters []string // Clusters holding index, sorted; more than one means duplicate index
+}
+
+// IndicesExist checks which of named indices exist on registered clusters. Every cluster is asked
+// concurrently with one _cat/indices request, so it suits provisioning reconciliation of many indices.
+// Degraded clusters are skipped, so their indices are reported missing. Failure of a cluster is
+// returned as *MultiError together with presence found on other clusters.
+func (r *Registry) IndicesExist(ctx context.Context, names []string) (map[string]IndexPresence, error) {
+	result := make(map[string]IndexPresence, len(names))
+	for _, name := range names {
+		result[name] = IndexPresence{}
+	}
+	if len(names) == 0 {
+		return result, nil
+	}
+
+	clusters := r.ListClusters()
+	sort.Strings(clusters)
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = &MultiError{}
+	)
+	for _, cluster := range clusters {
+		client, err := r.GetTypedClient(cluster)
+		if IsDegraded(err) {
+			continue
+		}
+		if err != nil {
+			mu.Lock()
+			errs.Errors = append(errs.Errors, errors.Wrapf(err, "failed to get client for cluster %q", cluster))
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(cluster string, client *Client) {
+			defer wg.Done()
+			indices, err := client.listIndices(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs.Errors = append(errs.Errors, errors.Wrapf(err, "failed to list indices of cluster %q", cluster))
+				return
+			}
+			for _, index := range indices {
+				presence, ok := result[index]
+				if !ok {
+					continue
+				}
+				presence.Exists = true
+				presence.Clusters = append(presence.Clusters, cluster)
+				result[index] = presence
+			}
+		}(cluster, client)
+	}
+	wg.Wait()
+
+	for name, presence := range result {
+		sort.Strings(presence.Clusters)
+		result[name] = presence
+	}
+	return result, errs.errOrNil()
+}
+
+// listIndices returns names of all indices of cluster, including hidden ones.
+func (c *Client) listIndices(ctx context.Context) ([]string, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	query.Set("h", "index")
+	query.Set("expand_wildcards", "all")
+
+	var indices []catIndex
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, "/_cat/indices", query, nil, &indices)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "cat_indices", StatusCode: status, body: errBody}
+	}
+
+	result := make([]string, 0, len(indices))
+	for _, idx := range indices {
+		result = append(result, idx.Index)
+	}
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package esclient
+
+import (
+	"github.com/pkg/errors"
+)
+
+// defaultKnnK is number of nearest neighbours returned when KnnSearch.K is not set.
+const defaultKnnK = 10
+
+// maxKnnNumCandidates is maximum num_candidates accepted by Elasticsearch.
+const maxKnnNumCandidates = 10000
+
+// KnnSearch configures approximate kNN search on dense_vector field.
+// On shared indices company filter is added to Filter, so candidates are picked among company documents only.
+type KnnSearch struct {
+	Field         string         // dense_vector field name
+	QueryVector   []float32      // Query vector; dimensions must match field mapping
+	K             int            // Number of nearest neighbours to return (default: 10)
+	NumCandidates int            // Candidates considered per shard (default: 1.5*K, capped at 10000)
+	Filter        map[string]any // Query restricting candidate documents, optional
+	Similarity    *float64       // Minimum similarity of returned documents, optional
+	Boost         *float64       // Weight of kNN score when combined with query, optional
+}
+
+// body converts kNN search to ES knn section for cluster major version.
+// ES 8 before 8.12 requires k and num_candidates, so defaults are sent explicitly
+// unless cluster is known to be ES 9, which derives them from request size.
+func (k *KnnSearch) body(version int, companyID string) (map[string]any, error) {
+	if k.Field == "" {
+		return nil, errors.New("knn field is required")
+	}
+	if len(k.QueryVector) == 0 {
+		return nil, errors.New("knn query vector is required")
+	}
+	if k.K < 0 || k.NumCandidates < 0 {
+		return nil, errors.New("knn k and num_candidates must not be negative")
+	}
+	if k.NumCandidates > maxKnnNumCandidates {
+		return nil, errors.Errorf("knn num_candidates %d exceeds %d", k.NumCandidates, maxKnnNumCandidates)
+	}
+
+	numK, numCandidates := k.K, k.NumCandidates
+	if version != 9 {
+		if numK == 0 {
+			numK = defaultKnnK
+		}
+		if numCandidates == 0 {
+			numCandidates = min(numK+numK/2, maxKnnNumCandidates)
+		}
+	}
+	if numK > 0 && numCandidates > 0 && numCandidates < numK {
+		return nil, errors.Errorf("knn num_candidates %d is less than k %d", numCandidates, numK)
+	}
+
+	body := map[string]any{
+		"field":        k.Field,
+		"query_vector": k.QueryVector,
+	}
+	if numK > 0 {
+		body["k"] = numK
+	}
+	if numCandidates > 0 {
+		body["num_candidates"] = numCandidates
+	}
+	if k.Similarity != nil {
+		body["similarity"] = *k.Similarity
+	}
+	if k.Boost != nil {
+		body["boost"] = *k.Boost
+	}
+
+	switch {
+	case k.Filter != nil && companyID != "":
+		body["filter"] = []any{deepCopyMap(k.Filter), companyTermFilter(companyID)}
+	case companyID != "":
+		body["filter"] = companyTermFilter(companyID)
+	case k.Filter != nil:
+		body["filter"] = deepCopyMap(k.Filter)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,253 @@
+package esclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultLoadConcurrency = 16
+
+// LoadQuery is search replayed by RunLoad: captured production query (e.g., with its fingerprint
+// as Name) or synthetic template generating body of every request.
+type LoadQuery struct {
+	Name      string         `json:"name"`       // Name in report (default: index)
+	Index     string         `json:"index"`      // Index name or pattern
+	CompanyID string         `json:"company_id"` // Company of query; required for shared index, adds company filter
+	Body      map[string]any `json:"body"`       // Search body (JSON), used if Template is nil
+	Weight    int            `json:"weight"`     // Relative frequency among queries (default: 1)
+
+	// Template generates search body of every request for synthetic load (e.g., random term or price range).
+	Template func(rnd *rand.Rand) map[string]any `json:"-"`
+}
+
+// LoadConfig configures RunLoad. At least one of Duration and Requests is required.
+type LoadConfig struct {
+	Cluster     string        // Cluster under load (default: default cluster)
+	Queries     []LoadQuery   // Queries picked by weight for every request
+	Rate        float64       // Requests started per second
+	Duration    time.Duration // How long requests are started, optional
+	Requests    int           // Max number of requests started, optional
+	Concurrency int           // Max requests in flight (default: 16)
+	Seed        uint64        // Seed of query picking and templates, 0 for random
+}
+
+// LatencyStats summarizes request latencies.
+type LatencyStats struct {
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// LoadQueryReport is result of one query of load run.
+type LoadQueryReport struct {
+	Requests int          `json:"requests"`
+	Errors   int          `json:"errors"`
+	Latency  LatencyStats `json:"latency"` // Latency of all requests, including failed ones
+}
+
+// LoadReport is result of load run.
+type LoadReport struct {
+	Cluster    string                     `json:"cluster"`
+	Elapsed    time.Duration              `json:"elapsed"`
+	Requests   int                        `json:"requests"`
+	Errors     int                        `json:"errors"`
+	Missed     int                        `json:"missed"`     // Requests not started since Concurrency requests were in flight
+	Throughput float64                    `json:"throughput"` // Completed requests per second
+	Latency    LatencyStats               `json:"latency"`
+	Queries    map[string]LoadQueryReport `json:"queries"` // By query name
+	LastError  string                     `json:"last_error,omitempty"`
+}
+
+// RunLoad replays queries against cluster at fixed rate through typed client of registry, so
+// capacity tests exercise the same middleware, query mutation and routing as production.
+// Load is open-loop: requests start on schedule regardless of latency, and those that would exceed
+// Concurrency are counted as missed instead of delaying schedule. Requests in flight when Duration
+// ends are awaited. Canceling ctx stops run and returns report collected so far.
+func (r *Registry) RunLoad(ctx context.Context, cfg LoadConfig) (*LoadReport, error) {
+	if len(cfg.Queries) == 0 {
+		return nil, errors.New("at least one query is required")
+	}
+	if cfg.Rate <= 0 {
+		return nil, errors.New("rate must be positive")
+	}
+	if cfg.Duration <= 0 && cfg.Requests <= 0 {
+		return nil, errors.New("duration or number of requests is required")
+	}
+	for i, q := range cfg.Queries {
+		if q.Index == "" {
+			return nil, errors.Errorf("query %d has no index", i)
+		}
+		if q.Body == nil && q.Template == nil {
+			return nil, errors.Errorf("query %d has neither body nor template", i)
+		}
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultLoadConcurrency
+	}
+	if cfg.Cluster == "" {
+		cfg.Cluster = r.defaultName
+	}
+
+	client, err := r.GetTypedClient(cfg.Cluster)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get client for cluster %q", cfg.Cluster)
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rnd := rand.New(rand.NewPCG(seed, seed))
+	weights := make([]int, len(cfg.Queries))
+	total := 0
+	for i, q := range cfg.Queries {
+		weights[i] = max(q.Weight, 1)
+		total += weights[i]
+	}
+
+	runCtx := ctx
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		latencies = make(map[string][]time.Duration)
+		report    = &LoadReport{Cluster: cfg.Cluster, Queries: make(map[string]LoadQueryReport)}
+		slots     = make(chan struct{}, cfg.Concurrency)
+	)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+	defer ticker.Stop()
+
+	start := time.Now()
+schedule:
+	for cfg.Requests <= 0 || report.Requests < cfg.Requests {
+		select {
+		case <-runCtx.Done():
+			break schedule
+		case <-ticker.C:
+		}
+		select {
+		case slots <- struct{}{}:
+		default:
+			report.Missed++
+			continue
+		}
+
+		// Picking and templates run here, since rnd is not safe for concurrent use
+		pick := rnd.IntN(total)
+		i := 0
+		for pick >= weights[i] {
+			pick -= weights[i]
+			i++
+		}
+		q := cfg.Queries[i]
+		body := q.Body
+		if q.Template != nil {
+			body = q.Template(rnd)
+		}
+		name := q.Name
+		if name == "" {
+			name = q.Index
+		}
+		report.Requests++
+
+		wg.Add(1)
+		go func(name string, req *SearchRequest) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			begin := time.Now()
+			_, err := client.Search(ctx, req)
+			latency := time.Since(begin)
+
+			mu.Lock()
+			defer mu.Unlock()
+			latencies[name] = append(latencies[name], latency)
+			stats := report.Queries[name]
+			stats.Requests++
+			if err != nil {
+				stats.Errors++
+				report.Errors++
+				report.LastError = err.Error()
+			}
+			report.Queries[name] = stats
+		}(name, &SearchRequest{Index: q.Index, Query: body, CompanyID: q.CompanyID})
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+
+	var all []time.Duration
+	for name, values := range latencies {
+		stats := report.Queries[name]
+		stats.Latency = latencyStats(values)
+		report.Queries[name] = stats
+		all = append(all, values...)
+	}
+	report.Latency = latencyStats(all)
+	if report.Elapsed > 0 {
+		report.Throughput = float64(len(all)) / report.Elapsed.Seconds()
+	}
+	return report, nil
+}
+
+// ReadLoadQueries reads captured queries from JSON lines, one LoadQuery per line.
+// Empty lines are skipped.
+func ReadLoadQueries(r io.Reader) ([]LoadQuery, error) {
+	var queries []LoadQuery
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var q LoadQuery
+		if err := json.Unmarshal(scanner.Bytes(), &q); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode query on line %d", line)
+		}
+		queries = append(queries, q)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read queries")
+	}
+	return queries, nil
+}
+
+// latencyStats returns mean, nearest-rank percentiles and max of latencies.
+func latencyStats(latencies []time.Duration) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	percentile := func(p float64) time.Duration {
+		rank := int(math.Ceil(p * float64(len(sorted))))
+		return sorted[max(rank, 1)-1]
+	}
+	return LatencyStats{
+		Mean: sum / time.Duration(len(sorted)),
+		P50:  percentile(0.50),
+		P90:  percentile(0.90),
+		P99:  percentile(0.99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
@@ -1,6 +1,10 @@
 package esclient
 
-import "context"
+import (
+	"context"
+	"sync"
+	"time"
+)
 
 // Logger interface for debug logging.
 // Compatible with github.com/billz-2/packages/pkg/logger interface.
@@ -23,3 +27,137 @@ func safeLogger(log Logger) Logger {
 	}
 	return log
 }
+
+// WarnLogger is optional extension of Logger for warnings.
+// If provided logger implements it, warnings (e.g., resolver fallback) are logged at warn level,
+// otherwise they are logged at debug level.
+type WarnLogger interface {
+	WarnWithCtx(ctx context.Context, msg string, fields ...any)
+}
+
+// logWarn logs warning via WarnLogger if supported, falling back to debug level.
+func logWarn(ctx context.Context, log Logger, msg string, fields ...any) {
+	if wl, ok := log.(WarnLogger); ok {
+		wl.WarnWithCtx(ctx, msg, fields...)
+		return
+	}
+	log.DebugWithCtx(ctx, msg, fields...)
+}
+
+// LogSampling configures SampledLogger. Zero value logs every line.
+type LogSampling struct {
+	Every    int           // Log every Nth debug line of same message (default: 1, every line)
+	Burst    int           // Max debug lines of same message per Interval after Every is applied, 0 is unlimited
+	Interval time.Duration // Window of Burst (default: 1s)
+	Clock    Clock         // Time source of Interval (default: system clock)
+}
+
+// SampledLogger limits debug output per message, so debug logging can be enabled in production
+// during incident. Warnings and debug lines carrying error (non-nil "error" field or status_code >= 400)
+// are always logged.
+type SampledLogger struct {
+	log Logger
+	cfg LogSampling
+
+	mu      sync.Mutex
+	counts  map[string]*sampleCounter // message -> counter
+	dropped int64
+}
+
+// sampleCounter is sampling state of single message.
+type sampleCounter struct {
+	seen        int64
+	windowStart time.Time
+	inWindow    int
+}
+
+// NewSampledLogger wraps logger with sampling. Nil logger disables logging.
+func NewSampledLogger(log Logger, cfg LogSampling) *SampledLogger {
+	if cfg.Every <= 0 {
+		cfg.Every = 1
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = systemClock{}
+	}
+	return &SampledLogger{log: safeLogger(log), cfg: cfg, counts: make(map[string]*sampleCounter)}
+}
+
+// Debug logs line if it passes sampling.
+func (l *SampledLogger) Debug(msg string, fields ...any) {
+	if l.sample(msg, fields) {
+		l.log.Debug(msg, fields...)
+	}
+}
+
+// DebugWithCtx logs line if it passes sampling.
+func (l *SampledLogger) DebugWithCtx(ctx context.Context, msg string, fields ...any) {
+	if l.sample(msg, fields) {
+		l.log.DebugWithCtx(ctx, msg, fields...)
+	}
+}
+
+// WarnWithCtx logs warning without sampling.
+func (l *SampledLogger) WarnWithCtx(ctx context.Context, msg string, fields ...any) {
+	logWarn(ctx, l.log, msg, fields...)
+}
+
+// Dropped returns number of debug lines dropped by sampling.
+func (l *SampledLogger) Dropped() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.dropped
+}
+
+// sample reports whether debug line should be logged.
+func (l *SampledLogger) sample(msg string, fields []any) bool {
+	if hasError(fields) {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	counter, ok := l.counts[msg]
+	if !ok {
+		counter = &sampleCounter{}
+		l.counts[msg] = counter
+	}
+	counter.seen++
+	if (counter.seen-1)%int64(l.cfg.Every) != 0 {
+		l.dropped++
+		return false
+	}
+
+	if l.cfg.Burst > 0 {
+		now := l.cfg.Clock.Now()
+		if now.Sub(counter.windowStart) >= l.cfg.Interval {
+			counter.windowStart, counter.inWindow = now, 0
+		}
+		if counter.inWindow >= l.cfg.Burst {
+			l.dropped++
+			return false
+		}
+		counter.inWindow++
+	}
+	return true
+}
+
+// hasError reports whether log fields carry error: non-nil "error" field or status_code >= 400.
+func hasError(fields []any) bool {
+	for _, field := range fields {
+		m, ok := field.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err, ok := m["error"]; ok && err != nil {
+			return true
+		}
+		if status, ok := m["status_code"].(int); ok && status >= 400 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package esclient
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultRetryBackoff = 100 * time.Millisecond
+
+// RetryPolicy configures retries of failed requests.
+// Requests are retried on 429/503 responses and failures to connect; on other connection errors
+// and 502/504 responses only idempotent requests (GET, HEAD and searches) are retried.
+// Retry-After header of response is honored if it asks to wait longer than backoff.
+type RetryPolicy struct {
+	MaxAttempts int           // Max number of attempts including the first one
+	Backoff     time.Duration // Delay before first retry, doubled on every next one (default: 100ms)
+	MaxBackoff  time.Duration // Cap of delay between attempts, including Retry-After, optional
+	Jitter      float64       // Fraction of delay randomized, 0..1 (e.g., 0.2 waits 80%..120% of delay), optional
+}
+
+// Metrics receives measurements of every request made by Client.
+type Metrics interface {
+	ObserveRequest(ctx context.Context, method, path string, statusCode int, duration time.Duration, err error)
+}
+
+// DeadlineMetrics is optional extension of Metrics. If implemented, it receives
+// fraction of context deadline consumed by every request with deadline (1.0 means whole budget).
+type DeadlineMetrics interface {
+	ObserveDeadlineUsage(ctx context.Context, method, path string, used float64)
+}
+
+// deadlineWarnThreshold is fraction of context deadline above which request is logged as near timeout.
+const deadlineWarnThreshold = 0.8
+
+// Tracer starts span for every request made by Client.
+// Returned function ends span with response status code (0 on error) and error.
+type Tracer interface {
+	StartSpan(ctx context.Context, method, path string) (context.Context, func(statusCode int, err error))
+}
+
+// retryClient retries failed requests according to policy.
+type retryClient struct {
+	es     ESClient
+	policy RetryPolicy
+	clock  Clock
+}
+
+// Do executes request, retrying on connection errors and retryable statuses.
+// Requests with body are retried only if body can be replayed (GetBody is set).
+// Response is returned without retry if delay would outlast context deadline.
+func (rc *retryClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	backoff := rc.policy.Backoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := rc.es.Do(ctx, req)
+		if attempt >= rc.policy.MaxAttempts || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		delay := rc.delay(backoff, resp)
+		if deadline, ok := ctx.Deadline(); ok && rc.clock.Now().Add(delay).After(deadline) {
+			return resp, err
+		}
+		if !rewind(req) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close() //nolint:errcheck
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-rc.clock.After(delay):
+		}
+		backoff *= 2
+	}
+}
+
+// delay returns wait before next attempt: backoff or longer Retry-After of response,
+// capped by max backoff and randomized by jitter.
+func (rc *retryClient) delay(backoff time.Duration, resp *http.Response) time.Duration {
+	delay := backoff
+	if resp != nil {
+		if after, ok := retryAfter(resp.Header.Get("Retry-After"), rc.clock.Now()); ok && after > delay {
+			delay = after
+		}
+	}
+	if rc.policy.MaxBackoff > 0 && delay > rc.policy.MaxBackoff {
+		delay = rc.policy.MaxBackoff
+	}
+	if jitter := min(rc.policy.Jitter, 1); jitter > 0 {
+		delay = time.Duration(float64(delay) * (1 - jitter + 2*jitter*rand.Float64()))
+	}
+	return delay
+}
+
+// retryAfter parses Retry-After header value given in seconds or as HTTP date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// retryable reports whether failed request can be retried. 429 and 503 mean request was rejected
+// before being applied, as do dial errors. Other connection errors and 502/504 may hide applied
+// request whose response was lost, so only idempotent requests are retried on them: retrying
+// bulk, by-query or conditional writes could duplicate items or re-run scripts.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return idempotent(req)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(req)
+	default:
+		return false
+	}
+}
+
+// idempotent reports whether request can be repeated without changing result: GET and HEAD
+// requests and searches (POST with search body, see operationClass).
+func idempotent(req *http.Request) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+	return req.Method == http.MethodPost && operationClass(req) == OperationSearch
+}
+
+// rewind resets request body for retry; returns false if body cannot be replayed.
+func rewind(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
+}
+
+// metricsClient reports request measurements to Metrics.
+type metricsClient struct {
+	es      ESClient
+	metrics Metrics
+	clock   Clock
+}
+
+// Do executes request and observes its duration and outcome.
+func (mc *metricsClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	start := mc.clock.Now()
+	resp, err := mc.es.Do(ctx, req)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	mc.metrics.ObserveRequest(ctx, req.Method, req.URL.Path, status, mc.clock.Now().Sub(start), err)
+
+	return resp, err
+}
+
+// deadlineClient measures how much of context deadline requests consume.
+type deadlineClient struct {
+	es      ESClient
+	log     Logger
+	metrics DeadlineMetrics // optional
+	clock   Clock
+}
+
+// Do executes request and reports deadline usage if context has deadline.
+func (dc *deadlineClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return dc.es.Do(ctx, req)
+	}
+
+	start := dc.clock.Now()
+	budget := deadline.Sub(start)
+	resp, err := dc.es.Do(ctx, req)
+	if budget <= 0 {
+		return resp, err
+	}
+
+	elapsed := dc.clock.Now().Sub(start)
+	used := float64(elapsed) / float64(budget)
+	if dc.metrics != nil {
+		dc.metrics.ObserveDeadlineUsage(ctx, req.Method, req.URL.Path, used)
+	}
+	if used > deadlineWarnThreshold {
+		logWarn(ctx, dc.log, "elasticsearch request near deadline", map[string]interface{}{
+			"method":       req.Method,
+			"path":         req.URL.Path,
+			"used_percent": int(used * 100),
+			"elapsed":      elapsed.String(),
+			"budget":       budget.String(),
+		})
+	}
+
+	return resp, err
+}
+
+// tracingClient wraps every request into span.
+type tracingClient struct {
+	es     ESClient
+	tracer Tracer
+}
+
+// Do executes request within span.
+func (tc *tracingClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	ctx, end := tc.tracer.StartSpan(ctx, req.Method, req.URL.Path)
+	resp, err := tc.es.Do(ctx, req.WithContext(ctx))
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	end(status, err)
+
+	return resp, err
+}
+
+// withMiddleware wraps ESClient with configured middleware. From outermost to innermost:
+// default operation deadline, timeout (both cover all retries), tracing, deadline usage, metrics,
+// request statistics, read fallback, retry, headers.
+func (c *Client) withMiddleware(es ESClient) ESClient {
+	es = withHeaders(es, c.headers)
+	if c.retry != nil && c.retry.MaxAttempts > 1 {
+		es = &retryClient{es: es, policy: *c.retry, clock: c.clock}
+	}
+	if c.fallback != nil {
+		es = &fallbackClient{
+			es:         es,
+			fallback:   c.fallback,
+			fallbackES: withHeaders(c.fallback.es, c.headers),
+			log:        c.log,
+			clock:      c.clock,
+		}
+	}
+	es = &statsClient{es: es, stats: c.stats, clock: c.clock}
+	if c.metrics != nil {
+		es = &metricsClient{es: es, metrics: c.metrics, clock: c.clock}
+	}
+	deadlineMetrics, _ := c.metrics.(DeadlineMetrics)
+	es = &deadlineClient{es: es, log: c.log, metrics: deadlineMetrics, clock: c.clock}
+	if c.tracer != nil {
+		es = &tracingClient{es: es, tracer: c.tracer}
+	}
+	es = withTimeout(es, c.timeout)
+	return withOperationTimeouts(es, c.opTimeouts)
+}
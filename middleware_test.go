@@ -0,0 +1,382 @@
+package esclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMetrics struct {
+	statuses []int
+}
+
+func (m *recordingMetrics) ObserveRequest(ctx context.Context, method, path string, statusCode int, duration time.Duration, err error) {
+	m.statuses = append(m.statuses, statusCode)
+}
+
+type recordingTracer struct {
+	spans []string
+}
+
+func (tr *recordingTracer) StartSpan(ctx context.Context, method, path string) (context.Context, func(int, error)) {
+	tr.spans = append(tr.spans, method+" "+path)
+	return ctx, func(int, error) {}
+}
+
+// countingCodec delegates to encoding/json and counts calls.
+type countingCodec struct {
+	marshals, unmarshals int
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func TestNewClient_Options(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{status: http.StatusServiceUnavailable},
+		{body: `{"count": 7}`},
+	}}
+	metrics := &recordingMetrics{}
+	tracer := &recordingTracer{}
+	codec := &countingCodec{}
+
+	client, err := NewClient(es, "http://localhost:9200",
+		WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+		WithMetrics(metrics),
+		WithTracer(tracer),
+		WithCodec(codec),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.Count(context.Background(), &CountRequest{
+		Index:     "orders",
+		CompanyID: "c1",
+		Query:     map[string]any{"query": map[string]any{"match_all": map[string]any{}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 7, resp.Count)
+
+	// Body is replayed on retry
+	require.Len(t, es.bodies, 2)
+	assert.Equal(t, es.bodies[0], es.bodies[1])
+	assert.NotEmpty(t, es.bodies[1])
+
+	// Metrics and tracing observe logical request, retries are internal
+	assert.Equal(t, []int{http.StatusOK}, metrics.statuses)
+	assert.Equal(t, []string{"POST /orders/_count"}, tracer.spans)
+	assert.Equal(t, 1, codec.marshals)
+	assert.Equal(t, 1, codec.unmarshals)
+}
+
+type slowES struct {
+	delay time.Duration
+}
+
+func (s *slowES) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	time.Sleep(s.delay)
+	return (&fakeES{}).Do(ctx, req)
+}
+
+type deadlineRecorder struct {
+	recordingMetrics
+	used []float64
+}
+
+func (d *deadlineRecorder) ObserveDeadlineUsage(ctx context.Context, method, path string, used float64) {
+	d.used = append(d.used, used)
+}
+
+type warnRecorder struct {
+	noopLogger
+	warnings []string
+}
+
+func (w *warnRecorder) WarnWithCtx(ctx context.Context, msg string, fields ...any) {
+	w.warnings = append(w.warnings, msg)
+}
+
+func TestClient_DeadlineUsage(t *testing.T) {
+	metrics := &deadlineRecorder{}
+	log := &warnRecorder{}
+	client, err := NewClient(&slowES{delay: 45 * time.Millisecond}, "http://localhost:9200", WithMetrics(metrics), WithLogger(log))
+	require.NoError(t, err)
+
+	// No deadline: nothing observed
+	require.NoError(t, client.Refresh(context.Background()))
+	assert.Empty(t, metrics.used)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.NoError(t, client.Refresh(ctx))
+
+	require.Len(t, metrics.used, 1)
+	assert.Greater(t, metrics.used[0], deadlineWarnThreshold)
+	assert.Equal(t, []string{"elasticsearch request near deadline"}, log.warnings)
+}
+
+func TestClient_BodyLimits(t *testing.T) {
+	es := &fakeES{}
+	client, err := NewClient(es, "http://localhost:9200", WithBodyLimits(BodyLimits{MaxSize: 1000, GzipThreshold: 100}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Small body is sent as is
+	_, err = client.Search(ctx, &SearchRequest{Index: "orders", CompanyID: "c1"})
+	require.NoError(t, err)
+	assert.Empty(t, es.requests[0].Header.Get("Content-Encoding"))
+
+	// Body above threshold is compressed
+	ids := make([]any, 30)
+	for i := range ids {
+		ids[i] = "id"
+	}
+	query := map[string]any{"query": map[string]any{"ids": map[string]any{"values": ids}}}
+	_, err = client.Search(ctx, &SearchRequest{Index: "orders", CompanyID: "c1", Query: query})
+	require.NoError(t, err)
+	assert.Equal(t, "gzip", es.requests[1].Header.Get("Content-Encoding"))
+	zr, err := gzip.NewReader(bytes.NewReader([]byte(es.bodies[1])))
+	require.NoError(t, err)
+	plain, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Contains(t, string(plain), `"ids":{"values":["id"`)
+
+	// Body above max size is rejected
+	query["query"] = map[string]any{"ids": map[string]any{"values": []any{strings.Repeat("x", 1000)}}}
+	_, err = client.Search(ctx, &SearchRequest{Index: "orders", CompanyID: "c1", Query: query})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrBodyTooLarge))
+	assert.Len(t, es.requests, 2)
+}
+
+func TestClient_Compression(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, err := zw.Write([]byte(`{"count": 7}`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	es := &fakeES{response: gz.String(), header: http.Header{"Content-Encoding": []string{"gzip"}}}
+	registry := NewRegistry("main")
+	registry.configs["main"] = ClusterConfig{GzipThreshold: 10}
+	registry.byName["main"] = Entry{Name: "main", Version: 8, BaseURL: "http://localhost:9200", ES: es}
+	client, err := registry.GetTypedClient("main")
+	require.NoError(t, err)
+
+	resp, err := client.Count(context.Background(), &CountRequest{
+		Index: "orders_01234567-89ab-cdef-0123-456789abcdef",
+		Query: map[string]any{"query": map[string]any{"match": map[string]any{"status": "new"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 7, resp.Count)
+	assert.Equal(t, "gzip", es.requests[0].Header.Get("Accept-Encoding"))
+	assert.Equal(t, "gzip", es.requests[0].Header.Get("Content-Encoding"))
+}
+
+func TestClient_RetryBackoff_Clock(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{status: http.StatusServiceUnavailable},
+		{status: http.StatusServiceUnavailable},
+		{body: `{"count": 3}`},
+	}}
+	clock := NewFakeClock(time.Now())
+	client, err := NewClient(es, "http://localhost:9200",
+		WithClock(clock),
+		WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Second}),
+	)
+	require.NoError(t, err)
+
+	done := make(chan *CountResponse)
+	go func() {
+		resp, err := client.Count(context.Background(), &CountRequest{Index: "orders_shared", CompanyID: "c1"})
+		assert.NoError(t, err)
+		done <- resp
+	}()
+
+	// Backoff doubles: 1s, then 2s
+	for _, backoff := range []time.Duration{time.Second, 2 * time.Second} {
+		require.Eventually(t, func() bool { return clock.Waiters() == 1 }, time.Second, time.Millisecond)
+		clock.Advance(backoff)
+	}
+
+	resp := <-done
+	assert.Equal(t, 3, resp.Count)
+	assert.Len(t, es.paths, 3)
+}
+
+func TestRetryClient_Delay(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rc := &retryClient{policy: RetryPolicy{MaxBackoff: 10 * time.Second}, clock: NewFakeClock(now)}
+	withRetryAfter := func(value string) *http.Response {
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	assert.Equal(t, time.Second, rc.delay(time.Second, nil))
+	// Longer Retry-After wins, in seconds or as HTTP date
+	assert.Equal(t, 3*time.Second, rc.delay(time.Second, withRetryAfter("3")))
+	assert.Equal(t, 5*time.Second, rc.delay(time.Second, withRetryAfter(now.Add(5*time.Second).Format(http.TimeFormat))))
+	assert.Equal(t, 2*time.Second, rc.delay(2*time.Second, withRetryAfter("1")))
+	// Capped by max backoff
+	assert.Equal(t, 10*time.Second, rc.delay(time.Second, withRetryAfter("60")))
+	assert.Equal(t, 10*time.Second, rc.delay(time.Minute, nil))
+
+	rc.policy.Jitter = 0.5
+	for range 100 {
+		delay := rc.delay(2*time.Second, nil)
+		assert.GreaterOrEqual(t, delay, time.Second)
+		assert.LessOrEqual(t, delay, 3*time.Second)
+	}
+
+	search := httptest.NewRequest(http.MethodPost, "/orders/_search", nil)
+	assert.True(t, retryable(search, &http.Response{StatusCode: http.StatusBadGateway}, nil))
+	assert.True(t, retryable(search, &http.Response{StatusCode: http.StatusGatewayTimeout}, nil))
+	assert.False(t, retryable(search, &http.Response{StatusCode: http.StatusInternalServerError}, nil))
+	assert.True(t, retryable(search, nil, errors.New("connection reset by peer")))
+}
+
+func TestRetryable_NonIdempotent(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	lost := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/_bulk", nil),
+		httptest.NewRequest(http.MethodPost, "/orders/_update_by_query", nil),
+		httptest.NewRequest(http.MethodPost, "/orders/_delete_by_query", nil),
+		httptest.NewRequest(http.MethodPost, "/_reindex", nil),
+		httptest.NewRequest(http.MethodPut, "/orders/_split/orders_split", nil),
+		httptest.NewRequest(http.MethodPut, "/orders/_doc/1?op_type=create", nil),
+		httptest.NewRequest(http.MethodPut, "/orders/_doc/1?if_seq_no=3&if_primary_term=1", nil),
+	} {
+		name := req.Method + " " + req.URL.String()
+		// Rejected before being applied
+		assert.True(t, retryable(req, &http.Response{StatusCode: http.StatusTooManyRequests}, nil), name)
+		assert.True(t, retryable(req, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil), name)
+		assert.True(t, retryable(req, nil, refused), name)
+		// May have been applied with response lost
+		assert.False(t, retryable(req, &http.Response{StatusCode: http.StatusBadGateway}, nil), name)
+		assert.False(t, retryable(req, &http.Response{StatusCode: http.StatusGatewayTimeout}, nil), name)
+		assert.False(t, retryable(req, nil, lost), name)
+	}
+
+	es := &scriptedES{responses: []scriptedResponse{{status: http.StatusGatewayTimeout}, {body: `{"items": []}`}}}
+	client, err := NewClient(es, "http://localhost:9200", WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+	require.NoError(t, err)
+	_, err = client.Bulk(context.Background(), &BulkRequest{Index: "orders", Body: strings.NewReader("{}\n")})
+	assert.Error(t, err)
+	assert.Len(t, es.paths, 1)
+}
+
+func TestClient_Retry_DeadlineExceeded(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{{status: http.StatusTooManyRequests}}}
+	client, err := NewClient(es, "http://localhost:9200", WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Minute}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Backoff outlasts deadline, so 429 is returned at once instead of waiting
+	_, err = client.Count(ctx, &CountRequest{Index: "orders_shared", CompanyID: "c1"})
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	assert.Len(t, es.paths, 1)
+}
+
+func TestClient_OperationTimeouts(t *testing.T) {
+	es := &fakeES{response: `{"count": 1}`}
+	client, err := NewClient(es, "http://localhost:9200", WithOperationTimeouts(OperationTimeouts{
+		Search: 5 * time.Second,
+		Admin:  time.Minute,
+	}))
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Count(context.Background(), &CountRequest{Index: "orders_shared", CompanyID: "c1"})
+	require.NoError(t, err)
+	deadline, ok := es.requests[0].Context().Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, start.Add(5*time.Second), deadline, time.Second)
+
+	// Explicit deadline of call is kept
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	_, err = client.Count(ctx, &CountRequest{Index: "orders_shared", CompanyID: "c1"})
+	require.NoError(t, err)
+	deadline, _ = es.requests[1].Context().Deadline()
+	assert.WithinDuration(t, start.Add(time.Hour), deadline, time.Second)
+
+	// Bulk class has no timeout
+	_, err = client.CreateDocument(context.Background(), &CreateDocumentRequest{
+		Index:      "orders",
+		DocumentID: "1",
+		Body:       strings.NewReader(`{}`),
+	})
+	require.NoError(t, err)
+	_, ok = es.requests[2].Context().Deadline()
+	assert.False(t, ok)
+
+	class := func(method, path string) string {
+		req, err := http.NewRequest(method, "http://localhost:9200"+path, nil)
+		require.NoError(t, err)
+		return operationClass(req)
+	}
+	assert.Equal(t, OperationAdmin, class(http.MethodPut, "/orders_c1/_mapping"))
+	assert.Equal(t, OperationSearch, class(http.MethodGet, "/orders_c1/_doc/1"))
+	assert.Equal(t, OperationBulk, class(http.MethodPut, "/orders_c1/_doc/1"))
+	assert.Equal(t, OperationBulk, class(http.MethodPost, "/_bulk"))
+}
+
+type debugRecorder struct {
+	lines []string
+}
+
+func (d *debugRecorder) Debug(msg string, fields ...any) {
+	d.lines = append(d.lines, msg)
+}
+
+func (d *debugRecorder) DebugWithCtx(ctx context.Context, msg string, fields ...any) {
+	d.lines = append(d.lines, msg)
+}
+
+func TestSampledLogger(t *testing.T) {
+	ctx := context.Background()
+	inner := &debugRecorder{}
+	clock := NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	log := NewSampledLogger(inner, LogSampling{Every: 2, Burst: 2, Interval: time.Second, Clock: clock})
+
+	// Every 2nd line, at most 2 per second
+	for i := 0; i < 10; i++ {
+		log.DebugWithCtx(ctx, "request", map[string]interface{}{"path": "/orders"})
+	}
+	assert.Len(t, inner.lines, 2)
+	assert.Equal(t, int64(8), log.Dropped())
+
+	// Errors and warnings are never sampled
+	log.DebugWithCtx(ctx, "request", map[string]interface{}{"error": "timeout"})
+	log.DebugWithCtx(ctx, "response", map[string]interface{}{"status_code": 503})
+	log.WarnWithCtx(ctx, "fallback")
+	assert.Len(t, inner.lines, 5)
+
+	// Burst window resets
+	clock.Advance(time.Second)
+	log.DebugWithCtx(ctx, "request", nil)
+	log.DebugWithCtx(ctx, "request", nil)
+	assert.Len(t, inner.lines, 6)
+}
@@ -0,0 +1,235 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MigrationPhase is a stage of index migration.
+type MigrationPhase string
+
+const (
+	MigrationPending   MigrationPhase = "pending"   // Migration not started
+	MigrationCopying   MigrationPhase = "copying"   // Documents are being reindexed into destination
+	MigrationVerifying MigrationPhase = "verifying" // Destination document count is being compared with source
+	MigrationCompleted MigrationPhase = "completed" // Migration finished successfully
+	MigrationFailed    MigrationPhase = "failed"    // Migration stopped with error
+)
+
+// MigrationRequest describes migration of index (or company documents of shared index) between clusters.
+type MigrationRequest struct {
+	CompanyID  string          // Company being migrated; on shared source index only its documents are copied
+	SrcCluster string          // Source cluster name
+	SrcIndex   string          // Source index name
+	DstCluster string          // Destination cluster name
+	DstIndex   string          // Destination index name
+	Reindex    *ReindexOptions // Reindex options, optional
+}
+
+// MigrationProgress is a snapshot of migration status.
+type MigrationProgress struct {
+	CompanyID string         `json:"company_id,omitempty"`
+	Phase     MigrationPhase `json:"phase"`
+	Total     int64          `json:"total"`               // Documents to copy; 0 until reindex task reports it
+	Copied    int64          `json:"copied"`              // Documents processed by reindex task
+	Rate      float64        `json:"rate"`                // Documents per second since start
+	ETA       time.Duration  `json:"eta"`                 // Estimated remaining copy time; 0 if unknown
+	StartedAt time.Time      `json:"started_at,omitzero"` // Start of migration
+	UpdatedAt time.Time      `json:"updated_at,omitzero"` // Time of last progress update
+	Error     string         `json:"error,omitempty"`     // Failure reason if Phase is MigrationFailed
+}
+
+// Migrator runs migration and tracks its progress.
+// Progress can be queried from other goroutines via Progress while Run is in flight,
+// and is streamed to onProgress callback on every change.
+type Migrator struct {
+	registry   *Registry
+	req        MigrationRequest
+	onProgress func(MigrationProgress)
+	clock      Clock
+
+	mu       sync.Mutex
+	progress MigrationProgress
+}
+
+// NewMigrator creates migrator of req. onProgress is optional and is called synchronously
+// from Run, so it must not block.
+func NewMigrator(registry *Registry, req MigrationRequest, onProgress func(MigrationProgress)) *Migrator {
+	return &Migrator{
+		registry:   registry,
+		req:        req,
+		onProgress: onProgress,
+		clock:      systemClock{},
+		progress:   MigrationProgress{CompanyID: req.CompanyID, Phase: MigrationPending},
+	}
+}
+
+// Progress returns current migration progress.
+func (m *Migrator) Progress() MigrationProgress {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.progress
+}
+
+// Run copies documents with ReindexAcrossClusters and verifies that destination
+// holds at least as many matching documents as source.
+func (m *Migrator) Run(ctx context.Context) (*ReindexResult, error) {
+	result, err := m.run(ctx)
+	if err != nil {
+		m.update(func(p *MigrationProgress) {
+			p.Phase = MigrationFailed
+			p.ETA = 0
+			p.Error = err.Error()
+		})
+		return nil, err
+	}
+
+	m.update(func(p *MigrationProgress) {
+		p.Phase = MigrationCompleted
+	})
+	return result, nil
+}
+
+func (m *Migrator) run(ctx context.Context) (*ReindexResult, error) {
+	src, err := m.registry.GetTypedClient(m.req.SrcCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get source cluster")
+	}
+	dst, err := m.registry.GetTypedClient(m.req.DstCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get destination cluster")
+	}
+	m.clock = dst.clock
+
+	opts := ReindexOptions{}
+	if m.req.Reindex != nil {
+		opts = *m.req.Reindex
+	}
+	srcQuery, err := m.companyQuery(opts.Query, m.req.SrcIndex)
+	if err != nil {
+		return nil, err
+	}
+	opts.Query = srcQuery
+	onPoll := opts.OnPoll
+	opts.OnPoll = func(task *GetTaskResponse) {
+		if !task.Completed {
+			// Completed task is accounted from reindex result
+			m.update(func(p *MigrationProgress) {
+				p.copied(task.Task.Status, m.clock.Now())
+			})
+		}
+		if onPoll != nil {
+			onPoll(task)
+		}
+	}
+
+	m.update(func(p *MigrationProgress) {
+		p.Phase = MigrationCopying
+		p.StartedAt = m.clock.Now()
+	})
+
+	result, err := m.registry.ReindexAcrossClusters(ctx, m.req.SrcCluster, m.req.SrcIndex, m.req.DstCluster, m.req.DstIndex, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	m.update(func(p *MigrationProgress) {
+		p.Phase = MigrationVerifying
+		p.Total = int64(result.Total)
+		p.Copied = int64(result.Total)
+		p.ETA = 0
+	})
+
+	dstQuery, err := m.companyQuery(nil, m.req.DstIndex)
+	if err != nil {
+		return nil, err
+	}
+	srcCount, err := src.countAll(ctx, m.req.SrcIndex, srcQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count source documents")
+	}
+	dstCount, err := dst.countAll(ctx, m.req.DstIndex, dstQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count destination documents")
+	}
+	if dstCount < srcCount {
+		return nil, errors.Errorf("destination index %s has %d documents, source index %s has %d",
+			m.req.DstIndex, dstCount, m.req.SrcIndex, srcCount)
+	}
+
+	return result, nil
+}
+
+// companyQuery returns query limited to migrated company on shared index.
+func (m *Migrator) companyQuery(query map[string]any, index string) (map[string]any, error) {
+	if m.req.CompanyID == "" || DetectIndexTarget(index) != IndexTargetShared {
+		return query, nil
+	}
+
+	body := map[string]any{}
+	if query != nil {
+		body["query"] = deepCopyMap(query)
+	}
+	if err := NewQueryMutator().InjectCompanyFilter(body, m.req.CompanyID, IndexTargetShared); err != nil {
+		return nil, err
+	}
+	return body["query"].(map[string]any), nil
+}
+
+// update applies fn to progress under lock and streams the result to callback.
+func (m *Migrator) update(fn func(p *MigrationProgress)) {
+	m.mu.Lock()
+	fn(&m.progress)
+	m.progress.UpdatedAt = m.clock.Now()
+	snapshot := m.progress
+	m.mu.Unlock()
+
+	if m.onProgress != nil {
+		m.onProgress(snapshot)
+	}
+}
+
+// copied updates copy progress from reindex task status.
+func (p *MigrationProgress) copied(status map[string]interface{}, now time.Time) {
+	p.Copied = 0
+	for _, key := range []string{"created", "updated", "deleted", "noops", "version_conflicts"} {
+		n, _ := toInt(status[key])
+		p.Copied += int64(n)
+	}
+	total, _ := toInt(status["total"])
+	p.Total = int64(total)
+
+	p.Rate, p.ETA = 0, 0
+	if elapsed := now.Sub(p.StartedAt).Seconds(); elapsed > 0 && p.Copied > 0 {
+		p.Rate = float64(p.Copied) / elapsed
+		if remaining := p.Total - p.Copied; remaining > 0 {
+			p.ETA = time.Duration(float64(remaining) / p.Rate * float64(time.Second))
+		}
+	}
+}
+
+// countAll counts documents of index matching query without tenant checks,
+// for admin operations that already built company filter.
+func (c *Client) countAll(ctx context.Context, index string, query map[string]any) (int64, error) {
+	var body any
+	if query != nil {
+		body = map[string]any{"query": query}
+	}
+
+	var resp struct {
+		Count int64 `json:"count"`
+	}
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, fmt.Sprintf("/%s/_count", index), nil, body, &resp)
+	if err != nil {
+		return 0, err
+	}
+	if status != http.StatusOK {
+		return 0, &StatusError{Op: "count", StatusCode: status, body: errBody}
+	}
+	return resp.Count, nil
+}
@@ -0,0 +1,60 @@
+package esclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator_Progress(t *testing.T) {
+	const companyID = "01234567-89ab-cdef-0123-456789abcdef"
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"task": "node:1"}`},
+		{body: `{"completed": false, "task": {"status": {"total": 100, "created": 30, "version_conflicts": 10}}}`},
+		{body: `{"completed": true, "response": {"total": 100, "created": 100}}`},
+		{body: `{"count": 100}`},
+		{body: `{"count": 100}`},
+	}}
+	registry := NewRegistry("main")
+	registry.byName["main"] = Entry{Name: "main", Version: 8, BaseURL: "http://localhost:9200", ES: es}
+
+	var events []MigrationProgress
+	migrator := NewMigrator(registry, MigrationRequest{
+		CompanyID:  companyID,
+		SrcCluster: "main",
+		SrcIndex:   "orders",
+		DstCluster: "main",
+		DstIndex:   "orders_" + companyID,
+		Reindex:    &ReindexOptions{PollInterval: time.Millisecond},
+	}, func(p MigrationProgress) {
+		events = append(events, p)
+	})
+	assert.Equal(t, MigrationPending, migrator.Progress().Phase)
+
+	_, err := migrator.Run(context.Background())
+	require.NoError(t, err)
+
+	phases := make([]MigrationPhase, len(events))
+	for i, event := range events {
+		phases[i] = event.Phase
+	}
+	assert.Equal(t, []MigrationPhase{MigrationCopying, MigrationCopying, MigrationVerifying, MigrationCompleted}, phases)
+	assert.Equal(t, int64(100), events[1].Total)
+	assert.Equal(t, int64(40), events[1].Copied)
+	assert.Positive(t, events[1].Rate)
+	assert.Positive(t, events[1].ETA)
+
+	progress := migrator.Progress()
+	assert.Equal(t, MigrationCompleted, progress.Phase)
+	assert.Equal(t, int64(100), progress.Copied)
+
+	// Only company documents are copied from shared source index and counted on both sides
+	assert.JSONEq(t, `{"source": {"index": "orders", "query": {"bool": {"filter": [{"term": {"company_id.keyword": "`+companyID+`"}}]}}},
+		"dest": {"index": "orders_`+companyID+`"}}`, es.bodies[0])
+	assert.JSONEq(t, `{"query": {"bool": {"filter": [{"term": {"company_id.keyword": "`+companyID+`"}}]}}}`, es.bodies[3])
+	assert.Equal(t, "POST /orders_"+companyID+"/_count", es.paths[4])
+	assert.Empty(t, es.bodies[4])
+}
@@ -0,0 +1,69 @@
+package esclient
+
+import (
+	"github.com/pkg/errors"
+)
+
+// MoreLikeThis configures more_like_this query finding documents similar to liked documents or texts,
+// e.g. "similar products" recommendations. It is added to SearchRequest query as required clause,
+// so company filter of shared index applies to results as usual.
+type MoreLikeThis struct {
+	Fields    []string // Fields to analyze (default: all text fields)
+	LikeIDs   []string // IDs of liked documents in searched index; excluded from results
+	LikeTexts []string // Liked free texts
+
+	MinTermFreq        *int   // Min frequency of term in liked input (ES default: 2; use 1 for short texts like names)
+	MinDocFreq         *int   // Min number of documents term must occur in (ES default: 5)
+	MaxQueryTerms      *int   // Max number of selected terms (ES default: 25)
+	MinimumShouldMatch string // Min selected terms to match (ES default: "30%"), optional
+}
+
+// apply adds more_like_this clause to query body. Liked documents are fetched with routing,
+// which on shared index must be company routing for documents to be found.
+func (m *MoreLikeThis) apply(body map[string]any, routing string) error {
+	if len(m.LikeIDs) == 0 && len(m.LikeTexts) == 0 {
+		return errors.New("more_like_this requires liked document IDs or texts")
+	}
+
+	like := make([]any, 0, len(m.LikeIDs)+len(m.LikeTexts))
+	for _, id := range m.LikeIDs {
+		item := map[string]any{"_id": id}
+		if routing != "" {
+			item["routing"] = routing
+		}
+		like = append(like, item)
+	}
+	for _, text := range m.LikeTexts {
+		like = append(like, text)
+	}
+
+	mlt := map[string]any{"like": like}
+	if len(m.Fields) > 0 {
+		mlt["fields"] = m.Fields
+	}
+	if m.MinTermFreq != nil {
+		mlt["min_term_freq"] = *m.MinTermFreq
+	}
+	if m.MinDocFreq != nil {
+		mlt["min_doc_freq"] = *m.MinDocFreq
+	}
+	if m.MaxQueryTerms != nil {
+		mlt["max_query_terms"] = *m.MaxQueryTerms
+	}
+	if m.MinimumShouldMatch != "" {
+		mlt["minimum_should_match"] = m.MinimumShouldMatch
+	}
+	clause := map[string]any{"more_like_this": mlt}
+
+	query, ok := body["query"]
+	if !ok {
+		body["query"] = clause
+		return nil
+	}
+	body["query"] = map[string]any{
+		"bool": map[string]any{
+			"must": []any{query, clause},
+		},
+	}
+	return nil
+}
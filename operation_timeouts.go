@@ -0,0 +1,106 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Operation classes of OperationTimeouts.
+const (
+	OperationSearch = "search" // Searches, counts and document reads
+	OperationBulk   = "bulk"   // Bulk requests, document writes and by-query updates
+	OperationAdmin  = "admin"  // Everything else: index, alias, cluster and task management
+)
+
+// OperationTimeouts configures default deadlines per operation class. They apply only
+// to calls whose context has no deadline, preventing unbounded hangs. 0 disables a class.
+type OperationTimeouts struct {
+	Search time.Duration // Default deadline of search operations
+	Bulk   time.Duration // Default deadline of bulk and write operations
+	Admin  time.Duration // Default deadline of admin operations
+}
+
+// WithOperationTimeouts sets default deadlines of calls without context deadline per operation class.
+// Unlike WithTimeout, calls with explicit context deadline are not affected.
+func WithOperationTimeouts(timeouts OperationTimeouts) ClientOption {
+	return func(c *Client) {
+		c.opTimeouts = timeouts
+	}
+}
+
+// merge returns timeouts with non-zero overlay fields applied.
+func (t OperationTimeouts) merge(overlay OperationTimeouts) OperationTimeouts {
+	if overlay.Search != 0 {
+		t.Search = overlay.Search
+	}
+	if overlay.Bulk != 0 {
+		t.Bulk = overlay.Bulk
+	}
+	if overlay.Admin != 0 {
+		t.Admin = overlay.Admin
+	}
+	return t
+}
+
+// isZero reports whether no timeout is set.
+func (t OperationTimeouts) isZero() bool {
+	return t == OperationTimeouts{}
+}
+
+// forClass returns timeout of operation class.
+func (t OperationTimeouts) forClass(class string) time.Duration {
+	switch class {
+	case OperationSearch:
+		return t.Search
+	case OperationBulk:
+		return t.Bulk
+	default:
+		return t.Admin
+	}
+}
+
+// operationClass classifies request by its path endpoints and method.
+func operationClass(req *http.Request) string {
+	for _, segment := range strings.Split(req.URL.Path, "/") {
+		switch segment {
+		case "_search", "_msearch", "_count", "_mget", "_pit", "_explain", "_termvectors", "_mtermvectors":
+			return OperationSearch
+		case "_bulk", "_update_by_query", "_delete_by_query", "_reindex", "_create", "_update":
+			return OperationBulk
+		case "_doc", "_source":
+			if req.Method == http.MethodGet || req.Method == http.MethodHead {
+				return OperationSearch
+			}
+			return OperationBulk
+		}
+	}
+	return OperationAdmin
+}
+
+// operationTimeoutClient applies default deadline of operation class to requests without deadline.
+type operationTimeoutClient struct {
+	es       ESClient
+	timeouts OperationTimeouts
+}
+
+// Do executes request with default deadline if context has none.
+func (oc *operationTimeoutClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if _, ok := ctx.Deadline(); ok {
+		return oc.es.Do(ctx, req)
+	}
+	timeout := oc.timeouts.forClass(operationClass(req))
+	if timeout <= 0 {
+		return oc.es.Do(ctx, req)
+	}
+	return (&timeoutClient{es: oc.es, timeout: timeout}).Do(ctx, req)
+}
+
+// withOperationTimeouts wraps ESClient to apply default deadlines; returns es as is if none is set.
+func withOperationTimeouts(es ESClient, timeouts OperationTimeouts) ESClient {
+	if timeouts.isZero() {
+		return es
+	}
+	return &operationTimeoutClient{es: es, timeouts: timeouts}
+}
@@ -2,63 +2,165 @@ package esclient
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
 // Client provides typed Elasticsearch operations on top of ESClient.
 type Client struct {
-	es      ESClient
-	baseURL *url.URL
-	log     Logger
+	es               ESClient // base with headers and timeout applied
+	base             ESClient
+	baseURL          *url.URL
+	log              Logger
+	headers          http.Header       // static headers of every request
+	timeout          time.Duration     // timeout of every request
+	opTimeouts       OperationTimeouts // default deadlines of calls without deadline
+	refresh          string            // default refresh policy of writes
+	retry            *RetryPolicy      // retry policy, optional
+	metrics          Metrics           // request metrics receiver, optional
+	tracer           Tracer            // request tracer, optional
+	codec            Codec             // request and response body codec
+	version          int               // ES major version of cluster, 0 if unknown
+	templates        *TemplateRegistry // index templates, optional
+	bodyLimits       BodyLimits        // request body size guard and compression
+	acceptGzip       bool              // ask for gzip-compressed responses
+	clock            Clock             // time source of backoff and request timing
+	shardDiagnostics bool              // log shards serving every search
+	bulkSizes        *bulkSizer        // bulk chunk size accepted by cluster
+	quarantinePrefix string            // quarantine index prefix of UpsertMany, empty if disabled
+	boosts           *TenantBoosts     // score boosts per company, optional
+	errorBodyLimit   int               // bytes of error response body to retain, 0 for default
+	fallback         *readFallback     // cluster serving reads while cluster is failing, optional
+	crossTenant      bool              // allow audited searches bypassing company filter
+	stats            *requestStats     // request statistics of cluster
 }
 
 // NewClient creates a typed client wrapper around ESClient.
-func NewClient(es ESClient, baseURL string) (*Client, error) {
-	return NewClientWithLogger(es, baseURL, nil)
+// Options configure logger, retries, metrics, tracing, codec and other behavior.
+func NewClient(es ESClient, baseURL string, opts ...ClientOption) (*Client, error) {
+	u, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(es, u, opts...), nil
 }
 
 // NewClientWithLogger creates a typed client wrapper around ESClient with logger.
+// Equivalent to NewClient with WithLogger option.
 func NewClientWithLogger(es ESClient, baseURL string, log Logger) (*Client, error) {
-	u, err := parseBaseURL(baseURL)
+	return NewClient(es, baseURL, WithLogger(log))
+}
+
+// NewClientWithHeaders creates a typed client wrapper around ESClient that applies
+// static headers to every request. Clients created from Registry don't need it:
+// ClusterConfig.Headers are already applied by the version-specific client transport.
+// Equivalent to NewClient with WithHeaders and WithLogger options.
+func NewClientWithHeaders(es ESClient, baseURL string, headers http.Header, log Logger) (*Client, error) {
+	return NewClient(es, baseURL, WithHeaders(headers), WithLogger(log))
+}
+
+// Search performs search request.
+func (c *Client) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	httpReq, err := c.newSearchRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{
-		es:      es,
-		baseURL: u,
-		log:     safeLogger(log),
-	}, nil
+	var resp SearchResponse
+	status, errBody, err := c.doJSON(ctx, httpReq, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "search", StatusCode: status, body: errBody}
+	}
+
+	return &resp, nil
 }
 
-// Search performs search request.
-func (c *Client) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+// newSearchRequest builds HTTP request of search with tenant filter, routing and typed options applied.
+func (c *Client) newSearchRequest(ctx context.Context, req *SearchRequest) (*http.Request, error) {
 	if req.Index == "" {
 		return nil, errors.New("index name is required")
 	}
 
-	target := DetectIndexTarget(req.Index)
+	target := indexTarget(req.Target, req.Index)
 	queryCopy := deepCopyMap(req.Query)
+	if queryCopy == nil {
+		queryCopy = make(map[string]any)
+	}
 
-	if target == IndexTargetShared {
+	crossTenant := target == IndexTargetShared && req.crossTenant != nil
+	if req.MoreLikeThis != nil {
+		routing := ""
+		if !crossTenant {
+			routing = routingFor(req.Routing, req.CompanyID, target)
+		}
+		if err := req.MoreLikeThis.apply(queryCopy, routing); err != nil {
+			return nil, err
+		}
+	}
+	if crossTenant {
+		if !c.crossTenant {
+			return nil, ErrCrossTenantNotAllowed
+		}
+		if err := req.crossTenant.validate(); err != nil {
+			return nil, err
+		}
+		logWarn(ctx, c.log, "elasticsearch tenant filter bypassed", map[string]interface{}{
+			"index":  req.Index,
+			"actor":  req.crossTenant.Actor,
+			"reason": req.crossTenant.Reason,
+		})
+	} else if target == IndexTargetShared && (req.Knn == nil || queryCopy["query"] != nil) {
+		// Filter-only query next to knn would add every company document to kNN hits,
+		// so pure kNN search is isolated by knn filter alone
 		mutator := NewQueryMutator()
 		if err := mutator.InjectCompanyFilter(queryCopy, req.CompanyID, target); err != nil {
 			return nil, errors.Wrap(err, "failed to inject company filter")
 		}
 	}
 
-	body, err := jsonBody(queryCopy)
+	if c.boosts != nil && req.CompanyID != "" && (req.Knn == nil || queryCopy["query"] != nil) {
+		NewQueryMutator().ApplyScoreBoosts(queryCopy, c.boosts.match(req.CompanyID, req.Index)...)
+	}
+
+	buildSearchBody(ctx, queryCopy, req)
+
+	if req.Knn != nil {
+		companyID := ""
+		if target == IndexTargetShared && !crossTenant {
+			if req.CompanyID == "" {
+				return nil, errors.New("companyID required for shared index")
+			}
+			companyID = req.CompanyID
+		}
+		knn, err := req.Knn.body(c.version, companyID)
+		if err != nil {
+			return nil, err
+		}
+		queryCopy["knn"] = knn
+	}
+
+	body, err := c.jsonBody(queryCopy)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal query")
 	}
 
 	path := fmt.Sprintf("/%s/_search", req.Index)
+	if req.PointInTime != nil {
+		// Point-in-time already pins indices; index must not be in path
+		path = "/_search"
+	}
 	query := url.Values{}
 
 	if req.Size != nil {
@@ -70,6 +172,30 @@ func (c *Client) Search(ctx context.Context, req *SearchRequest) (*SearchRespons
 	if req.WithTrackTotalHits {
 		query.Set("track_total_hits", "true")
 	}
+	if req.PointInTime == nil {
+		// Not allowed with point-in-time, which keeps options it was opened with
+		setIndicesOptions(query, req.IgnoreUnavailable, req.AllowNoIndices)
+		routingCompanyID := req.CompanyID
+		if crossTenant {
+			// Documents of all companies are searched, so company routing does not apply
+			routingCompanyID = ""
+		}
+		setRouting(query, routingFor(req.Routing, routingCompanyID, target))
+		if req.Preference != "" {
+			query.Set("preference", req.Preference)
+		}
+	}
+	if req.AllowPartialResults != nil {
+		query.Set("allow_partial_search_results", strconv.FormatBool(*req.AllowPartialResults))
+	}
+
+	if c.shardDiagnostics && req.PointInTime == nil {
+		c.logSearchShards(ctx, &SearchShardsRequest{
+			Index:      req.Index,
+			Routing:    query.Get("routing"),
+			Preference: req.Preference,
+		})
+	}
 
 	u := newURL(c.baseURL, path, query)
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
@@ -78,17 +204,7 @@ func (c *Client) Search(ctx context.Context, req *SearchRequest) (*SearchRespons
 	}
 	contentTypeJSON(httpReq)
 
-	var resp SearchResponse
-	status, err := doJSON(ctx, c.es, httpReq, &resp, c.log)
-	if err != nil {
-		return nil, err
-	}
-
-	if status != http.StatusOK {
-		return nil, &StatusError{Op: "search", StatusCode: status}
-	}
-
-	return &resp, nil
+	return httpReq, nil
 }
 
 // OpenPIT opens point-in-time for pagination.
@@ -111,13 +227,13 @@ func (c *Client) OpenPIT(ctx context.Context, req *OpenPITRequest) (*PIT, error)
 	}
 
 	var pit PIT
-	status, err := doJSON(ctx, c.es, httpReq, &pit, c.log)
+	status, errBody, err := c.doJSON(ctx, httpReq, &pit)
 	if err != nil {
 		return nil, err
 	}
 
 	if status != http.StatusOK {
-		return nil, &StatusError{Op: "open_pit", StatusCode: status}
+		return nil, &StatusError{Op: "open_pit", StatusCode: status, body: errBody}
 	}
 
 	return &pit, nil
@@ -130,7 +246,7 @@ func (c *Client) ClosePIT(ctx context.Context, pitID string) error {
 	}
 
 	path := "/_pit"
-	body, err := jsonBody(map[string]interface{}{
+	body, err := c.jsonBody(map[string]interface{}{
 		"id": pitID,
 	})
 	if err != nil {
@@ -144,13 +260,13 @@ func (c *Client) ClosePIT(ctx context.Context, pitID string) error {
 	}
 	contentTypeJSON(httpReq)
 
-	status, err := doJSON(ctx, c.es, httpReq, nil, c.log)
+	status, errBody, err := c.doJSON(ctx, httpReq, nil)
 	if err != nil {
 		return err
 	}
 
 	if status != http.StatusOK {
-		return &StatusError{Op: "close_pit", StatusCode: status}
+		return &StatusError{Op: "close_pit", StatusCode: status, body: errBody}
 	}
 
 	return nil
@@ -163,24 +279,45 @@ func (c *Client) Bulk(ctx context.Context, req *BulkRequest) (*BulkResponse, err
 		path = fmt.Sprintf("/%s/_bulk", req.Index)
 	}
 
+	body := req.Body
+	if req.StampCompanyID {
+		if req.CompanyID == "" {
+			return nil, errors.New("companyID required for company_id stamping")
+		}
+		stamped, err := stampBulkBody(body, req.Index, req.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		body = stamped
+	}
+
+	refresh := "wait_for"
+	if c.refresh != "" {
+		refresh = c.refresh
+	}
 	query := url.Values{
-		"refresh": []string{"wait_for"},
+		"refresh": []string{refresh},
 	}
+	routing := req.Routing
+	if req.Index != "" {
+		routing = routingFor(req.Routing, req.CompanyID, DetectIndexTarget(req.Index))
+	}
+	setRouting(query, routing)
 	u := newURL(c.baseURL, path, query)
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), req.Body)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create bulk request")
 	}
 	httpReq.Header.Set("Content-Type", "application/x-ndjson")
 
 	var resp BulkResponse
-	status, err := doJSON(ctx, c.es, httpReq, &resp, c.log)
+	status, errBody, err := c.doJSON(ctx, httpReq, &resp)
 	if err != nil {
 		return nil, err
 	}
 
 	if status != http.StatusOK {
-		return nil, &StatusError{Op: "bulk", StatusCode: status}
+		return nil, &StatusError{Op: "bulk", StatusCode: status, body: errBody}
 	}
 	return &resp, nil
 }
@@ -191,7 +328,7 @@ func (c *Client) DeleteByQuery(ctx context.Context, req *DeleteByQueryRequest) (
 		return nil, errors.New("index name is required")
 	}
 
-	target := DetectIndexTarget(req.Index)
+	target := indexTarget(req.Target, req.Index)
 	queryCopy := deepCopyMap(req.Query)
 
 	if target == IndexTargetShared {
@@ -201,13 +338,16 @@ func (c *Client) DeleteByQuery(ctx context.Context, req *DeleteByQueryRequest) (
 		}
 	}
 
-	body, err := jsonBody(queryCopy)
+	body, err := c.jsonBody(queryCopy)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to encode query")
 	}
 
 	path := fmt.Sprintf("/%s/_delete_by_query", req.Index)
-	u := newURL(c.baseURL, path, nil)
+	query := url.Values{}
+	setIndicesOptions(query, req.IgnoreUnavailable, req.AllowNoIndices)
+	setRouting(query, routingFor(req.Routing, req.CompanyID, target))
+	u := newURL(c.baseURL, path, query)
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
 	if err != nil {
@@ -216,40 +356,58 @@ func (c *Client) DeleteByQuery(ctx context.Context, req *DeleteByQueryRequest) (
 	contentTypeJSON(httpReq)
 
 	var resp DeleteByQueryResponse
-	status, err := doJSON(ctx, c.es, httpReq, &resp, c.log)
+	status, errBody, err := c.doJSON(ctx, httpReq, &resp)
 	if err != nil {
 		return nil, err
 	}
 
 	if status != http.StatusOK {
-		return nil, &StatusError{Op: "delete_by_query", StatusCode: status}
+		return nil, &StatusError{Op: "delete_by_query", StatusCode: status, body: errBody}
 	}
 
 	return &resp, nil
 }
 
 // CreateIndex creates a new index with mappings and settings.
+// Index sort settings are validated against mappings, see ValidateIndexSort.
 func (c *Client) CreateIndex(ctx context.Context, req *CreateIndexRequest) error {
 	if req.Index == "" {
 		return errors.New("index name is required")
 	}
 
+	body := req.Body
+	if body == nil {
+		bodyReq, err := c.indexBody(req)
+		if err != nil {
+			return err
+		}
+		// Index sort cannot be changed later, so misconfiguration is caught before index is created
+		if err := ValidateIndexSort(bodyReq.Settings, bodyReq.Mappings); err != nil {
+			return errors.Wrapf(err, "invalid index sort of index %q", req.Index)
+		}
+		b, err := createIndexBody(bodyReq)
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+
 	path := fmt.Sprintf("/%s", req.Index)
 	u := newURL(c.baseURL, path, nil)
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), req.Body)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), body)
 	if err != nil {
 		return errors.Wrap(err, "failed to create index request")
 	}
 	contentTypeJSON(httpReq)
 
-	status, err := doJSON(ctx, c.es, httpReq, nil, c.log)
+	status, errBody, err := c.doJSON(ctx, httpReq, nil)
 	if err != nil {
 		return err
 	}
 
 	if status != http.StatusOK && status != http.StatusCreated {
-		return &StatusError{Op: "create_index", StatusCode: status}
+		return &StatusError{Op: "create_index", StatusCode: status, body: errBody}
 	}
 
 	return nil
@@ -269,13 +427,13 @@ func (c *Client) DeleteIndex(ctx context.Context, indexName string) error {
 		return errors.Wrap(err, "failed to create delete index request")
 	}
 
-	status, err := doJSON(ctx, c.es, httpReq, nil, c.log)
+	status, errBody, err := c.doJSON(ctx, httpReq, nil)
 	if err != nil {
 		return err
 	}
 
 	if status != http.StatusOK {
-		return &StatusError{Op: "delete_index", StatusCode: status}
+		return &StatusError{Op: "delete_index", StatusCode: status, body: errBody}
 	}
 
 	return nil
@@ -295,7 +453,7 @@ func (c *Client) IndexExists(ctx context.Context, indexName string) (bool, error
 		return false, errors.Wrap(err, "failed to create index exists request")
 	}
 
-	status, err := doJSON(ctx, c.es, httpReq, nil, c.log)
+	status, _, err := c.doJSON(ctx, httpReq, nil)
 	if err != nil {
 		return false, err
 	}
@@ -309,7 +467,7 @@ func (c *Client) Count(ctx context.Context, req *CountRequest) (*CountResponse,
 		return nil, errors.New("index name is required")
 	}
 
-	target := DetectIndexTarget(req.Index)
+	target := indexTarget(req.Target, req.Index)
 	query := req.Query
 	if query == nil {
 		query = make(map[string]any)
@@ -323,13 +481,19 @@ func (c *Client) Count(ctx context.Context, req *CountRequest) (*CountResponse,
 		}
 	}
 
-	body, err := jsonBody(queryCopy)
+	body, err := c.jsonBody(queryCopy)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to encode query")
 	}
 
 	path := fmt.Sprintf("/%s/_count", req.Index)
-	u := newURL(c.baseURL, path, nil)
+	params := url.Values{}
+	setIndicesOptions(params, req.IgnoreUnavailable, req.AllowNoIndices)
+	setRouting(params, routingFor(req.Routing, req.CompanyID, target))
+	if req.TerminateAfter > 0 {
+		params.Set("terminate_after", strconv.Itoa(req.TerminateAfter))
+	}
+	u := newURL(c.baseURL, path, params)
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
 	if err != nil {
@@ -340,25 +504,53 @@ func (c *Client) Count(ctx context.Context, req *CountRequest) (*CountResponse,
 	}
 
 	var resp CountResponse
-	status, err := doJSON(ctx, c.es, httpReq, &resp, c.log)
+	status, errBody, err := c.doJSON(ctx, httpReq, &resp)
 	if err != nil {
 		return nil, err
 	}
 
 	if status != http.StatusOK {
-		return nil, &StatusError{Op: "count", StatusCode: status}
+		return nil, &StatusError{Op: "count", StatusCode: status, body: errBody}
 	}
 
 	return &resp, nil
 }
 
+// Exists checks if at least one document matches query.
+// Unlike Count it stops on first match per shard and doesn't track total hits.
+func (c *Client) Exists(ctx context.Context, req *ExistsRequest) (bool, error) {
+	query := deepCopyMap(req.Query)
+	if query == nil {
+		query = make(map[string]any)
+	}
+	query["size"] = 0
+	query["terminate_after"] = 1
+	query["track_total_hits"] = false
+
+	resp, err := c.Search(ctx, &SearchRequest{
+		Index:             req.Index,
+		Target:            req.Target,
+		Query:             query,
+		CompanyID:         req.CompanyID,
+		IgnoreUnavailable: req.IgnoreUnavailable,
+		AllowNoIndices:    req.AllowNoIndices,
+		Routing:           req.Routing,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// With terminate_after=1 any shard holding a match terminates early
+	return resp.TerminatedEarly || len(resp.Hits.Hits) > 0, nil
+}
+
 // UpdateByQuery updates documents matching query.
 func (c *Client) UpdateByQuery(ctx context.Context, req *UpdateByQueryRequest) (*UpdateByQueryResponse, error) {
 	if req.Index == "" {
 		return nil, errors.New("index name is required")
 	}
 
-	target := DetectIndexTarget(req.Index)
+	target := indexTarget(req.Target, req.Index)
 	queryCopy := deepCopyMap(req.Query)
 
 	if target == IndexTargetShared {
@@ -368,13 +560,27 @@ func (c *Client) UpdateByQuery(ctx context.Context, req *UpdateByQueryRequest) (
 		}
 	}
 
-	body, err := jsonBody(queryCopy)
+	body, err := c.jsonBody(queryCopy)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to encode query")
 	}
 
 	path := fmt.Sprintf("/%s/_update_by_query", req.Index)
-	u := newURL(c.baseURL, path, nil)
+	query := url.Values{}
+	setRouting(query, routingFor(req.Routing, req.CompanyID, target))
+	if req.Conflicts != "" {
+		query.Set("conflicts", req.Conflicts)
+	}
+	if req.WaitForCompletion != nil {
+		query.Set("wait_for_completion", strconv.FormatBool(*req.WaitForCompletion))
+	}
+	if req.Slices != "" {
+		query.Set("slices", req.Slices)
+	}
+	if req.RequestsPerSecond != nil {
+		query.Set("requests_per_second", strconv.FormatFloat(*req.RequestsPerSecond, 'f', -1, 64))
+	}
+	u := newURL(c.baseURL, path, query)
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
 	if err != nil {
@@ -383,13 +589,13 @@ func (c *Client) UpdateByQuery(ctx context.Context, req *UpdateByQueryRequest) (
 	contentTypeJSON(httpReq)
 
 	var resp UpdateByQueryResponse
-	status, err := doJSON(ctx, c.es, httpReq, &resp, c.log)
+	status, errBody, err := c.doJSON(ctx, httpReq, &resp)
 	if err != nil {
 		return nil, err
 	}
 
 	if status != http.StatusOK {
-		return nil, &StatusError{Op: "update_by_query", StatusCode: status}
+		return nil, &StatusError{Op: "update_by_query", StatusCode: status, body: errBody}
 	}
 
 	return &resp, nil
@@ -404,22 +610,100 @@ func (c *Client) CreateDocument(ctx context.Context, req *CreateDocumentRequest)
 		return nil, errors.New("document ID is required")
 	}
 
-	path := fmt.Sprintf("/%s/_doc/%s", req.Index, req.DocumentID)
-	u := newURL(c.baseURL, path, nil)
+	if (req.IfSeqNo == nil) != (req.IfPrimaryTerm == nil) {
+		return nil, errors.New("if_seq_no and if_primary_term must be set together")
+	}
+
+	target := DetectIndexTarget(req.Index)
+	body := req.Body
+	if req.StampCompanyID && target == IndexTargetShared {
+		if req.CompanyID == "" {
+			return nil, errors.New("companyID required for company_id stamping")
+		}
+		stamped, err := stampDocument(body, req.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		body = stamped
+	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), req.Body)
+	path := fmt.Sprintf("/%s/_doc/%s", req.Index, url.PathEscape(req.DocumentID))
+	query := url.Values{}
+	setRouting(query, routingFor(req.Routing, req.CompanyID, target))
+	if req.Refresh != "" {
+		query.Set("refresh", req.Refresh)
+	} else if c.refresh != "" {
+		query.Set("refresh", c.refresh)
+	}
+	if req.OpType != "" {
+		query.Set("op_type", req.OpType)
+	}
+	if req.Version != nil {
+		query.Set("version", strconv.FormatInt(*req.Version, 10))
+		query.Set("version_type", "external")
+	}
+	if req.IfSeqNo != nil {
+		query.Set("if_seq_no", strconv.FormatInt(*req.IfSeqNo, 10))
+		query.Set("if_primary_term", strconv.FormatInt(*req.IfPrimaryTerm, 10))
+	}
+	u := newURL(c.baseURL, path, query)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), body)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create document request")
 	}
 	contentTypeJSON(httpReq)
 
 	var resp CreateDocumentResponse
-	status, err := doJSON(ctx, c.es, httpReq, &resp, c.log)
+	status, errBody, err := c.doJSON(ctx, httpReq, &resp)
 	if err != nil {
 		return nil, err
 	}
 	if status != http.StatusOK && status != http.StatusCreated {
-		return nil, &StatusError{Op: "create_document", StatusCode: status}
+		return nil, &StatusError{Op: "create_document", StatusCode: status, body: errBody}
+	}
+
+	return &resp, nil
+}
+
+// GetDocument returns document by ID. Missing document is reported with Found false.
+func (c *Client) GetDocument(ctx context.Context, req *GetDocumentRequest) (*GetDocumentResponse, error) {
+	if req.Index == "" {
+		return nil, errors.New("index name is required")
+	}
+	if req.DocumentID == "" {
+		return nil, errors.New("document ID is required")
+	}
+
+	target := DetectIndexTarget(req.Index)
+	if target == IndexTargetShared && req.CompanyID == "" {
+		return nil, errors.New("companyID required for shared index")
+	}
+
+	query := url.Values{}
+	setRouting(query, routingFor(req.Routing, req.CompanyID, target))
+
+	var resp GetDocumentResponse
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("/%s/_doc/%s", req.Index, url.PathEscape(req.DocumentID)), query, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return &GetDocumentResponse{Index: req.Index, ID: req.DocumentID}, nil
+	}
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "get_document", StatusCode: status, body: errBody}
+	}
+
+	if target == IndexTargetShared && resp.Found {
+		var doc map[string]any
+		if err := json.Unmarshal(resp.Source, &doc); err != nil {
+			return nil, errors.Wrap(err, "failed to decode document source")
+		}
+		if doc[companyIDField] != req.CompanyID {
+			// Same as missing, so document IDs of other companies cannot be probed
+			return &GetDocumentResponse{Index: req.Index, ID: req.DocumentID}, nil
+		}
 	}
 
 	return &resp, nil
@@ -429,7 +713,7 @@ func (c *Client) CreateDocument(ctx context.Context, req *CreateDocumentRequest)
 func (c *Client) RawRequest(ctx context.Context, method, path string, body interface{}) (int, map[string]interface{}, error) {
 	var bodyReader interface{}
 	if body != nil {
-		r, err := jsonBody(body)
+		r, err := c.jsonBody(body)
 		if err != nil {
 			return 0, nil, err
 		}
@@ -447,7 +731,7 @@ func (c *Client) RawRequest(ctx context.Context, method, path string, body inter
 	}
 
 	var result map[string]interface{}
-	status, err := doJSON(ctx, c.es, httpReq, &result, c.log)
+	status, _, err := c.doJSON(ctx, httpReq, &result)
 
 	return status, result, err
 }
@@ -0,0 +1,1147 @@
+package esclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeES records requests and replies with a canned response.
+type fakeES struct {
+	requests []*http.Request
+	bodies   []string
+	status   int
+	response string
+	header   http.Header
+}
+
+func (f *fakeES) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	f.requests = append(f.requests, req)
+	f.bodies = append(f.bodies, string(body))
+
+	status := f.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := f.response
+	if response == "" {
+		response = "{}"
+	}
+
+	header := f.header
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader([]byte(response))),
+	}, nil
+}
+
+func newTestClient(t *testing.T, es ESClient) *Client {
+	t.Helper()
+
+	client, err := NewClient(es, "http://localhost:9200")
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_Search_Highlight(t *testing.T) {
+	es := &fakeES{}
+	client := newTestClient(t, es)
+
+	fragmentSize := 150
+	_, err := client.Search(context.Background(), &SearchRequest{
+		Index: "products_01234567-89ab-cdef-0123-456789abcdef",
+		Query: map[string]any{
+			"query": map[string]any{"match": map[string]any{"name": "phone"}},
+		},
+		Highlight: &Highlight{
+			Fields:       []string{"name"},
+			PreTags:      []string{"<b>"},
+			PostTags:     []string{"</b>"},
+			FragmentSize: &fragmentSize,
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, es.bodies, 1)
+
+	expectedJSON := `{
+		"query": {"match": {"name": "phone"}},
+		"highlight": {
+			"fields": {"name": {}},
+			"pre_tags": ["<b>"],
+			"post_tags": ["</b>"],
+			"fragment_size": 150
+		}
+	}`
+	assert.JSONEq(t, expectedJSON, es.bodies[0])
+}
+
+func TestSearchResponse_Highlights(t *testing.T) {
+	raw := `{
+		"hits": {
+			"hits": [
+				{"_id": "1", "highlight": {"name": ["<em>phone</em> case", "smart<em>phone</em>"]}},
+				{"_id": "2"}
+			]
+		}
+	}`
+
+	var resp SearchResponse
+	require.NoError(t, json.Unmarshal([]byte(raw), &resp))
+
+	highlights := resp.Highlights()
+	require.Len(t, highlights, 2)
+	assert.Equal(t, []string{"<em>phone</em> case", "smart<em>phone</em>"}, highlights[0]["name"])
+	assert.Nil(t, highlights[1])
+}
+
+func TestClient_Search_Sort(t *testing.T) {
+	es := &fakeES{}
+	client := newTestClient(t, es)
+
+	_, err := client.Search(context.Background(), &SearchRequest{
+		Index: "orders_01234567-89ab-cdef-0123-456789abcdef",
+		Query: map[string]any{
+			"sort": []any{"_doc"},
+		},
+		Sort: []SortClause{
+			{Field: "created_at", Order: "desc", Missing: "_last"},
+			{Field: "items.price", Order: "asc", Mode: "min", NestedPath: "items"},
+		},
+	})
+	require.NoError(t, err)
+
+	expectedJSON := `{
+		"sort": [
+			{"created_at": {"order": "desc", "missing": "_last"}},
+			{"items.price": {"order": "asc", "mode": "min", "nested": {"path": "items"}}}
+		]
+	}`
+	assert.JSONEq(t, expectedJSON, es.bodies[0])
+}
+
+func TestClient_Search_Routing(t *testing.T) {
+	es := &fakeES{}
+	client := newTestClient(t, es)
+	ctx := context.Background()
+
+	// Shared index routes by company ID
+	_, err := client.Search(ctx, &SearchRequest{Index: "products_shared", CompanyID: "company-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "company-1", es.requests[0].URL.Query().Get("routing"))
+
+	// Explicit routing wins
+	_, err = client.Search(ctx, &SearchRequest{Index: "products_shared", CompanyID: "company-1", Routing: "custom"})
+	require.NoError(t, err)
+	assert.Equal(t, "custom", es.requests[1].URL.Query().Get("routing"))
+
+	// Per-company index is not routed
+	_, err = client.Search(ctx, &SearchRequest{Index: "products_01234567-89ab-cdef-0123-456789abcdef", CompanyID: "company-1"})
+	require.NoError(t, err)
+	assert.False(t, es.requests[2].URL.Query().Has("routing"))
+}
+
+func TestClient_Exists(t *testing.T) {
+	es := &fakeES{response: `{"terminated_early": true, "hits": {"hits": []}}`}
+	client := newTestClient(t, es)
+
+	exists, err := client.Exists(context.Background(), &ExistsRequest{
+		Index:     "orders_shared",
+		CompanyID: "company-1",
+		Query: map[string]any{
+			"query": map[string]any{"term": map[string]any{"synced": false}},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal([]byte(es.bodies[0]), &body))
+	assert.EqualValues(t, 0, body["size"])
+	assert.EqualValues(t, 1, body["terminate_after"])
+	assert.Equal(t, false, body["track_total_hits"])
+
+	allowNoIndices := false
+	es.response = `{"hits": {"hits": []}}`
+	exists, err = client.Exists(context.Background(), &ExistsRequest{
+		Index:             "orders_shared",
+		CompanyID:         "company-1",
+		IgnoreUnavailable: true,
+		AllowNoIndices:    &allowNoIndices,
+	})
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, "true", es.requests[1].URL.Query().Get("ignore_unavailable"))
+	assert.Equal(t, "false", es.requests[1].URL.Query().Get("allow_no_indices"))
+}
+
+func TestClient_CreateDocument_Concurrency(t *testing.T) {
+	es := &fakeES{status: http.StatusConflict}
+	client := newTestClient(t, es)
+
+	seqNo, primaryTerm := int64(5), int64(1)
+	_, err := client.CreateDocument(context.Background(), &CreateDocumentRequest{
+		Index:         "orders",
+		DocumentID:    "order-1",
+		Body:          bytes.NewReader([]byte(`{"status":"paid"}`)),
+		Refresh:       "wait_for",
+		OpType:        "create",
+		IfSeqNo:       &seqNo,
+		IfPrimaryTerm: &primaryTerm,
+	})
+	require.Error(t, err)
+	assert.True(t, IsConflict(err))
+
+	query := es.requests[0].URL.Query()
+	assert.Equal(t, "wait_for", query.Get("refresh"))
+	assert.Equal(t, "create", query.Get("op_type"))
+	assert.Equal(t, "5", query.Get("if_seq_no"))
+	assert.Equal(t, "1", query.Get("if_primary_term"))
+}
+
+func TestClient_CreateDocument_StampCompanyID(t *testing.T) {
+	es := &fakeES{status: http.StatusCreated}
+	client := newTestClient(t, es)
+
+	_, err := client.CreateDocument(context.Background(), &CreateDocumentRequest{
+		Index:          "orders",
+		DocumentID:     "order-1",
+		Body:           bytes.NewReader([]byte(`{"company_id":"other","total":12345678901234567}`)),
+		CompanyID:      "c1",
+		StampCompanyID: true,
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"company_id":"c1","total":12345678901234567}`, es.bodies[0])
+	assert.Equal(t, "c1", es.requests[0].URL.Query().Get("routing"))
+}
+
+func TestClient_Document_EscapesID(t *testing.T) {
+	es := &fakeES{status: http.StatusCreated}
+	client := newTestClient(t, es)
+
+	_, err := client.CreateDocument(context.Background(), &CreateDocumentRequest{
+		Index:      "orders_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87",
+		DocumentID: "a/b?c",
+		Body:       bytes.NewReader([]byte(`{"status":"paid"}`)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/orders_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87/_doc/a%2Fb%3Fc", es.requests[0].URL.EscapedPath())
+	assert.Empty(t, es.requests[0].URL.RawQuery)
+
+	es.status = http.StatusNotFound
+	_, err = client.GetDocument(context.Background(), &GetDocumentRequest{Index: "orders_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87", DocumentID: "a/b?c"})
+	require.NoError(t, err)
+	assert.Equal(t, es.requests[0].URL.EscapedPath(), es.requests[1].URL.EscapedPath())
+}
+
+func TestClient_Bulk_StampCompanyID(t *testing.T) {
+	es := &fakeES{response: `{"items": []}`}
+	client := newTestClient(t, es)
+
+	body := `{"index":{"_id":"1"}}
+{"name":"a"}
+{"delete":{"_id":"2"}}
+{"update":{"_id":"3"}}
+{"doc":{"name":"c"},"doc_as_upsert":true}
+{"create":{"_index":"orders_2f1b8c4e-5d6a-4b7c-8e9f-0a1b2c3d4e5f","_id":"4"}}
+{"name":"d"}
+`
+	_, err := client.Bulk(context.Background(), &BulkRequest{
+		Index:          "orders",
+		Body:           strings.NewReader(body),
+		CompanyID:      "c1",
+		StampCompanyID: true,
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(es.bodies[0]), "\n")
+	require.Len(t, lines, 7)
+	assert.JSONEq(t, `{"name":"a","company_id":"c1"}`, lines[1])
+	assert.JSONEq(t, `{"delete":{"_id":"2"}}`, lines[2])
+	assert.JSONEq(t, `{"doc":{"name":"c","company_id":"c1"},"doc_as_upsert":true}`, lines[4])
+	// Per-company index documents are left untouched
+	assert.JSONEq(t, `{"name":"d"}`, lines[6])
+}
+
+func TestClient_GetIndex(t *testing.T) {
+	es := &fakeES{response: `{
+		"orders-000002": {
+			"aliases": {"orders": {"is_write_index": true}},
+			"mappings": {"properties": {"company_id": {"type": "keyword"}}},
+			"settings": {"index.number_of_shards": "3", "index.number_of_replicas": "1"}
+		}
+	}`}
+	client := newTestClient(t, es)
+
+	meta, err := client.GetIndex(context.Background(), "orders")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/orders", es.requests[0].URL.Path)
+	assert.Equal(t, "true", es.requests[0].URL.Query().Get("flat_settings"))
+	assert.Equal(t, "orders-000002", meta.Name)
+	assert.Equal(t, 3, meta.NumberOfShards())
+	assert.Equal(t, 1, meta.NumberOfReplicas())
+	require.NotNil(t, meta.Aliases["orders"].IsWriteIndex)
+	assert.True(t, *meta.Aliases["orders"].IsWriteIndex)
+	assert.Contains(t, meta.Mappings, "properties")
+}
+
+func TestClient_RefreshFlush(t *testing.T) {
+	es := &fakeES{}
+	client := newTestClient(t, es)
+
+	require.NoError(t, client.Refresh(context.Background(), "orders", "products"))
+	require.NoError(t, client.Flush(context.Background()))
+
+	assert.Equal(t, "/orders,products/_refresh", es.requests[0].URL.Path)
+	assert.Equal(t, "/_flush", es.requests[1].URL.Path)
+}
+
+func TestClient_Split(t *testing.T) {
+	es := &fakeES{response: `{"acknowledged": true, "shards_acknowledged": true, "index": "orders-split"}`}
+	client := newTestClient(t, es)
+
+	resp, err := client.Split(context.Background(), &ResizeRequest{
+		Index:    "orders",
+		Target:   "orders-split",
+		Settings: map[string]any{"index.number_of_shards": 4},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "orders-split", resp.Index)
+	assert.Equal(t, "/orders/_split/orders-split", es.requests[0].URL.Path)
+	assert.JSONEq(t, `{"settings": {"index.number_of_shards": 4}}`, es.bodies[0])
+}
+
+func TestClient_IndexStats(t *testing.T) {
+	es := &fakeES{response: `{
+		"_all": {"primaries": {"docs": {"count": 10}}},
+		"indices": {
+			"orders_c1": {
+				"uuid": "u1",
+				"primaries": {"docs": {"count": 10}, "store": {"size_in_bytes": 2048}, "indexing": {"index_total": 100}},
+				"total": {"search": {"query_total": 50}}
+			}
+		}
+	}`}
+	client := newTestClient(t, es)
+
+	stats, err := client.IndexStats(context.Background(), "orders_*")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/orders_*/_stats/docs,store,indexing,search", es.requests[0].URL.Path)
+	entry := stats.Indices["orders_c1"]
+	assert.Equal(t, int64(10), entry.Primaries.Docs.Count)
+	assert.Equal(t, int64(2048), entry.Primaries.Store.SizeInBytes)
+	assert.Equal(t, int64(50), entry.Total.Search.QueryTotal)
+
+	prev := IndexStats{}
+	assert.Equal(t, 10.0, entry.Primaries.IndexingRate(&prev, 10*time.Second))
+}
+
+func TestClient_TopHitsPerCompany(t *testing.T) {
+	es := &fakeES{response: `{
+		"hits": {"hits": []},
+		"aggregations": {"by_company": {"buckets": [
+			{"key": "c1", "doc_count": 42, "top": {"hits": {"hits": [{"_id": "1"}, {"_id": "2"}]}}}
+		]}}
+	}`}
+	client := newTestClient(t, es)
+	access := CrossTenantAccess{Actor: "analyst@billz", Reason: "weekly report"}
+
+	// Bypass requires client-level opt-in
+	_, err := client.TopHitsPerCompany(context.Background(), &TopHitsPerCompanyRequest{Index: "orders", Access: access})
+	require.ErrorIs(t, err, ErrCrossTenantNotAllowed)
+	client = client.With(WithCrossTenantAccess())
+
+	_, err = client.TopHitsPerCompany(context.Background(), &TopHitsPerCompanyRequest{Index: "orders"})
+	require.Error(t, err)
+	require.Empty(t, es.requests)
+
+	result, err := client.TopHitsPerCompany(context.Background(), &TopHitsPerCompanyRequest{
+		Index:  "orders",
+		Size:   2,
+		Access: access,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result, 1)
+	assert.Equal(t, "c1", result[0].CompanyID)
+	assert.Equal(t, int64(42), result[0].DocCount)
+	assert.Len(t, result[0].Hits, 2)
+
+	// No tenant filter and routing
+	assert.Empty(t, es.requests[0].URL.Query().Get("routing"))
+	assert.NotContains(t, es.bodies[0], "company_id.keyword\":\"")
+	assert.Contains(t, es.bodies[0], `"top_hits":{"size":2}`)
+}
+
+func TestClient_With(t *testing.T) {
+	es := &fakeES{status: http.StatusCreated}
+	client := newTestClient(t, es)
+
+	scoped := client.With(
+		WithHeaders(http.Header{"X-Request-Id": []string{"req-1"}}),
+		WithRefresh("true"),
+		WithTimeout(time.Second),
+	)
+	_, err := scoped.CreateDocument(context.Background(), &CreateDocumentRequest{
+		Index:      "orders",
+		DocumentID: "1",
+		Body:       strings.NewReader(`{}`),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "req-1", es.requests[0].Header.Get("X-Request-Id"))
+	assert.Equal(t, "true", es.requests[0].URL.Query().Get("refresh"))
+	_, hasDeadline := es.requests[0].Context().Deadline()
+	assert.True(t, hasDeadline)
+
+	// Original client is not affected
+	_, err = client.CreateDocument(context.Background(), &CreateDocumentRequest{
+		Index:      "orders",
+		DocumentID: "1",
+		Body:       strings.NewReader(`{}`),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, es.requests[1].Header.Get("X-Request-Id"))
+	assert.Empty(t, es.requests[1].URL.Query().Get("refresh"))
+}
+
+func TestClient_Snapshot(t *testing.T) {
+	es := &fakeES{response: `{"snapshot": {"snapshot": "pre-migration", "state": "SUCCESS", "indices": ["orders_c1"]}}`}
+	client := newTestClient(t, es)
+	ctx := context.Background()
+
+	info, err := client.CreateSnapshot(ctx, &CreateSnapshotRequest{
+		Repository:        "backups",
+		Snapshot:          "pre-migration",
+		Indices:           []string{"orders_c1", "products_c1"},
+		WaitForCompletion: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, SnapshotStateSuccess, info.State)
+	assert.Equal(t, "/_snapshot/backups/pre-migration", es.requests[0].URL.Path)
+	assert.Equal(t, "true", es.requests[0].URL.Query().Get("wait_for_completion"))
+	assert.JSONEq(t, `{"indices": "orders_c1,products_c1", "include_global_state": false}`, es.bodies[0])
+
+	err = client.RestoreSnapshot(ctx, &RestoreSnapshotRequest{
+		Repository:        "backups",
+		Snapshot:          "pre-migration",
+		Indices:           []string{"orders_c1"},
+		RenamePattern:     "(.+)",
+		RenameReplacement: "restored_$1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/_snapshot/backups/pre-migration/_restore", es.requests[1].URL.Path)
+	assert.JSONEq(t, `{
+		"indices": "orders_c1",
+		"include_global_state": false,
+		"rename_pattern": "(.+)",
+		"rename_replacement": "restored_$1"
+	}`, es.bodies[1])
+}
+
+func TestClient_Search_ResponseMeta(t *testing.T) {
+	es := &fakeES{header: http.Header{
+		"X-Elastic-Product": []string{"Elasticsearch"},
+		"Warning":           []string{`299 Elasticsearch-8.19.0 "[types removal] Specifying types in search requests is deprecated."`},
+	}}
+	client := newTestClient(t, es)
+
+	resp, err := client.Search(context.Background(), &SearchRequest{Index: "orders_shared", CompanyID: "c1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Elasticsearch", resp.Product)
+	assert.True(t, resp.Deprecated())
+	assert.Len(t, resp.Warnings, 1)
+}
+
+func TestClient_Bulk_IndexBlock(t *testing.T) {
+	es := &fakeES{status: http.StatusTooManyRequests, response: `{"error": {
+		"type": "cluster_block_exception",
+		"reason": "index [orders_shared] blocked by: [TOO_MANY_REQUESTS/12/disk usage exceeded flood-stage watermark, index has read-only-allow-delete block];"
+	}, "status": 429}`}
+	client := newTestClient(t, es)
+
+	_, err := client.Bulk(context.Background(), &BulkRequest{Index: "orders_shared", Body: strings.NewReader("{}\n")})
+	require.Error(t, err)
+	assert.True(t, IsIndexBlock(err))
+
+	var blockErr *IndexBlockError
+	require.ErrorAs(t, err, &blockErr)
+	assert.True(t, blockErr.ReadOnlyAllowDelete)
+	assert.Equal(t, []string{"orders_shared"}, blockErr.Indices)
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+}
+
+func TestClient_ErrorBodyLimit(t *testing.T) {
+	es := &fakeES{status: http.StatusBadGateway, response: "<html>" + strings.Repeat("x", 1000) + "</html>"}
+	client := newTestClient(t, es).With(WithErrorBodyLimit(16))
+
+	_, err := client.Search(context.Background(), &SearchRequest{Index: "orders_shared", CompanyID: "c1"})
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "<html>xxxxxxxxxx", statusErr.Body())
+	assert.True(t, statusErr.BodyTruncated())
+	assert.Equal(t, "search returned status code 502", err.Error())
+
+	es.response = `{"error": 1}`
+	es.status = http.StatusNotFound
+	_, err = client.Search(context.Background(), &SearchRequest{Index: "orders_shared", CompanyID: "c1"})
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, `{"error": 1}`, statusErr.Body())
+	assert.False(t, statusErr.BodyTruncated())
+}
+
+func TestClient_RemoveReadOnlyBlock(t *testing.T) {
+	es := &fakeES{response: `{"nodes": {"n1": {"name": "es-1", "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 5}}}}}`}
+	client := newTestClient(t, es)
+
+	// Disk still full
+	err := client.RemoveReadOnlyBlock(context.Background(), []string{"orders_shared"}, nil)
+	require.Error(t, err)
+	assert.Len(t, es.requests, 1)
+
+	es.response = `{"nodes": {"n1": {"name": "es-1", "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 40}}}}}`
+	require.NoError(t, client.RemoveReadOnlyBlock(context.Background(), []string{"orders_shared"}, nil))
+	assert.Equal(t, "/orders_shared/_settings", es.requests[2].URL.Path)
+	assert.JSONEq(t, `{"index.blocks.read_only_allow_delete": null}`, es.bodies[2])
+}
+
+func TestClient_SetIndexPipelines(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"orders_enrich": {"processors": []}}`},
+		{status: http.StatusNotFound},
+		{body: `{"orders_enrich": {"processors": []}}`},
+	}}
+	client := newTestClient(t, es)
+
+	err := client.SetIndexPipelines(context.Background(), "orders", "orders_enrich", "orders_audit")
+	require.EqualError(t, err, `pipeline "orders_audit" does not exist`)
+	assert.Len(t, es.paths, 2)
+
+	err = client.SetIndexPipelines(context.Background(), "orders", "orders_enrich", PipelineNone)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /_ingest/pipeline/orders_enrich",
+		"GET /_ingest/pipeline/orders_audit",
+		"GET /_ingest/pipeline/orders_enrich",
+		"PUT /orders/_settings",
+	}, es.paths)
+	assert.JSONEq(t, `{"index.default_pipeline": "orders_enrich", "index.final_pipeline": "_none"}`, es.bodies[3])
+}
+
+func TestUpdateWithRetry(t *testing.T) {
+	type stock struct {
+		Qty int `json:"qty"`
+	}
+	const index = "products_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87"
+	decrement := func(current stock) (stock, error) {
+		current.Qty--
+		return current, nil
+	}
+
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"_id": "p1", "_seq_no": 5, "_primary_term": 1, "found": true, "_source": {"qty": 10}}`},
+		{status: http.StatusConflict, body: `{"error": {"type": "version_conflict_engine_exception"}}`},
+		{body: `{"_id": "p1", "_seq_no": 6, "_primary_term": 1, "found": true, "_source": {"qty": 8}}`},
+		{status: http.StatusCreated, body: `{"_id": "p1", "result": "updated"}`},
+	}}
+	client := newTestClient(t, es)
+
+	updated, err := UpdateWithRetry(context.Background(), client, index, "p1", decrement, 3)
+	require.NoError(t, err)
+	assert.Equal(t, stock{Qty: 7}, updated)
+	assert.Equal(t, []string{
+		"GET /" + index + "/_doc/p1", "PUT /" + index + "/_doc/p1",
+		"GET /" + index + "/_doc/p1", "PUT /" + index + "/_doc/p1",
+	}, es.paths)
+	assert.JSONEq(t, `{"qty": 7}`, es.bodies[3])
+
+	// Conflict after last retry
+	es = &scriptedES{responses: []scriptedResponse{
+		{body: `{"_id": "p1", "_seq_no": 5, "_primary_term": 1, "found": true, "_source": {"qty": 10}}`},
+		{status: http.StatusConflict},
+	}}
+	_, err = UpdateWithRetry(context.Background(), newTestClient(t, es), index, "p1", decrement, 0)
+	assert.True(t, IsConflict(err))
+	assert.Len(t, es.paths, 2)
+
+	// Missing document is created from zero value; fn error stops update
+	es = &scriptedES{responses: []scriptedResponse{{status: http.StatusNotFound, body: `{"found": false}`}}}
+	updated, err = UpdateWithRetry(context.Background(), newTestClient(t, es), index, "p2", decrement, 3)
+	require.NoError(t, err)
+	assert.Equal(t, stock{Qty: -1}, updated)
+	assert.Equal(t, "PUT /"+index+"/_doc/p2", es.paths[1])
+
+	_, err = UpdateWithRetry(context.Background(), newTestClient(t, es), index, "p2", func(stock) (stock, error) {
+		return stock{}, assert.AnError
+	}, 3)
+	assert.Equal(t, assert.AnError, err)
+}
+
+func TestClient_CreateIndex_IndexSort(t *testing.T) {
+	mappings := map[string]any{"properties": map[string]any{
+		"created_at": map[string]any{"type": "date"},
+		"status":     map[string]any{"type": "keyword", "doc_values": false},
+		"name":       map[string]any{"type": "text", "fields": map[string]any{"keyword": map[string]any{"type": "keyword"}}},
+		"items":      map[string]any{"type": "nested", "properties": map[string]any{"sku": map[string]any{"type": "keyword"}}},
+	}}
+
+	settings := IndexSortSettings(IndexSortField{Field: "created_at", Order: "desc"}, IndexSortField{Field: "name.keyword"})
+	assert.Equal(t, map[string]any{
+		"index.sort.field": []string{"created_at", "name.keyword"},
+		"index.sort.order": []string{"desc", "asc"},
+	}, settings)
+
+	es := &fakeES{}
+	client := newTestClient(t, es)
+	err := client.CreateIndex(context.Background(), &CreateIndexRequest{Index: "orders_c1", Settings: settings, Mappings: mappings})
+	require.NoError(t, err)
+	assert.Len(t, es.requests, 1)
+
+	tests := []struct {
+		name     string
+		settings map[string]any
+		want     string
+	}{
+		{"unmapped", IndexSortSettings(IndexSortField{Field: "updated_at"}), `index sort field "updated_at" is not mapped`},
+		{"text", IndexSortSettings(IndexSortField{Field: "name"}), `index sort field "name" has type "text"`},
+		{"no_doc_values", IndexSortSettings(IndexSortField{Field: "status"}), `index sort field "status" has doc values disabled`},
+		{"nested", IndexSortSettings(IndexSortField{Field: "items.sku"}), `index sort field "items.sku" is inside nested field`},
+		{"order_count", map[string]any{"index": map[string]any{"sort": map[string]any{
+			"field": []any{"created_at"}, "order": []any{"desc", "asc"},
+		}}}, "index.sort.order has 2 values for 1 sort fields"},
+		{"bad_missing", map[string]any{"index.sort.field": "created_at", "index.sort.missing": "last"}, `index.sort.missing value "last" must be one of _last, _first`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.CreateIndex(context.Background(), &CreateIndexRequest{Index: "orders_c1", Settings: tt.settings, Mappings: mappings})
+			assert.ErrorContains(t, err, tt.want)
+			assert.Len(t, es.requests, 1)
+		})
+	}
+}
+
+func TestClient_IndicesOptions(t *testing.T) {
+	es := &fakeES{response: `{"count": 2, "deleted": 2, "hits": {"hits": []}}`}
+	client := newTestClient(t, es)
+	ctx := context.Background()
+	allow := false
+	const perCompany = "orders_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87"
+
+	_, err := client.Search(ctx, &SearchRequest{Index: perCompany + ",orders_archive", Target: IndexTargetPerCompany, IgnoreUnavailable: true, AllowNoIndices: &allow})
+	require.NoError(t, err)
+	_, err = client.Count(ctx, &CountRequest{Index: "orders_*", CompanyID: "c1", IgnoreUnavailable: true})
+	require.NoError(t, err)
+	_, err = client.DeleteByQuery(ctx, &DeleteByQueryRequest{Index: perCompany, AllowNoIndices: &allow,
+		Query: map[string]any{"query": map[string]any{"term": map[string]any{"status": "draft"}}}})
+	require.NoError(t, err)
+	// Point-in-time keeps options it was opened with
+	pit := "pit-1"
+	_, err = client.Search(ctx, &SearchRequest{Index: perCompany, PointInTime: &pit, IgnoreUnavailable: true, AllowNoIndices: &allow})
+	require.NoError(t, err)
+
+	var got []string
+	for _, req := range es.requests {
+		got = append(got, req.Method+" "+req.URL.Path+"?"+req.URL.RawQuery)
+	}
+	assert.Equal(t, []string{
+		"POST /" + perCompany + ",orders_archive/_search?allow_no_indices=false&ignore_unavailable=true",
+		"POST /orders_*/_count?ignore_unavailable=true&routing=c1",
+		"POST /" + perCompany + "/_delete_by_query?allow_no_indices=false",
+		"POST /_search?",
+	}, got)
+	assert.JSONEq(t, `{"query": {"term": {"status": "draft"}}}`, es.bodies[2])
+	assert.JSONEq(t, `{"query": {"bool": {"filter": [{"term": {"company_id.keyword": "c1"}}]}}}`, es.bodies[1])
+}
+
+func TestClient_Search_Preference(t *testing.T) {
+	es := &fakeES{response: `{"hits": {"hits": []}}`}
+	client := newTestClient(t, es)
+	ctx := context.Background()
+
+	_, err := client.Search(ctx, &SearchRequest{Index: "orders_shared", CompanyID: "c1", Preference: "session-42"})
+	require.NoError(t, err)
+	_, err = client.Search(ctx, &SearchRequest{Index: "orders_shared", CompanyID: "c1", Preference: "_local", Routing: "r1"})
+	require.NoError(t, err)
+	// Not allowed with point-in-time
+	pit := "pit-1"
+	_, err = client.Search(ctx, &SearchRequest{Index: "orders_shared", CompanyID: "c1", Preference: "session-42", PointInTime: &pit})
+	require.NoError(t, err)
+
+	require.Len(t, es.requests, 3)
+	assert.Equal(t, "/orders_shared/_search", es.requests[0].URL.Path)
+	assert.Equal(t, "preference=session-42&routing=c1", es.requests[0].URL.RawQuery)
+	assert.Equal(t, "preference=_local&routing=r1", es.requests[1].URL.RawQuery)
+	assert.Equal(t, "/_search", es.requests[2].URL.Path)
+	assert.Empty(t, es.requests[2].URL.RawQuery)
+	assert.JSONEq(t, `{
+		"query": {"bool": {"filter": [{"term": {"company_id.keyword": "c1"}}]}},
+		"pit": {"id": "pit-1", "keep_alive": "60000ms"}
+	}`, es.bodies[2])
+}
+
+func TestClient_Search_TimeoutPartialResults(t *testing.T) {
+	es := &fakeES{response: `{"took": 1500, "timed_out": true, "_shards": {"total": 2, "successful": 1, "failed": 1},
+		"hits": {"total": {"value": 1, "relation": "eq"}, "hits": [{"_id": "o1"}]}}`}
+	client := newTestClient(t, es)
+	partial := false
+
+	resp, err := client.Search(context.Background(), &SearchRequest{
+		Index:               "orders_shared",
+		CompanyID:           "c1",
+		Timeout:             1500 * time.Millisecond,
+		AllowPartialResults: &partial,
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.TimedOut)
+	assert.Equal(t, 1500, resp.Took)
+	assert.Equal(t, 1.0, resp.Shards["failed"])
+
+	assert.Equal(t, "/orders_shared/_search", es.requests[0].URL.Path)
+	assert.Equal(t, "allow_partial_search_results=false&routing=c1", es.requests[0].URL.RawQuery)
+	assert.JSONEq(t, `{
+		"query": {"bool": {"filter": [{"term": {"company_id.keyword": "c1"}}]}},
+		"timeout": "1500ms"
+	}`, es.bodies[0])
+}
+
+func TestRegistry_UsageSnapshot(t *testing.T) {
+	gold := &fakeES{response: `{
+		"indices": {"count": 12, "shards": {"total": 48}, "store": {"size_in_bytes": 1073741824}},
+		"nodes": {"fs": {"total_in_bytes": 10737418240, "available_in_bytes": 8589934592},
+			"jvm": {"mem": {"heap_used_in_bytes": 536870912, "heap_max_in_bytes": 2147483648}}}
+	}`}
+	reg := NewRegistry("tier-gold")
+	reg.byName["tier-gold"] = Entry{Name: "tier-gold", Version: 9, BaseURL: "http://gold:9200", ES: gold}
+	reg.byName["tier-silver"] = Entry{Name: "tier-silver", Version: 8, Err: errors.New("invalid base URL")}
+
+	usage, err := reg.UsageSnapshot(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []ClusterUsage{{
+		Cluster:            "tier-gold",
+		IndexCount:         12,
+		ShardCount:         48,
+		DiskUsedBytes:      1073741824,
+		DiskTotalBytes:     10737418240,
+		DiskAvailBytes:     8589934592,
+		JVMHeapUsedBytes:   536870912,
+		JVMHeapMaxBytes:    2147483648,
+		JVMHeapUsedPercent: 25,
+	}}, usage)
+
+	require.Len(t, gold.requests, 1)
+	assert.Equal(t, http.MethodGet, gold.requests[0].Method)
+	assert.Equal(t, "/_cluster/stats", gold.requests[0].URL.Path)
+	assert.Empty(t, gold.requests[0].URL.RawQuery)
+	assert.Empty(t, gold.bodies[0])
+
+	gold.status = http.StatusForbidden
+	_, err = reg.UsageSnapshot(context.Background())
+	assert.ErrorContains(t, err, `failed to get usage of cluster "tier-gold"`)
+}
+
+func TestClient_StaticHeaders(t *testing.T) {
+	es := &fakeES{response: `{"count": 4}`}
+	headers := http.Header{"X-Found-Cluster": {"gold"}, "X-Team": {"search", "billing"}}
+	client, err := NewClientWithHeaders(es, "http://localhost:9200", headers, nil)
+	require.NoError(t, err)
+	headers.Set("X-Team", "changed")
+
+	count, err := client.Count(context.Background(), &CountRequest{Index: "orders_shared", CompanyID: "c1"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, count.Count)
+	assert.Equal(t, "/orders_shared/_count", es.requests[0].URL.Path)
+	assert.Equal(t, "routing=c1", es.requests[0].URL.RawQuery)
+	assert.Equal(t, []string{"gold"}, es.requests[0].Header.Values("X-Found-Cluster"))
+	assert.Equal(t, []string{"search", "billing"}, es.requests[0].Header.Values("X-Team"))
+	assert.Equal(t, "application/json", es.requests[0].Header.Get("Content-Type"))
+
+	// Registry clients send ClusterConfig.Headers through ES client transport
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{"count": 1}`))
+	}))
+	defer server.Close()
+	reg, err := NewRegistryFromConfig(&Config{
+		DefaultCluster: "tier-gold",
+		Clusters: map[string]ClusterConfig{
+			"tier-gold": {Version: 9, Addresses: []string{server.URL}, Headers: http.Header{"X-Found-Cluster": {"gold"}}},
+		},
+	})
+	require.NoError(t, err)
+	client, err = reg.GetTypedClient("")
+	require.NoError(t, err)
+	_, err = client.Count(context.Background(), &CountRequest{Index: "orders_shared", CompanyID: "c1"})
+	require.NoError(t, err)
+	assert.Equal(t, "gold", got.Get("X-Found-Cluster"))
+}
+
+func TestClient_UpdateByQuery(t *testing.T) {
+	es := &fakeES{response: `{"took": 12, "total": 3, "updated": 2, "batches": 1, "version_conflicts": 1, "failures": []}`}
+	client := newTestClient(t, es)
+	ctx := context.Background()
+	script := map[string]any{"script": map[string]any{"source": "ctx._source.currency = 'UZS'"}}
+
+	resp, err := client.UpdateByQuery(ctx, &UpdateByQueryRequest{Index: "orders_shared", CompanyID: "c1", Query: script, Conflicts: "proceed"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Total)
+	assert.Equal(t, 2, resp.Updated)
+	assert.Equal(t, 1, resp.VersionConflicts)
+	assert.Empty(t, resp.Task)
+	assert.Equal(t, "/orders_shared/_update_by_query", es.requests[0].URL.Path)
+	assert.Equal(t, "conflicts=proceed&routing=c1", es.requests[0].URL.RawQuery)
+	assert.JSONEq(t, `{
+		"script": {"source": "ctx._source.currency = 'UZS'"},
+		"query": {"bool": {"filter": [{"term": {"company_id.keyword": "c1"}}]}}
+	}`, es.bodies[0])
+
+	// Asynchronous run returns task ID only
+	es.response = `{"task": "node-1:42"}`
+	wait := false
+	rps := 500.5
+	resp, err = client.UpdateByQuery(ctx, &UpdateByQueryRequest{
+		Index:             "orders_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87",
+		Query:             script,
+		WaitForCompletion: &wait,
+		Slices:            "auto",
+		RequestsPerSecond: &rps,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "node-1:42", resp.Task)
+	assert.Equal(t, "/orders_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87/_update_by_query", es.requests[1].URL.Path)
+	assert.Equal(t, "requests_per_second=500.5&slices=auto&wait_for_completion=false", es.requests[1].URL.RawQuery)
+	assert.JSONEq(t, `{"script": {"source": "ctx._source.currency = 'UZS'"}}`, es.bodies[1])
+}
+
+func TestClient_ProxyURL(t *testing.T) {
+	var requestURI, proxyAuth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI, proxyAuth = r.RequestURI, r.Header.Get("Proxy-Authorization")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{"count": 7}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:s3cret@", 1)
+	reg, err := NewRegistryFromConfig(&Config{
+		DefaultCluster: "tier-gold",
+		Clusters: map[string]ClusterConfig{
+			"tier-gold": {Version: 8, Addresses: []string{"http://es-gold.internal:9200"}, ProxyURL: proxyURL},
+		},
+	})
+	require.NoError(t, err)
+	client, err := reg.GetTypedClient("")
+	require.NoError(t, err)
+
+	count, err := client.Count(context.Background(), &CountRequest{Index: "orders_shared", CompanyID: "c1"})
+	require.NoError(t, err)
+	assert.Equal(t, 7, count.Count)
+	// Proxy gets absolute-form request of cluster address with proxy credentials
+	assert.Equal(t, "http://es-gold.internal:9200/orders_shared/_count?routing=c1", requestURI)
+	assert.Equal(t, "Basic dXNlcjpzM2NyZXQ=", proxyAuth)
+}
+
+func TestClient_Tasks(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"completed": false, "task": {"node": "n1", "id": 42, "action": "indices:data/write/update/byquery",
+			"cancellable": true, "status": {"total": 10, "updated": 4}}}`},
+		{body: `{"tasks": [
+			{"node": "n1", "id": 42, "type": "transport", "action": "indices:data/write/update/byquery", "running_time_in_nanos": 1500000000},
+			{"node": "n2", "id": 7, "action": "indices:data/write/reindex", "parent_task_id": "n1:42"}
+		]}`},
+		{body: `{"node_failures": []}`},
+		{body: `{"node_failures": [{"reason": "task is not cancellable"}]}`},
+		{status: http.StatusNotFound, body: `{"error": {"type": "resource_not_found_exception"}}`},
+	}}
+	client := newTestClient(t, es)
+	ctx := context.Background()
+
+	task, err := client.GetTask(ctx, "n1:42")
+	require.NoError(t, err)
+	assert.False(t, task.Completed)
+	assert.Equal(t, "n1:42", task.Task.TaskID())
+	assert.True(t, task.Task.Cancellable)
+	assert.Equal(t, 4.0, task.Task.Status["updated"])
+
+	tasks, err := client.ListTasks(ctx, &ListTasksRequest{
+		Actions:      []string{"*byquery", "*reindex"},
+		Nodes:        []string{"n1", "n2"},
+		ParentTaskID: "n1:42",
+		Detailed:     true,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, int64(1500000000), tasks[0].RunningTimeInNanos)
+	assert.Equal(t, "n1:42", tasks[1].ParentTaskID)
+
+	require.NoError(t, client.CancelTask(ctx, "n1:42"))
+	assert.ErrorContains(t, client.CancelTask(ctx, "n2:7"), "failed to cancel task n2:7: task is not cancellable")
+	_, err = client.GetTask(ctx, "n3:1")
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+
+	assert.Equal(t, []string{
+		"GET /_tasks/n1:42",
+		"GET /_tasks",
+		"POST /_tasks/n1:42/_cancel",
+		"POST /_tasks/n2:7/_cancel",
+		"GET /_tasks/n3:1",
+	}, es.paths)
+	assert.Equal(t, []string{"", "actions=%2Abyquery%2C%2Areindex&detailed=true&group_by=none&nodes=n1%2Cn2&parent_task_id=n1%3A42", "", "", ""}, es.queries)
+	assert.Equal(t, []string{"", "", "", "", ""}, es.bodies)
+	_, err = client.GetTask(ctx, "")
+	assert.ErrorContains(t, err, "task ID is required")
+	assert.Len(t, es.paths, 5)
+}
+
+func TestRegistry_ReindexAcrossClusters(t *testing.T) {
+	dst := &scriptedES{responses: []scriptedResponse{
+		{body: `{"task": "n1:5"}`},
+		{body: `{"completed": false, "task": {"node": "n1", "id": 5, "status": {"total": 3, "created": 1}}}`},
+		{body: `{"completed": true, "response": {"took": 40, "total": 3, "created": 2, "updated": 1, "batches": 1, "failures": []}}`},
+		{body: `{"task": "n1:6"}`},
+		{body: `{"completed": true, "error": {"type": "illegal_argument_exception", "reason": "[silver:9200] not whitelisted in reindex.remote.whitelist"}}`},
+	}}
+	reg := NewRegistry("tier-gold")
+	reg.byName["tier-gold"] = Entry{Name: "tier-gold", Version: 9, BaseURL: "http://gold:9200", ES: dst}
+	reg.byName["tier-silver"] = Entry{Name: "tier-silver", Version: 8, BaseURL: "http://silver:9200", ES: &fakeES{}}
+	reg.configs["tier-silver"] = ClusterConfig{Username: "reindexer", Password: "secret"}
+	ctx := context.Background()
+
+	rps := 100.0
+	var polls int
+	result, err := reg.ReindexAcrossClusters(ctx, "tier-silver", "orders_c1", "tier-gold", "orders_c1", &ReindexOptions{
+		Query:             map[string]any{"range": map[string]any{"created_at": map[string]any{"gte": "2025-01-01"}}},
+		BatchSize:         500,
+		OpType:            "create",
+		RequestsPerSecond: &rps,
+		PollInterval:      time.Millisecond,
+		OnPoll:            func(*GetTaskResponse) { polls++ },
+		RemoteHost:        "https://silver.internal:9200",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ReindexResult{TaskID: "n1:5", Took: 40, Total: 3, Created: 2, Updated: 1, Batches: 1, Failures: []map[string]interface{}{}}, *result)
+	assert.Equal(t, 2, polls)
+
+	assert.Equal(t, []string{"POST /_reindex", "GET /_tasks/n1:5", "GET /_tasks/n1:5"}, dst.paths)
+	assert.Equal(t, "requests_per_second=100&wait_for_completion=false", dst.queries[0])
+	assert.JSONEq(t, `{
+		"source": {
+			"index": "orders_c1",
+			"query": {"range": {"created_at": {"gte": "2025-01-01"}}},
+			"size": 500,
+			"remote": {"host": "https://silver.internal:9200", "username": "reindexer", "password": "secret"}
+		},
+		"dest": {"index": "orders_c1", "op_type": "create"}
+	}`, dst.bodies[0])
+
+	// Reindex within cluster has no remote source; task error is returned
+	_, err = reg.ReindexAcrossClusters(ctx, "tier-gold", "orders_c1", "tier-gold", "orders_c1_v2", &ReindexOptions{PollInterval: time.Millisecond})
+	assert.ErrorContains(t, err, "task n1:6 failed: [silver:9200] not whitelisted in reindex.remote.whitelist")
+	assert.Equal(t, "wait_for_completion=false", dst.queries[3])
+	assert.JSONEq(t, `{"source": {"index": "orders_c1"}, "dest": {"index": "orders_c1_v2"}}`, dst.bodies[3])
+
+	_, err = reg.ReindexAcrossClusters(ctx, "tier-silver", "", "tier-gold", "orders_c1", nil)
+	assert.ErrorContains(t, err, "source and destination index names are required")
+	assert.Len(t, dst.paths, 5)
+}
+
+func TestClient_Rollover(t *testing.T) {
+	es := &fakeES{response: `{"acknowledged": true, "shards_acknowledged": true, "old_index": "orders-000001",
+		"new_index": "orders-000002", "rolled_over": true, "dry_run": false,
+		"conditions": {"[max_age: 30d]": true, "[max_docs: 1000000]": false}}`}
+	client := newTestClient(t, es)
+	ctx := context.Background()
+
+	resp, err := client.Rollover(ctx, &RolloverRequest{
+		Alias: "orders_write",
+		Conditions: RolloverConditions{
+			MaxAge:              "30d",
+			MaxDocs:             1000000,
+			MaxSize:             "100gb",
+			MaxPrimaryShardSize: "50gb",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &RolloverResponse{
+		Acknowledged:       true,
+		ShardsAcknowledged: true,
+		OldIndex:           "orders-000001",
+		NewIndex:           "orders-000002",
+		RolledOver:         true,
+		Conditions:         map[string]bool{"[max_age: 30d]": true, "[max_docs: 1000000]": false},
+	}, resp)
+	assert.Equal(t, "POST /orders_write/_rollover", es.requests[0].Method+" "+es.requests[0].URL.Path)
+	assert.Empty(t, es.requests[0].URL.RawQuery)
+	assert.JSONEq(t, `{"conditions": {"max_age": "30d", "max_docs": 1000000, "max_size": "100gb", "max_primary_shard_size": "50gb"}}`, es.bodies[0])
+
+	// Named new index, dry run and unconditional rollover without body
+	_, err = client.Rollover(ctx, &RolloverRequest{Alias: "orders_write", NewIndex: "orders-2025.06", DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, "/orders_write/_rollover/orders-2025.06", es.requests[1].URL.Path)
+	assert.Equal(t, "dry_run=true", es.requests[1].URL.RawQuery)
+	assert.Empty(t, es.bodies[1])
+
+	es.status = http.StatusBadRequest
+	_, err = client.Rollover(ctx, &RolloverRequest{Alias: "orders_write"})
+	assert.ErrorContains(t, err, "rollover returned status code 400")
+	_, err = client.Rollover(ctx, &RolloverRequest{})
+	assert.ErrorContains(t, err, "alias is required")
+	assert.Len(t, es.requests, 3)
+}
+
+func TestClient_ILMPolicy(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"acknowledged": true}`},
+		{body: `{"orders_retention": {"version": 3, "modified_date": "2025-06-01T10:00:00.000Z", "policy": {
+			"phases": {"hot": {"min_age": "0ms", "actions": {"rollover": {"max_age": "30d"}}}, "delete": {"min_age": "365d", "actions": {"delete": {}}}},
+			"_meta": {"owner": "orders"}
+		}}}`},
+		{body: `{"acknowledged": true}`},
+		{status: http.StatusNotFound, body: `{"error": {"type": "resource_not_found_exception"}}`},
+	}}
+	client := newTestClient(t, es)
+	ctx := context.Background()
+
+	policy := &ILMPolicy{
+		Phases: map[string]ILMPhase{
+			"hot":    {Actions: map[string]any{"rollover": map[string]any{"max_age": "30d"}}},
+			"delete": {MinAge: "365d", Actions: map[string]any{"delete": map[string]any{}}},
+		},
+	}
+	require.NoError(t, client.PutILMPolicy(ctx, "orders_retention", policy))
+	info, err := client.GetILMPolicy(ctx, "orders_retention")
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteILMPolicy(ctx, "orders_retention"))
+	_, err = client.GetILMPolicy(ctx, "orders_retention")
+	assert.ErrorContains(t, err, "get_ilm_policy returned status code 404")
+
+	assert.Equal(t, []string{
+		"PUT /_ilm/policy/orders_retention",
+		"GET /_ilm/policy/orders_retention",
+		"DELETE /_ilm/policy/orders_retention",
+		"GET /_ilm/policy/orders_retention",
+	}, es.paths)
+	assert.JSONEq(t, `{"policy": {"phases": {
+		"hot": {"actions": {"rollover": {"max_age": "30d"}}},
+		"delete": {"min_age": "365d", "actions": {"delete": {}}}
+	}}}`, es.bodies[0])
+	assert.Equal(t, []string{"", "", ""}, es.bodies[1:])
+
+	assert.Equal(t, 3, info.Version)
+	assert.Equal(t, "2025-06-01T10:00:00.000Z", info.ModifiedDate)
+	assert.Equal(t, "365d", info.Policy.Phases["delete"].MinAge)
+	assert.Equal(t, map[string]any{"max_age": "30d"}, info.Policy.Phases["hot"].Actions["rollover"])
+	assert.Equal(t, map[string]any{"owner": "orders"}, info.Policy.Meta)
+
+	// ILM settings are sent with created index
+	fake := &fakeES{}
+	client = newTestClient(t, fake)
+	require.NoError(t, client.CreateIndex(ctx, &CreateIndexRequest{Index: "orders-000001", Settings: ILMSettings("orders_retention", "orders_write")}))
+	assert.Equal(t, "PUT /orders-000001", fake.requests[0].Method+" "+fake.requests[0].URL.Path)
+	assert.JSONEq(t, `{"settings": {"index.lifecycle.name": "orders_retention", "index.lifecycle.rollover_alias": "orders_write"}}`, fake.bodies[0])
+	assert.Equal(t, map[string]any{"index.lifecycle.name": "orders_retention"}, ILMSettings("orders_retention", ""))
+}
+
+func TestResolver_MultipleIndices(t *testing.T) {
+	const companyID = "5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87"
+	tests := []struct {
+		name       string
+		response   string
+		wantSearch string
+		wantWrite  string
+		wantTarget IndexTarget
+	}{
+		{
+			name:       "single index",
+			response:   `{"cluster_name": "tier-gold", "index_name": "orders_shared"}`,
+			wantSearch: "orders_shared",
+			wantWrite:  "orders_shared",
+			wantTarget: IndexTargetShared,
+		},
+		{
+			name:       "index list",
+			response:   `{"cluster_name": "tier-gold", "index_name": "orders_c1", "indices": ["orders_c1_2025-05", "orders_c1_2025-06"], "write_index": "orders_c1_2025-06"}`,
+			wantSearch: "orders_c1_2025-05,orders_c1_2025-06",
+			wantWrite:  "orders_c1_2025-06",
+			wantTarget: IndexTargetPerCompany,
+		},
+		{
+			name:       "index pattern wins over list",
+			response:   `{"cluster_name": "tier-gold", "index_name": "orders_c1", "indices": ["orders_c1_2025-05"], "index_pattern": "orders_c1_*"}`,
+			wantSearch: "orders_c1_*",
+			wantWrite:  "orders_c1",
+			wantTarget: IndexTargetPerCompany,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var syncBodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				syncBodies = append(syncBodies, r.Method+" "+r.URL.Path+" "+string(body))
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			es := &fakeES{response: `{"hits": {"hits": []}}`}
+			reg := NewRegistry("tier-gold")
+			reg.byName["tier-gold"] = Entry{Name: "tier-gold", Version: 9, BaseURL: "http://gold:9200", ES: es}
+			r, err := NewResolver(ResolverConfig{Registry: reg, Redis: unavailableRedis(), SyncURL: server.URL, HTTPClient: server.Client()})
+			require.NoError(t, err)
+			ctx := context.Background()
+
+			client, index, err := r.ResolveSearch(ctx, companyID, "orders")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSearch, index)
+			_, writeIndex, err := r.ResolveWrite(ctx, companyID, "orders")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWrite, writeIndex)
+			target, err := r.ResolveSearchTarget(ctx, companyID, "orders")
+			require.NoError(t, err)
+			assert.Equal(t, ResolvedTarget{ClusterName: "tier-gold", Index: tt.wantSearch, Target: tt.wantTarget, Client: target.Client}, *target)
+
+			require.Len(t, syncBodies, 3)
+			assert.Equal(t, "POST /v1/company/refresh-es-info-cache "+`{"company_id":"`+companyID+`","type":"orders"}`, strings.TrimSpace(syncBodies[0]))
+
+			_, err = client.Search(ctx, &SearchRequest{Index: target.Index, Target: target.Target, CompanyID: companyID})
+			require.NoError(t, err)
+			assert.Equal(t, "/"+tt.wantSearch+"/_search", es.requests[0].URL.Path)
+			if tt.wantTarget == IndexTargetPerCompany {
+				assert.Empty(t, es.requests[0].URL.RawQuery)
+				assert.JSONEq(t, `{}`, es.bodies[0])
+			} else {
+				assert.Equal(t, "routing="+companyID, es.requests[0].URL.RawQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package esclient
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultPageTokenTTL matches default PIT keep alive of search pages.
+const defaultPageTokenTTL = defaultPITKeepAlive
+
+// PageTokenConfig configures PageTokens.
+type PageTokenConfig struct {
+	Secret []byte        // HMAC key signing tokens, required
+	TTL    time.Duration // Token lifetime; should not exceed PIT keep alive (default: 1m)
+	Clock  Clock         // Time source of expiry (default: system clock)
+}
+
+// PageTokens issues and verifies opaque signed cursor tokens encoding PIT ID and search_after values,
+// so HTTP APIs can offer cursor pagination without exposing Elasticsearch internals.
+// Tokens are bound to company, so token of one company is rejected for another.
+type PageTokens struct {
+	secret []byte
+	ttl    time.Duration
+	clock  Clock
+}
+
+// pageToken is signed payload of token.
+type pageToken struct {
+	PITID       string `json:"p"`
+	SearchAfter []any  `json:"a"`
+	CompanyID   string `json:"c,omitempty"`
+	ExpiresAt   int64  `json:"e"`
+}
+
+// NewPageTokens creates page token issuer.
+func NewPageTokens(cfg PageTokenConfig) (*PageTokens, error) {
+	if len(cfg.Secret) == 0 {
+		return nil, errors.New("page token secret is required")
+	}
+	t := &PageTokens{
+		secret: append([]byte(nil), cfg.Secret...),
+		ttl:    cfg.TTL,
+		clock:  cfg.Clock,
+	}
+	if t.ttl <= 0 {
+		t.ttl = defaultPageTokenTTL
+	}
+	if t.clock == nil {
+		t.clock = systemClock{}
+	}
+	return t, nil
+}
+
+// Next returns token of page following resp, or "" if resp is the last page
+// (fewer hits than pageSize). Search must use PIT and sort, so hits carry sort values.
+func (t *PageTokens) Next(resp *SearchResponse, companyID string, pageSize int) (string, error) {
+	hits := resp.Hits.Hits
+	if len(hits) == 0 || len(hits) < pageSize {
+		return "", nil
+	}
+	if resp.PitID == "" {
+		return "", errors.New("page token requires point-in-time search")
+	}
+	sortValues, ok := hits[len(hits)-1]["sort"].([]interface{})
+	if !ok {
+		return "", errors.New("page token requires sorted search")
+	}
+
+	payload, err := json.Marshal(pageToken{
+		PITID:       resp.PitID,
+		SearchAfter: sortValues,
+		CompanyID:   companyID,
+		ExpiresAt:   t.clock.Now().Add(t.ttl).Unix(),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode page token")
+	}
+
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(t.sign(payload)), nil
+}
+
+// Apply verifies token and sets PIT and search_after of req to continue from it.
+// Returns ErrInvalidPageToken if token is malformed, forged or issued for another company,
+// and ErrPageTokenExpired if it has expired.
+func (t *PageTokens) Apply(token, companyID string, req *SearchRequest) error {
+	enc := base64.RawURLEncoding
+	encPayload, encSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidPageToken
+	}
+	payload, err := enc.DecodeString(encPayload)
+	if err != nil {
+		return ErrInvalidPageToken
+	}
+	sig, err := enc.DecodeString(encSig)
+	if err != nil || !hmac.Equal(sig, t.sign(payload)) {
+		return ErrInvalidPageToken
+	}
+
+	var decoded pageToken
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return ErrInvalidPageToken
+	}
+	if decoded.CompanyID != companyID {
+		return ErrInvalidPageToken
+	}
+	if t.clock.Now().Unix() >= decoded.ExpiresAt {
+		return ErrPageTokenExpired
+	}
+
+	req.PointInTime = &decoded.PITID
+	req.SearchAfter = decoded.SearchAfter
+	return nil
+}
+
+// sign returns HMAC-SHA256 of payload.
+func (t *PageTokens) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
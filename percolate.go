@@ -0,0 +1,144 @@
+package esclient
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// defaultPercolatorField is field storing percolator queries when not specified.
+const defaultPercolatorField = "query"
+
+// PercolatorMappings returns mappings of percolator index: percolator field storing queries
+// and properties of percolated documents, which stored queries are parsed against.
+func PercolatorMappings(field string, documentProperties map[string]any) map[string]any {
+	if field == "" {
+		field = defaultPercolatorField
+	}
+	properties := make(map[string]any, len(documentProperties)+2)
+	for name, prop := range documentProperties {
+		properties[name] = prop
+	}
+	properties[field] = map[string]any{"type": "percolator"}
+	if _, ok := properties["company_id"]; !ok {
+		properties["company_id"] = companyIDMapping()
+	}
+	return map[string]any{"properties": properties}
+}
+
+// PercolatorQuery represents query stored in percolator index.
+type PercolatorQuery struct {
+	Index     string         // Percolator index name
+	ID        string         // Query ID (e.g., saved search ID)
+	CompanyID string         // Owner company; stamped into stored document on shared index
+	Field     string         // Percolator field (default: "query")
+	Query     map[string]any // Query clause (e.g., {"match": {"status": "new"}})
+	Metadata  map[string]any // Additional fields stored with query (e.g., alert name), optional
+	Refresh   string         // Refresh policy: "true", "false" or "wait_for", optional
+}
+
+// PercolateRequest represents search of stored queries matching documents.
+type PercolateRequest struct {
+	Index     string         // Percolator index name
+	CompanyID string         // Company ID; on shared index only its queries are matched
+	Field     string         // Percolator field (default: "query")
+	Documents []any          // Documents matched against stored queries
+	Filter    map[string]any // Filter on stored queries metadata, optional
+	Size      *int           // Number of matched queries to return, optional
+}
+
+// PercolateResponse represents stored queries matching percolated documents.
+type PercolateResponse struct {
+	Total   int
+	Matches []PercolateMatch
+}
+
+// PercolateMatch represents stored query matching at least one document.
+type PercolateMatch struct {
+	ID     string         // Stored query ID
+	Source map[string]any // Stored query document, including metadata
+	Slots  []int          // Positions in PercolateRequest.Documents of matched documents
+}
+
+// PutPercolatorQuery stores query in percolator index.
+func (c *Client) PutPercolatorQuery(ctx context.Context, q *PercolatorQuery) (*CreateDocumentResponse, error) {
+	if q.Query == nil {
+		return nil, errors.New("percolator query is required")
+	}
+	field := q.Field
+	if field == "" {
+		field = defaultPercolatorField
+	}
+
+	doc := make(map[string]any, len(q.Metadata)+1)
+	for k, v := range q.Metadata {
+		doc[k] = v
+	}
+	doc[field] = q.Query
+
+	body, err := c.jsonBody(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal percolator query")
+	}
+
+	return c.CreateDocument(ctx, &CreateDocumentRequest{
+		Index:          q.Index,
+		DocumentID:     q.ID,
+		Body:           body,
+		Refresh:        q.Refresh,
+		CompanyID:      q.CompanyID,
+		StampCompanyID: true,
+	})
+}
+
+// Percolate returns stored queries matching any of documents.
+// Company filter of shared index is applied to stored queries like in Search.
+func (c *Client) Percolate(ctx context.Context, req *PercolateRequest) (*PercolateResponse, error) {
+	if len(req.Documents) == 0 {
+		return nil, errors.New("documents are required")
+	}
+	field := req.Field
+	if field == "" {
+		field = defaultPercolatorField
+	}
+
+	filter := []any{map[string]any{
+		"percolate": map[string]any{
+			"field":     field,
+			"documents": req.Documents,
+		},
+	}}
+	if req.Filter != nil {
+		filter = append(filter, req.Filter)
+	}
+
+	resp, err := c.Search(ctx, &SearchRequest{
+		Index:     req.Index,
+		CompanyID: req.CompanyID,
+		Size:      req.Size,
+		Query: map[string]any{
+			"query": map[string]any{"bool": map[string]any{"filter": filter}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PercolateResponse{Total: resp.Hits.Total.Value}
+	for _, hit := range resp.Hits.Hits {
+		match := PercolateMatch{}
+		match.ID, _ = hit["_id"].(string)
+		match.Source, _ = hit["_source"].(map[string]any)
+		if fields, ok := hit["fields"].(map[string]any); ok {
+			slots, _ := fields["_percolator_document_slot"].([]any)
+			for _, slot := range slots {
+				if n, ok := toInt(slot); ok {
+					match.Slots = append(match.Slots, n)
+				}
+			}
+		}
+		result.Matches = append(result.Matches, match)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Pipeline represents ingest pipeline.
+type Pipeline struct {
+	Description string           `json:"description,omitempty"` // Human-readable description, optional
+	Processors  []map[string]any `json:"processors"`            // Processors (e.g., {"set": {"field": "indexed_at", "value": "{{_ingest.timestamp}}"}})
+	OnFailure   []map[string]any `json:"on_failure,omitempty"`  // Processors run on failure, optional
+	Version     int              `json:"version,omitempty"`     // Pipeline version, optional
+	Meta        map[string]any   `json:"_meta,omitempty"`       // Arbitrary metadata, optional
+}
+
+// PutPipeline creates or updates ingest pipeline.
+func (c *Client) PutPipeline(ctx context.Context, id string, pipeline *Pipeline) error {
+	if id == "" {
+		return errors.New("pipeline id is required")
+	}
+	if pipeline == nil {
+		return errors.New("pipeline is required")
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("/_ingest/pipeline/%s", id), nil, pipeline, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "put_pipeline", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// GetPipeline returns ingest pipeline by id.
+// Returns *StatusError with status 404 if pipeline does not exist.
+func (c *Client) GetPipeline(ctx context.Context, id string) (*Pipeline, error) {
+	if id == "" {
+		return nil, errors.New("pipeline id is required")
+	}
+
+	var resp map[string]Pipeline
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("/_ingest/pipeline/%s", id), nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "get_pipeline", StatusCode: status, body: errBody}
+	}
+
+	pipeline, ok := resp[id]
+	if !ok {
+		return nil, errors.Errorf("pipeline %q not found in response", id)
+	}
+
+	return &pipeline, nil
+}
+
+// DeletePipeline deletes ingest pipeline by id.
+func (c *Client) DeletePipeline(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("pipeline id is required")
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodDelete, fmt.Sprintf("/_ingest/pipeline/%s", id), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "delete_pipeline", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// PipelineNone disables default or final pipeline of index.
+const PipelineNone = "_none"
+
+// PipelineExists checks if ingest pipeline exists.
+func (c *Client) PipelineExists(ctx context.Context, id string) (bool, error) {
+	_, err := c.GetPipeline(ctx, id)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PipelineSettings returns index settings running pipelines on every write, for use in CreateIndexRequest.Settings.
+// Default pipeline runs when request sets no pipeline; final pipeline always runs last.
+// Empty names are omitted.
+func PipelineSettings(defaultPipeline, finalPipeline string) map[string]any {
+	settings := map[string]any{}
+	if defaultPipeline != "" {
+		settings["index.default_pipeline"] = defaultPipeline
+	}
+	if finalPipeline != "" {
+		settings["index.final_pipeline"] = finalPipeline
+	}
+	return settings
+}
+
+// SetIndexPipelines sets default and final pipelines of existing index, so enrichment is enforced
+// at index level instead of relying on writers. Empty name leaves setting unchanged, PipelineNone clears it.
+// Pipelines are verified to exist on cluster first, since ES rejects writes to index with missing pipeline.
+func (c *Client) SetIndexPipelines(ctx context.Context, index, defaultPipeline, finalPipeline string) error {
+	if defaultPipeline == "" && finalPipeline == "" {
+		return errors.New("default or final pipeline is required")
+	}
+
+	for _, id := range []string{defaultPipeline, finalPipeline} {
+		if id == "" || id == PipelineNone {
+			continue
+		}
+		exists, err := c.PipelineExists(ctx, id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check pipeline %q", id)
+		}
+		if !exists {
+			return errors.Errorf("pipeline %q does not exist", id)
+		}
+	}
+
+	return c.PutSettings(ctx, index, PipelineSettings(defaultPipeline, finalPipeline))
+}
@@ -0,0 +1,283 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultPITIdleTimeout   = 5 * time.Minute
+	defaultPITSweepInterval = 15 * time.Second
+)
+
+// PITManagerConfig configures point-in-time lifecycle manager.
+type PITManagerConfig struct {
+	KeepAlive     time.Duration // Keep alive of PITs, extended on every search (default: 1m)
+	IdleTimeout   time.Duration // PIT not searched for this long is considered abandoned and closed (default: 5m)
+	SweepInterval time.Duration // Interval of extending active and closing abandoned PITs (default: 15s)
+}
+
+// PITManager tracks open point-in-times of a client. While PIT is actively used its
+// keep alive is extended in background, so slow page processing does not expire it;
+// PITs not used for IdleTimeout are closed to release search contexts.
+type PITManager struct {
+	client *Client
+	cfg    PITManagerConfig
+
+	mu   sync.Mutex
+	pits map[*ManagedPIT]struct{}
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// ManagedPIT is a point-in-time tracked by PITManager.
+type ManagedPIT struct {
+	manager *PITManager
+	index   string
+
+	mu         sync.Mutex
+	id         string
+	lastUsed   time.Time // last search by caller
+	lastExtend time.Time // last keep alive extension (search or background)
+	closed     bool
+}
+
+// NewPITManager creates PIT manager and starts background sweeper.
+// Close must be called to stop sweeper and close remaining PITs.
+func NewPITManager(client *Client, cfg PITManagerConfig) (*PITManager, error) {
+	if client == nil {
+		return nil, errors.New("client is required")
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = defaultPITKeepAlive
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultPITIdleTimeout
+	}
+	if cfg.SweepInterval <= 0 {
+		cfg.SweepInterval = defaultPITSweepInterval
+	}
+	if cfg.SweepInterval >= cfg.KeepAlive/2 {
+		return nil, errors.Errorf("sweep interval %s must be less than half of keep alive %s", cfg.SweepInterval, cfg.KeepAlive)
+	}
+
+	m := &PITManager{
+		client: client,
+		cfg:    cfg,
+		pits:   make(map[*ManagedPIT]struct{}),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go m.run()
+
+	return m, nil
+}
+
+// Open opens point-in-time on index and starts tracking it.
+func (m *PITManager) Open(ctx context.Context, index string) (*ManagedPIT, error) {
+	pit, err := m.client.OpenPIT(ctx, &OpenPITRequest{
+		Index:     index,
+		KeepAlive: formatDuration(m.cfg.KeepAlive),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.client.clock.Now()
+	p := &ManagedPIT{
+		manager:    m,
+		index:      index,
+		id:         pit.ID,
+		lastUsed:   now,
+		lastExtend: now,
+	}
+
+	m.mu.Lock()
+	m.pits[p] = struct{}{}
+	m.mu.Unlock()
+
+	return p, nil
+}
+
+// Len returns number of tracked open PITs.
+func (m *PITManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pits)
+}
+
+// Sweep extends keep alive of active PITs that would expire before next sweep
+// and closes PITs idle for IdleTimeout. Returns number of closed PITs.
+// Called periodically by background sweeper.
+func (m *PITManager) Sweep(ctx context.Context) int {
+	m.mu.Lock()
+	pits := make([]*ManagedPIT, 0, len(m.pits))
+	for p := range m.pits {
+		pits = append(pits, p)
+	}
+	m.mu.Unlock()
+
+	closed := 0
+	now := m.client.clock.Now()
+	for _, p := range pits {
+		p.mu.Lock()
+		idle := now.Sub(p.lastUsed)
+		sinceExtend := now.Sub(p.lastExtend)
+		p.mu.Unlock()
+
+		switch {
+		case idle >= m.cfg.IdleTimeout:
+			m.client.log.DebugWithCtx(ctx, "elasticsearch closing abandoned PIT", map[string]interface{}{
+				"index": p.index,
+				"idle":  idle.String(),
+			})
+			if err := p.Close(ctx); err == nil {
+				closed++
+			}
+		case sinceExtend+m.cfg.SweepInterval >= m.cfg.KeepAlive/2:
+			if err := p.extend(ctx); err != nil {
+				m.client.log.DebugWithCtx(ctx, "elasticsearch PIT keep alive extension failed", map[string]interface{}{
+					"index": p.index,
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+
+	return closed
+}
+
+// Close stops background sweeper and closes all tracked PITs.
+func (m *PITManager) Close(ctx context.Context) error {
+	select {
+	case <-m.stop:
+	default:
+		close(m.stop)
+	}
+	<-m.done
+
+	m.mu.Lock()
+	pits := make([]*ManagedPIT, 0, len(m.pits))
+	for p := range m.pits {
+		pits = append(pits, p)
+	}
+	m.mu.Unlock()
+
+	errs := &MultiError{}
+	for _, p := range pits {
+		if err := p.Close(ctx); err != nil {
+			errs.Errors = append(errs.Errors, err)
+		}
+	}
+	return errs.errOrNil()
+}
+
+// run periodically sweeps PITs until Close is called.
+func (m *PITManager) run() {
+	defer close(m.done)
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-m.client.clock.After(m.cfg.SweepInterval):
+			m.Sweep(context.Background())
+		}
+	}
+}
+
+// ID returns current PIT ID.
+func (p *ManagedPIT) ID() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.id
+}
+
+// Search searches within PIT, extending its keep alive. Index of request defaults to PIT index
+// and is used only for company filter detection.
+func (p *ManagedPIT) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, errors.New("PIT is closed")
+	}
+	id := p.id
+	p.mu.Unlock()
+
+	reqCopy := *req
+	if reqCopy.Index == "" {
+		reqCopy.Index = p.index
+	}
+	reqCopy.PointInTime = &id
+	reqCopy.PITKeepAlive = p.manager.cfg.KeepAlive
+
+	resp, err := p.manager.client.Search(ctx, &reqCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.manager.client.clock.Now()
+	p.mu.Lock()
+	if resp.PitID != "" {
+		p.id = resp.PitID
+	}
+	p.lastUsed = now
+	p.lastExtend = now
+	p.mu.Unlock()
+
+	return resp, nil
+}
+
+// Close closes PIT and stops tracking it. Closing already closed PIT is no-op.
+func (p *ManagedPIT) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	id := p.id
+	p.mu.Unlock()
+
+	p.manager.mu.Lock()
+	delete(p.manager.pits, p)
+	p.manager.mu.Unlock()
+
+	return p.manager.client.ClosePIT(ctx, id)
+}
+
+// extend extends PIT keep alive with empty search.
+func (p *ManagedPIT) extend(ctx context.Context) error {
+	id := p.ID()
+	body := map[string]any{
+		"size":             0,
+		"track_total_hits": false,
+		"pit": map[string]any{
+			"id":         id,
+			"keep_alive": formatDuration(p.manager.cfg.KeepAlive),
+		},
+	}
+
+	var resp SearchResponse
+	status, errBody, err := p.manager.client.doJSONRequest(ctx, http.MethodPost, "/_search", nil, body, &resp)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return &StatusError{Op: "extend_pit", StatusCode: status, body: errBody}
+	}
+
+	p.mu.Lock()
+	if resp.PitID != "" {
+		p.id = resp.PitID
+	}
+	p.lastExtend = p.manager.client.clock.Now()
+	p.mu.Unlock()
+
+	return nil
+}
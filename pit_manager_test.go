@@ -0,0 +1,57 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPITManager(t *testing.T) {
+	ctx := context.Background()
+	es := &fakeES{response: `{"id": "pit-1", "pit_id": "pit-2", "hits": {"hits": []}}`}
+	m, err := NewPITManager(newTestClient(t, es), PITManagerConfig{})
+	require.NoError(t, err)
+
+	pit, err := m.Open(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "pit-1", pit.ID())
+	assert.Equal(t, "60000ms", es.requests[0].URL.Query().Get("keep_alive"))
+
+	_, err = pit.Search(ctx, &SearchRequest{CompanyID: "c1", SearchAfter: []any{42}})
+	require.NoError(t, err)
+	assert.Equal(t, "pit-2", pit.ID())
+
+	searchReq := es.requests[1]
+	assert.Equal(t, "/_search", searchReq.URL.Path)
+	assert.Empty(t, searchReq.URL.Query().Get("routing"))
+	var body map[string]any
+	require.NoError(t, json.Unmarshal([]byte(es.bodies[1]), &body))
+	assert.Equal(t, map[string]any{"id": "pit-1", "keep_alive": "60000ms"}, body["pit"])
+	assert.Equal(t, []any{float64(42)}, body["search_after"])
+
+	// Active PIT close to keep alive expiry is extended
+	pit.mu.Lock()
+	pit.lastExtend = time.Now().Add(-25 * time.Second)
+	pit.mu.Unlock()
+	assert.Equal(t, 0, m.Sweep(ctx))
+	require.Len(t, es.requests, 3)
+	assert.Equal(t, "/_search", es.requests[2].URL.Path)
+
+	// Abandoned PIT is closed
+	pit.mu.Lock()
+	pit.lastUsed = time.Now().Add(-10 * time.Minute)
+	pit.mu.Unlock()
+	assert.Equal(t, 1, m.Sweep(ctx))
+	assert.Equal(t, http.MethodDelete, es.requests[3].Method)
+	assert.JSONEq(t, `{"id": "pit-2"}`, es.bodies[3])
+	assert.Equal(t, 0, m.Len())
+
+	_, err = pit.Search(ctx, &SearchRequest{CompanyID: "c1"})
+	require.Error(t, err)
+	require.NoError(t, m.Close(ctx))
+}
@@ -0,0 +1,297 @@
+package esclient
+
+import (
+	"context"
+	"sort"
+	"unicode/utf8"
+)
+
+// Product facet aggregation names of ProductSearchQuery.
+const (
+	ProductFacetCategories = "categories"
+	ProductFacetStatuses   = "statuses"
+	ProductFacetPrice      = "price"
+)
+
+// defaultFacetSize is number of buckets of product terms facets.
+const defaultFacetSize = 50
+
+// ProductSearchParams parameterizes storefront product search.
+type ProductSearchParams struct {
+	Text        string       // Full-text query on name, SKU and barcodes, optional
+	CategoryIDs []string     // Keep products of any of categories, optional
+	Statuses    []string     // Keep products with any of statuses, optional
+	MinPrice    *float64     // Minimum price, inclusive, optional
+	MaxPrice    *float64     // Maximum price, inclusive, optional
+	Facets      bool         // Return category, status and price facets
+	From        int          // Offset for pagination
+	Size        int          // Number of results (default: 20)
+	Sort        []SortClause // Sort clauses (default: relevance)
+}
+
+// ProductFacets represents facets returned by ProductSearchQuery.
+type ProductFacets struct {
+	Categories map[string]int // Category ID -> product count
+	Statuses   map[string]int // Status -> product count
+	MinPrice   float64        // Lowest price among matching products
+	MaxPrice   float64        // Highest price among matching products
+}
+
+// ProductSearchQuery builds full-text product search with filters and facets.
+// Filters go to post_filter when facets are requested, so facet counts cover every
+// product matching text, as storefront filter panels expect. Company filter is added
+// to query by Search, so facets never include other companies' products.
+func ProductSearchQuery(p ProductSearchParams) *SearchRequest {
+	size := p.Size
+	if size <= 0 {
+		size = 20
+	}
+
+	var query map[string]any
+	if p.Text != "" {
+		query = map[string]any{
+			"bool": map[string]any{
+				"should": []any{
+					map[string]any{"match": map[string]any{"name": map[string]any{"query": p.Text, "operator": "and", "fuzziness": "AUTO"}}},
+					map[string]any{"term": map[string]any{"sku": map[string]any{"value": p.Text, "boost": 5}}},
+					map[string]any{"term": map[string]any{"barcodes": map[string]any{"value": p.Text, "boost": 5}}},
+				},
+				"minimum_should_match": 1,
+			},
+		}
+	}
+
+	var filters []any
+	if len(p.CategoryIDs) > 0 {
+		filters = append(filters, map[string]any{"terms": map[string]any{"category_ids": p.CategoryIDs}})
+	}
+	if len(p.Statuses) > 0 {
+		filters = append(filters, map[string]any{"terms": map[string]any{"status": p.Statuses}})
+	}
+	if p.MinPrice != nil || p.MaxPrice != nil {
+		price := map[string]any{}
+		if p.MinPrice != nil {
+			price["gte"] = *p.MinPrice
+		}
+		if p.MaxPrice != nil {
+			price["lte"] = *p.MaxPrice
+		}
+		filters = append(filters, map[string]any{"range": map[string]any{"price": price}})
+	}
+
+	body := map[string]any{}
+	switch {
+	case p.Facets:
+		if query != nil {
+			body["query"] = query
+		}
+		if len(filters) > 0 {
+			body["post_filter"] = map[string]any{"bool": map[string]any{"filter": filters}}
+		}
+		body["aggs"] = map[string]any{
+			ProductFacetCategories: map[string]any{"terms": map[string]any{"field": "category_ids", "size": defaultFacetSize}},
+			ProductFacetStatuses:   map[string]any{"terms": map[string]any{"field": "status", "size": defaultFacetSize}},
+			ProductFacetPrice:      map[string]any{"stats": map[string]any{"field": "price"}},
+		}
+	case query != nil || len(filters) > 0:
+		boolQuery := map[string]any{}
+		if query != nil {
+			boolQuery["must"] = []any{query}
+		}
+		if len(filters) > 0 {
+			boolQuery["filter"] = filters
+		}
+		body["query"] = map[string]any{"bool": boolQuery}
+	}
+
+	req := &SearchRequest{
+		Query: body,
+		Size:  &size,
+		Sort:  p.Sort,
+	}
+	if p.From > 0 {
+		req.From = &p.From
+	}
+	return req
+}
+
+// ParseProductFacets parses facets of ProductSearchQuery response aggregations.
+func ParseProductFacets(aggs map[string]interface{}) *ProductFacets {
+	facets := &ProductFacets{
+		Categories: termsCounts(aggs[ProductFacetCategories]),
+		Statuses:   termsCounts(aggs[ProductFacetStatuses]),
+	}
+	if price, ok := aggs[ProductFacetPrice].(map[string]interface{}); ok {
+		facets.MinPrice, _ = price["min"].(float64)
+		facets.MaxPrice, _ = price["max"].(float64)
+	}
+	return facets
+}
+
+// termsCounts returns bucket key -> doc count of terms aggregation.
+func termsCounts(raw interface{}) map[string]int {
+	agg, _ := raw.(map[string]interface{})
+	counts := make(map[string]int)
+	for _, bucket := range aggBuckets(agg["buckets"]) {
+		key, _ := bucket["key"].(string)
+		count, _ := toInt(bucket["doc_count"])
+		counts[key] = count
+	}
+	return counts
+}
+
+// ProductAutocompleteParams parameterizes product autocomplete.
+type ProductAutocompleteParams struct {
+	Prefix string // Typed prefix
+	Size   int    // Number of products (default: 10)
+
+	Contexts       map[string][]string // Keep products whose field has any of values (e.g., {"category_ids": {"c1"}}), optional
+	Fuzzy          *SuggestFuzzy       // Tolerate typos in name, optional
+	KeepDuplicates bool                // Keep products whose names differ only in case and whitespace
+}
+
+// ProductAutocompleteQuery builds search-as-you-type query matching name prefix,
+// or exact SKU and barcode. Only fields needed for suggestion list are returned.
+// Contexts and Fuzzy apply as in SuggestRequest; duplicates are collapsed by Autocomplete,
+// so the query asks for twice as many products unless KeepDuplicates is set.
+func ProductAutocompleteQuery(p ProductAutocompleteParams) *SearchRequest {
+	size := p.Size
+	if size <= 0 {
+		size = 10
+	}
+	if !p.KeepDuplicates {
+		size *= 2
+	}
+
+	should := []any{
+		map[string]any{"match_phrase_prefix": map[string]any{"name": map[string]any{"query": p.Prefix, "max_expansions": 50}}},
+		map[string]any{"prefix": map[string]any{"sku": map[string]any{"value": p.Prefix, "boost": 2}}},
+		map[string]any{"term": map[string]any{"barcodes": map[string]any{"value": p.Prefix, "boost": 3}}},
+	}
+	if match := p.Fuzzy.nameMatch(p.Prefix); match != nil {
+		should = append(should, map[string]any{"match": map[string]any{"name": match}})
+	}
+	boolQuery := map[string]any{
+		"should":               should,
+		"minimum_should_match": 1,
+	}
+
+	names := make([]string, 0, len(p.Contexts))
+	for name := range p.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var filters []any
+	for _, name := range names {
+		filters = append(filters, map[string]any{"terms": map[string]any{name: p.Contexts[name]}})
+	}
+	if len(filters) > 0 {
+		boolQuery["filter"] = filters
+	}
+
+	return &SearchRequest{
+		Query: map[string]any{
+			"query":   map[string]any{"bool": boolQuery},
+			"_source": []string{"id", "name", "sku"},
+		},
+		Size: &size,
+	}
+}
+
+// nameMatch returns fuzzy match of prefix on product name with completion suggester
+// defaults, or nil if fuzzy matching is off or prefix is shorter than MinLength.
+func (f *SuggestFuzzy) nameMatch(prefix string) map[string]any {
+	if f == nil {
+		return nil
+	}
+	minLength, prefixLength, fuzziness := 3, 1, "AUTO"
+	if f.MinLength > 0 {
+		minLength = f.MinLength
+	}
+	if f.PrefixLength > 0 {
+		prefixLength = f.PrefixLength
+	}
+	if f.Fuzziness != "" {
+		fuzziness = f.Fuzziness
+	}
+	if utf8.RuneCountInString(prefix) < minLength {
+		return nil
+	}
+	return map[string]any{
+		"query":         prefix,
+		"operator":      "and",
+		"fuzziness":     fuzziness,
+		"prefix_length": prefixLength,
+	}
+}
+
+// ProductSimilarQuery builds more_like_this query of products similar to product by name,
+// boosting products of the same categories and excluding product itself.
+// Product text is passed inline, so it works on shared and per-company indices alike.
+func ProductSimilarQuery(product *Product, size int) *SearchRequest {
+	if size <= 0 {
+		size = 10
+	}
+
+	should := []any{
+		map[string]any{"more_like_this": map[string]any{
+			"fields":        []string{"name"},
+			"like":          product.Name,
+			"min_term_freq": 1,
+			"min_doc_freq":  1,
+		}},
+	}
+	if len(product.CategoryIDs) > 0 {
+		should = append(should, map[string]any{"terms": map[string]any{"category_ids": product.CategoryIDs, "boost": 0.5}})
+	}
+
+	boolQuery := map[string]any{
+		"should":               should,
+		"minimum_should_match": 1,
+	}
+	if product.ID != "" {
+		boolQuery["must_not"] = []any{map[string]any{"ids": map[string]any{"values": []string{product.ID}}}}
+	}
+
+	return &SearchRequest{
+		Query: map[string]any{"query": map[string]any{"bool": boolQuery}},
+		Size:  &size,
+	}
+}
+
+// Find runs storefront product search. Facets, if requested, can be parsed from
+// result aggregations with ParseProductFacets.
+func (r *ProductRepository) Find(ctx context.Context, companyID string, params ProductSearchParams) (*SearchResult[Product], error) {
+	return r.Search(ctx, companyID, ProductSearchQuery(params))
+}
+
+// Autocomplete returns products matching typed prefix. Products with the same name up to
+// case and whitespace are collapsed to the best scored one unless KeepDuplicates is set.
+func (r *ProductRepository) Autocomplete(ctx context.Context, companyID string, params ProductAutocompleteParams) (*SearchResult[Product], error) {
+	result, err := r.Search(ctx, companyID, ProductAutocompleteQuery(params))
+	if err != nil || params.KeepDuplicates {
+		return result, err
+	}
+
+	size := params.Size
+	if size <= 0 {
+		size = 10
+	}
+	seen := make(map[string]bool, len(result.Items))
+	ids, items := result.IDs[:0], result.Items[:0]
+	for i, item := range result.Items {
+		key := suggestionKey(item.Name)
+		if seen[key] || len(items) == size {
+			continue
+		}
+		seen[key] = true
+		ids, items = append(ids, result.IDs[i]), append(items, item)
+	}
+	result.IDs, result.Items = ids, items
+	return result, nil
+}
+
+// Similar returns products similar to product.
+func (r *ProductRepository) Similar(ctx context.Context, companyID string, product *Product, size int) (*SearchResult[Product], error) {
+	return r.Search(ctx, companyID, ProductSimilarQuery(product, size))
+}
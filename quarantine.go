@@ -0,0 +1,125 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultQuarantinePrefix is prefix of quarantine index name used by WithQuarantine when not specified.
+// Prefix keeps company ID at the end of name, so per-company indices stay per-company.
+const DefaultQuarantinePrefix = "quarantine_"
+
+// mappingErrorTypes are bulk item error types caused by document not matching index mapping.
+var mappingErrorTypes = []string{
+	"mapper_parsing_exception",
+	"document_parsing_exception",
+	"strict_dynamic_mapping_exception",
+}
+
+// QuarantinedDocument is document of quarantine index: rejected document with its error,
+// kept until mapping or document is fixed and document is replayed to original index.
+type QuarantinedDocument struct {
+	Index         string          `json:"index"`
+	DocumentID    string          `json:"document_id"`
+	Error         string          `json:"error"`
+	Source        json.RawMessage `json:"source"`
+	QuarantinedAt time.Time       `json:"quarantined_at"`
+}
+
+// WithQuarantine makes UpsertMany write documents rejected with mapping or parse errors to
+// quarantine index <prefix><index> (default prefix: "quarantine_") instead of dropping them.
+// Such documents are reported in UpsertManyResult.Quarantined instead of Failed.
+func WithQuarantine(prefix string) ClientOption {
+	return func(c *Client) {
+		if prefix == "" {
+			prefix = DefaultQuarantinePrefix
+		}
+		c.quarantinePrefix = prefix
+	}
+}
+
+// QuarantineMappings returns mappings of quarantine index. Source is stored but not indexed,
+// so documents rejected by original mapping are always accepted.
+func QuarantineMappings() map[string]any {
+	return map[string]any{
+		"dynamic": false,
+		"properties": map[string]any{
+			"index":          map[string]any{"type": "keyword"},
+			"document_id":    map[string]any{"type": "keyword"},
+			"error":          map[string]any{"type": "text"},
+			"source":         map[string]any{"type": "object", "enabled": false},
+			"quarantined_at": map[string]any{"type": "date"},
+		},
+	}
+}
+
+// quarantine moves documents failed with mapping errors from result.Failed to quarantine index.
+// Documents whose quarantine write fails stay in result.Failed.
+func (c *Client) quarantine(ctx context.Context, index string, docs []UpsertDocument, result *UpsertManyResult) error {
+	var rejected []UpsertDocument
+	for _, doc := range docs {
+		if reason, ok := result.Failed[doc.ID]; ok && isMappingError(reason) {
+			rejected = append(rejected, doc)
+		}
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	quarantineIndex := c.quarantinePrefix + index
+	if _, err := c.EnsureIndex(ctx, &CreateIndexRequest{Index: quarantineIndex, Mappings: QuarantineMappings()}); err != nil {
+		return errors.Wrapf(err, "failed to create quarantine index %s", quarantineIndex)
+	}
+
+	now := c.clock.Now()
+	quarantined := make([]UpsertDocument, 0, len(rejected))
+	for _, doc := range rejected {
+		source, err := json.Marshal(doc.Body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode quarantined document %q", doc.ID)
+		}
+		quarantined = append(quarantined, UpsertDocument{ID: doc.ID, Body: QuarantinedDocument{
+			Index:         index,
+			DocumentID:    doc.ID,
+			Error:         result.Failed[doc.ID],
+			Source:        source,
+			QuarantinedAt: now,
+		}})
+	}
+
+	written, err := c.upsertMany(ctx, quarantineIndex, "", quarantined, ConflictReplace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write quarantine index %s", quarantineIndex)
+	}
+
+	if result.Quarantined == nil {
+		result.Quarantined = make(map[string]string)
+	}
+	for _, ids := range [][]string{written.Created, written.Updated} {
+		for _, id := range ids {
+			result.Quarantined[id] = result.Failed[id]
+			delete(result.Failed, id)
+		}
+	}
+
+	logWarn(ctx, c.log, "elasticsearch documents quarantined", map[string]interface{}{
+		"index":            index,
+		"quarantine_index": quarantineIndex,
+		"count":            len(written.Created) + len(written.Updated),
+	})
+	return nil
+}
+
+// isMappingError reports whether bulk item failure reason is mapping or parse error.
+func isMappingError(reason string) bool {
+	for _, errType := range mappingErrorTypes {
+		if strings.HasPrefix(reason, errType+":") {
+			return true
+		}
+	}
+	return false
+}
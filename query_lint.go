@@ -0,0 +1,263 @@
+package esclient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Lint issue severities.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// Lint rules.
+const (
+	LintRuleIsolation  = "isolation"
+	LintRuleDeprecated = "deprecated"
+	LintRuleCost       = "cost"
+)
+
+// Thresholds of cost rules.
+const (
+	lintMaxResultWindow = 10000 // default index.max_result_window
+	lintMaxTerms        = 10000 // terms values above which SearchByIDs chunking is advised
+)
+
+// LintIssue represents problem found in query body.
+type LintIssue struct {
+	Severity string // LintError or LintWarning
+	Rule     string // LintRuleIsolation, LintRuleDeprecated or LintRuleCost
+	Path     string // Location in query body (e.g., "query.bool.must[0].wildcard")
+	Message  string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s %s %s: %s", i.Severity, i.Rule, i.Path, i.Message)
+}
+
+// deprecatedQueries are query types removed or deprecated in ES 8/9, with replacement hint.
+var deprecatedQueries = map[string]string{
+	"filtered":    "use bool query with filter",
+	"and":         "use bool query with filter",
+	"or":          "use bool query with should",
+	"not":         "use bool query with must_not",
+	"missing":     "use bool must_not with exists",
+	"common":      "use match query",
+	"type":        "mapping types are removed",
+	"indices":     "search indices separately or filter on _index",
+	"geo_polygon": "use geo_shape query",
+}
+
+// compoundQueries maps compound query type to its child query keys.
+var compoundQueries = map[string][]string{
+	"bool":           {"must", "should", "filter", "must_not"},
+	"constant_score": {"filter"},
+	"function_score": {"query"},
+	"script_score":   {"query"},
+	"dis_max":        {"queries"},
+	"boosting":       {"positive", "negative"},
+	"nested":         {"query"},
+	"has_child":      {"query"},
+	"has_parent":     {"query"},
+}
+
+// LintQuery checks search body for tenant isolation issues, deprecated syntax and costly constructs.
+// Target is index target the query runs against; isolation rules apply to shared indices.
+// Issues are sorted by path.
+func LintQuery(body map[string]any, target IndexTarget) []LintIssue {
+	l := &queryLinter{target: target}
+
+	if target == IndexTargetShared {
+		if err := NewQueryMutator().InjectCompanyFilter(deepCopyMap(body), "lint", target); err != nil {
+			l.add(LintError, LintRuleIsolation, "query", "company filter cannot be injected: "+err.Error())
+		}
+	}
+
+	for _, key := range []string{"query", "post_filter"} {
+		if q, ok := body[key].(map[string]any); ok {
+			l.query(q, key)
+		}
+	}
+	for _, key := range []string{"aggs", "aggregations"} {
+		if aggs, ok := body[key].(map[string]any); ok {
+			l.aggs(aggs, key)
+		}
+	}
+
+	from, _ := toInt(body["from"])
+	size, _ := toInt(body["size"])
+	if from+size > lintMaxResultWindow {
+		l.add(LintWarning, LintRuleCost, "from", fmt.Sprintf("from+size %d exceeds %d, use search_after or Export", from+size, lintMaxResultWindow))
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool { return l.issues[i].Path < l.issues[j].Path })
+	return l.issues
+}
+
+// queryLinter collects issues while walking query body.
+type queryLinter struct {
+	target IndexTarget
+	issues []LintIssue
+}
+
+func (l *queryLinter) add(severity, rule, path, message string) {
+	l.issues = append(l.issues, LintIssue{Severity: severity, Rule: rule, Path: path, Message: message})
+}
+
+// query checks query clause {"<type>": {...}}.
+func (l *queryLinter) query(clause map[string]any, path string) {
+	for qtype, params := range clause {
+		p := path + "." + qtype
+
+		if hint, ok := deprecatedQueries[qtype]; ok {
+			l.add(LintError, LintRuleDeprecated, p, fmt.Sprintf("%s query is not supported: %s", qtype, hint))
+		}
+
+		if children, ok := compoundQueries[qtype]; ok {
+			paramsMap, _ := params.(map[string]any)
+			for _, child := range children {
+				l.children(paramsMap[child], p+"."+child)
+			}
+		}
+
+		switch qtype {
+		case "wildcard":
+			l.leadingWildcard(params, p)
+		case "regexp":
+			l.add(LintWarning, LintRuleCost, p, "regexp query scans terms dictionary, prefer keyword or ngram fields")
+		case "script", "script_score":
+			l.add(LintWarning, LintRuleCost, p, qtype+" query runs script per document")
+		case "query_string":
+			if m, ok := params.(map[string]any); ok && m["allow_leading_wildcard"] != false {
+				l.add(LintWarning, LintRuleCost, p, "query_string allows leading wildcards, set allow_leading_wildcard: false")
+			}
+		case "terms":
+			l.terms(params, p)
+		case "term", "match", "match_phrase":
+			l.companyField(params, p)
+		}
+	}
+}
+
+// children checks child queries of compound query, which may be single clause or array.
+func (l *queryLinter) children(v any, path string) {
+	switch c := v.(type) {
+	case map[string]any:
+		l.query(c, path)
+	case []any:
+		for i, item := range c {
+			if m, ok := item.(map[string]any); ok {
+				l.query(m, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+}
+
+// leadingWildcard reports wildcard patterns starting with wildcard.
+func (l *queryLinter) leadingWildcard(params any, path string) {
+	fields, _ := params.(map[string]any)
+	for field, v := range fields {
+		value := v
+		if m, ok := v.(map[string]any); ok {
+			value = m["value"]
+			if value == nil {
+				value = m["wildcard"]
+			}
+		}
+		s, _ := value.(string)
+		if strings.HasPrefix(s, "*") || strings.HasPrefix(s, "?") {
+			l.add(LintWarning, LintRuleCost, path+"."+field, "leading wildcard scans all terms of field")
+		}
+	}
+}
+
+// terms reports hard-coded company filter and large value lists.
+func (l *queryLinter) terms(params any, path string) {
+	fields, _ := params.(map[string]any)
+	for field, v := range fields {
+		values, ok := v.([]any)
+		if !ok {
+			continue
+		}
+		if isCompanyField(field) && l.target == IndexTargetShared {
+			l.add(LintWarning, LintRuleIsolation, path+"."+field, "hard-coded company filter, pass CompanyID to request instead")
+		}
+		if len(values) > lintMaxTerms {
+			l.add(LintWarning, LintRuleCost, path+"."+field, fmt.Sprintf("%d terms exceed %d, use SearchByIDs or chunk values", len(values), lintMaxTerms))
+		}
+	}
+}
+
+// companyField reports hard-coded company filter in term or match query.
+func (l *queryLinter) companyField(params any, path string) {
+	if l.target != IndexTargetShared {
+		return
+	}
+	fields, _ := params.(map[string]any)
+	for field := range fields {
+		if isCompanyField(field) {
+			l.add(LintWarning, LintRuleIsolation, path+"."+field, "hard-coded company filter, pass CompanyID to request instead")
+		}
+	}
+}
+
+// aggs checks aggregations {"<name>": {"<type>": {...}, "aggs": {...}}}.
+func (l *queryLinter) aggs(aggs map[string]any, path string) {
+	for name, def := range aggs {
+		defMap, ok := def.(map[string]any)
+		if !ok {
+			continue
+		}
+		p := path + "." + name
+
+		for atype, params := range defMap {
+			switch atype {
+			case "aggs", "aggregations":
+				if sub, ok := params.(map[string]any); ok {
+					l.aggs(sub, p+"."+atype)
+				}
+			case "global":
+				if l.target == IndexTargetShared {
+					l.add(LintError, LintRuleIsolation, p+".global", "global aggregation ignores query and company filter, exposing other companies")
+				}
+			case "date_histogram":
+				if m, ok := params.(map[string]any); ok && m["interval"] != nil {
+					l.add(LintError, LintRuleDeprecated, p+".date_histogram.interval", "interval is removed, use calendar_interval or fixed_interval")
+				}
+			case "filter":
+				if q, ok := params.(map[string]any); ok {
+					l.query(q, p+".filter")
+				}
+			case "terms":
+				if m, ok := params.(map[string]any); ok {
+					if size, ok := toInt(m["size"]); ok && size > lintMaxResultWindow {
+						l.add(LintWarning, LintRuleCost, p+".terms.size", fmt.Sprintf("terms aggregation size %d is expensive, use composite aggregation", size))
+					}
+				}
+			case "scripted_metric":
+				l.add(LintWarning, LintRuleCost, p+".scripted_metric", "scripted_metric runs scripts per document")
+			}
+		}
+	}
+}
+
+// isCompanyField reports whether field is company ID field.
+func isCompanyField(field string) bool {
+	return field == companyIDField || field == companyIDField+".keyword"
+}
+
+// toInt converts JSON number to int.
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
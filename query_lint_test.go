@@ -0,0 +1,45 @@
+package esclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintQuery(t *testing.T) {
+	raw := `{
+		"query": {"bool": {
+			"must": [{"wildcard": {"name": {"value": "*phone"}}}],
+			"filter": [{"term": {"company_id.keyword": "c1"}}, {"filtered": {}}]
+		}},
+		"aggs": {
+			"all": {"global": {}, "aggs": {"per_day": {"date_histogram": {"field": "created_at", "interval": "1d"}}}}
+		},
+		"from": 9990,
+		"size": 20
+	}`
+	var body map[string]any
+	require.NoError(t, json.Unmarshal([]byte(raw), &body))
+
+	issues := LintQuery(body, IndexTargetShared)
+
+	paths := make(map[string]string, len(issues))
+	for _, issue := range issues {
+		paths[issue.Path] = issue.Severity + " " + issue.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"aggs.all.global": "error isolation",
+		"aggs.all.aggs.per_day.date_histogram.interval": "error deprecated",
+		"from": "warning cost",
+		"query.bool.filter[0].term.company_id.keyword": "warning isolation",
+		"query.bool.filter[1].filtered":                "error deprecated",
+		"query.bool.must[0].wildcard.name":             "warning cost",
+	}, paths)
+
+	// Isolation rules don't apply to per-company index
+	for _, issue := range LintQuery(body, IndexTargetPerCompany) {
+		assert.NotEqual(t, LintRuleIsolation, issue.Rule)
+	}
+}
@@ -23,6 +23,26 @@ func DetectIndexTarget(indexName string) IndexTarget {
 	return IndexTargetShared
 }
 
+// indexTarget returns explicit target if set, otherwise target detected from index name.
+func indexTarget(target IndexTarget, index string) IndexTarget {
+	if target != "" {
+		return target
+	}
+	return DetectIndexTarget(index)
+}
+
+// routingFor returns explicit routing if set, otherwise CompanyID for shared indices.
+// Shared indices are routed by company_id at index time, so this limits search to one shard.
+func routingFor(routing, companyID string, target IndexTarget) string {
+	if routing != "" {
+		return routing
+	}
+	if target == IndexTargetShared {
+		return companyID
+	}
+	return ""
+}
+
 type QueryMutator struct{}
 
 func NewQueryMutator() *QueryMutator {
@@ -39,11 +59,7 @@ func (m *QueryMutator) InjectCompanyFilter(query map[string]any, companyID strin
 		return errors.New("companyID required for shared index")
 	}
 
-	companyFilter := map[string]any{
-		"term": map[string]any{
-			"company_id.keyword": companyID,
-		},
-	}
+	companyFilter := companyTermFilter(companyID)
 
 	queryMap, hasQuery := query["query"].(map[string]any)
 	if !hasQuery {
@@ -71,6 +87,15 @@ func (m *QueryMutator) InjectCompanyFilter(query map[string]any, companyID strin
 	return nil
 }
 
+// companyTermFilter returns term filter matching documents of company.
+func companyTermFilter(companyID string) map[string]any {
+	return map[string]any{
+		"term": map[string]any{
+			"company_id.keyword": companyID,
+		},
+	}
+}
+
 func (m *QueryMutator) injectIntoBool(boolMap map[string]any, filter map[string]any) error {
 	filterVal, hasFilter := boolMap["filter"]
 
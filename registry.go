@@ -1,26 +1,54 @@
 package esclient
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	elasticV8 "github.com/elastic/go-elasticsearch/v8"
 	elasticV9 "github.com/elastic/go-elasticsearch/v9"
 	"github.com/pkg/errors"
 )
 
+// versionDetectTimeout limits version detection request of every cluster at registry construction.
+const versionDetectTimeout = 10 * time.Second
+
 // Entry represents a registered Elasticsearch cluster with pre-created client.
 type Entry struct {
-	Name    string   // Cluster name
-	Version int      // Elasticsearch version (8 or 9)
-	BaseURL string   // Base URL for the cluster
-	ES      ESClient // Pre-created ES client
+	Name    string      // Cluster name
+	Version int         // Elasticsearch version (8 or 9)
+	BaseURL string      // Base URL for the cluster
+	ES      ESClient    // Pre-created ES client
+	Headers http.Header // Static headers applied by ES client transport
+	Err     error       // Client creation error if cluster is degraded (ES is nil)
 }
 
 // Registry manages multiple Elasticsearch clusters.
-// All clients are created once during initialization.
+// Clients are created once during initialization; clusters can be added, replaced
+// and removed at runtime (AddCluster, ReplaceCluster, RemoveCluster).
 type Registry struct {
-	defaultName string
-	byName      map[string]Entry
+	defaultName   string
+	mu            sync.RWMutex // guards byName, configs and generation
+	byName        map[string]Entry
+	configs       map[string]ClusterConfig // cluster configs, needed for cross-cluster operations
+	generation    uint64                   // incremented on every runtime cluster change
+	log           Logger
+	clientOpts    []ClientOption    // options of typed clients created by registry
+	bulkSizes     sync.Map          // cluster name -> *bulkSizer shared by its typed clients
+	fallbacks     sync.Map          // cluster name -> *fallbackState shared by its typed clients
+	stats         sync.Map          // cluster name -> *requestStats shared by its typed clients
+	timeouts      OperationTimeouts // registry-wide default deadlines of typed clients
+	detectVersion bool              // verify versions of clusters added at runtime
+	healthMu      sync.RWMutex
+	health        map[string]ClusterStatus // latest background health checks by cluster name
+	healthRunning bool                     // StartHealthChecks loop is running
 }
 
 // NewRegistry creates a new empty registry.
@@ -31,104 +59,335 @@ func NewRegistry(defaultName string) *Registry {
 	return &Registry{
 		defaultName: defaultName,
 		byName:      make(map[string]Entry),
+		configs:     make(map[string]ClusterConfig),
+		log:         noopLogger{},
 	}
 }
 
 // NewRegistryFromConfig creates registry from configuration.
 // All ES clients are created during initialization (one-time setup).
-func NewRegistryFromConfig(cfg *Config) (*Registry, error) {
-	return NewRegistryFromConfigWithLogger(cfg, nil)
-}
-
-// NewRegistryFromConfigWithLogger creates registry from configuration with logger.
-// All ES clients are created during initialization (one-time setup).
-func NewRegistryFromConfigWithLogger(cfg *Config, log Logger) (*Registry, error) {
+// Options are applied to every typed client created by registry (GetTypedClient, Resolver);
+// logger option is also used by registry itself.
+func NewRegistryFromConfig(cfg *Config, opts ...ClientOption) (*Registry, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, errors.Wrap(err, "invalid config")
 	}
 
-	log = safeLogger(log)
+	log := optionsLogger(opts)
 	reg := NewRegistry(cfg.DefaultCluster)
+	reg.log = log
+	reg.clientOpts = opts
+	reg.timeouts = cfg.Timeouts
+	reg.detectVersion = cfg.DetectVersion
 
-	for name, clusterCfg := range cfg.Clusters {
-		// Parse and validate base URL
-		baseURL := clusterCfg.Addresses[0]
-		u, err := url.Parse(baseURL)
-		if err != nil || u.Scheme == "" || u.Host == "" {
-			return nil, ErrInvalidBaseURL(name, baseURL)
-		}
+	names := make([]string, 0, len(cfg.Clusters))
+	for name := range cfg.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-		var client ESClient
-
-		// Create appropriate client based on version
-		switch clusterCfg.Version {
-		case 9:
-			cl, err := elasticV9.NewClient(elasticV9.Config{
-				Addresses: clusterCfg.Addresses,
-				Username:  clusterCfg.Username,
-				Password:  clusterCfg.Password,
-			})
-			if err != nil {
-				return nil, errors.Wrapf(err, "failed to create ES v9 client for %q", name)
-			}
-			client = NewESClientV9WithLogger(cl, u, log)
-
-		case 8:
-			cl, err := elasticV8.NewClient(elasticV8.Config{
-				Addresses: clusterCfg.Addresses,
-				Username:  clusterCfg.Username,
-				Password:  clusterCfg.Password,
-			})
-			if err != nil {
-				return nil, errors.Wrapf(err, "failed to create ES v8 client for %q", name)
+	// Attempt every cluster and report all failures at once
+	errs := &MultiError{}
+	for _, name := range names {
+		reg.configs[name] = cfg.Clusters[name].clone()
+
+		newEntryFn := newEntry
+		if cfg.DetectVersion {
+			newEntryFn = newDetectedEntry
+		}
+		entry, err := newEntryFn(name, cfg.Clusters[name], log)
+		if err == nil && cfg.AllowDegraded && !cfg.DetectVersion && name != cfg.DefaultCluster {
+			// Client creation doesn't connect, so unreachable cluster would pass unnoticed
+			err = probeEntry(name, entry)
+		}
+		if err != nil {
+			if cfg.AllowDegraded && name != cfg.DefaultCluster {
+				log.Debug("elasticsearch registry cluster degraded", map[string]interface{}{
+					"cluster_name": name,
+					"error":        err.Error(),
+				})
+				reg.byName[name] = Entry{
+					Name:    name,
+					Version: cfg.Clusters[name].Version,
+					BaseURL: cfg.Clusters[name].Addresses[0],
+					Err:     err,
+				}
+				continue
 			}
-			client = NewESClientV8WithLogger(cl, u, log)
+			errs.Errors = append(errs.Errors, err)
+			continue
+		}
+		reg.byName[name] = entry
+		if cluster := reg.configs[name]; cluster.Version == 0 {
+			cluster.Version = entry.Version
+			reg.configs[name] = cluster
+		}
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	return reg, nil
+}
+
+// NewRegistryFromConfigWithLogger creates registry from configuration with logger.
+// Equivalent to NewRegistryFromConfig with WithLogger option.
+func NewRegistryFromConfigWithLogger(cfg *Config, log Logger) (*Registry, error) {
+	return NewRegistryFromConfig(cfg, WithLogger(log))
+}
+
+// newEntry creates registry entry with pre-created ES client for cluster.
+func newEntry(name string, clusterCfg ClusterConfig, log Logger) (Entry, error) {
+	// Parse and validate base URL
+	baseURL := clusterCfg.Addresses[0]
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return Entry{}, ErrInvalidBaseURL(name, baseURL)
+	}
+
+	transport, err := clusterTransport(clusterCfg)
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "failed to create transport for %q", name)
+	}
 
-		default:
-			// This should never happen after Validate()
-			return nil, ErrInvalidESVersion(name, clusterCfg.Version)
+	var client ESClient
+
+	// Create appropriate client based on version
+	switch clusterCfg.Version {
+	case 9:
+		cl, err := elasticV9.NewClient(elasticV9.Config{
+			Addresses:    clusterCfg.Addresses,
+			Username:     clusterCfg.Username,
+			Password:     clusterCfg.Password,
+			APIKey:       encodeAPIKey(clusterCfg.APIKey),
+			ServiceToken: clusterCfg.ServiceToken,
+			Header:       clusterCfg.Headers,
+			Transport:    transport,
+			Selector:     newWeightedSelector(clusterCfg),
+		})
+		if err != nil {
+			return Entry{}, errors.Wrapf(err, "failed to create ES v9 client for %q", name)
 		}
+		client = NewESClientV9WithLogger(cl, u, log)
 
-		reg.byName[name] = Entry{
-			Name:    name,
-			Version: clusterCfg.Version,
-			BaseURL: baseURL,
-			ES:      client,
+	case 8:
+		cl, err := elasticV8.NewClient(elasticV8.Config{
+			Addresses:    clusterCfg.Addresses,
+			Username:     clusterCfg.Username,
+			Password:     clusterCfg.Password,
+			APIKey:       encodeAPIKey(clusterCfg.APIKey),
+			ServiceToken: clusterCfg.ServiceToken,
+			Header:       clusterCfg.Headers,
+			Transport:    transport,
+			Selector:     newWeightedSelector(clusterCfg),
+		})
+		if err != nil {
+			return Entry{}, errors.Wrapf(err, "failed to create ES v8 client for %q", name)
 		}
+		client = NewESClientV8WithLogger(cl, u, log)
+
+	default:
+		// This should never happen after Validate()
+		return Entry{}, ErrInvalidESVersion(name, clusterCfg.Version)
 	}
 
-	return reg, nil
+	return Entry{
+		Name:    name,
+		Version: clusterCfg.Version,
+		BaseURL: baseURL,
+		ES:      client,
+		Headers: clusterCfg.Headers,
+	}, nil
 }
 
-// GetClient returns pre-created ES client by cluster name.
-// Returns error if cluster not found.
-func (r *Registry) GetClient(clusterName string) (ESClient, error) {
-	if clusterName == "" {
-		clusterName = r.defaultName
+// newDetectedEntry creates registry entry of cluster and verifies its version with GET /.
+// Cluster with Version 0 gets client of detected version.
+func newDetectedEntry(name string, clusterCfg ClusterConfig, log Logger) (Entry, error) {
+	probeCfg := clusterCfg
+	if probeCfg.Version == 0 {
+		probeCfg.Version = 8
+	}
+	entry, err := newEntry(name, probeCfg, log)
+	if err != nil {
+		return Entry{}, err
 	}
 
-	entry, ok := r.byName[clusterName]
-	if !ok {
-		return nil, ErrClusterNotFound(clusterName)
+	ctx, cancel := context.WithTimeout(context.Background(), versionDetectTimeout)
+	defer cancel()
+	detected, err := detectVersion(ctx, entry.ES)
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "failed to detect ES version of %q", name)
+	}
+
+	switch {
+	case clusterCfg.Version == 0 && detected != 8 && detected != 9:
+		return Entry{}, ErrInvalidESVersion(name, detected)
+	case clusterCfg.Version == 0 && detected != probeCfg.Version:
+		clusterCfg.Version = detected
+		return newEntry(name, clusterCfg, log)
+	case clusterCfg.Version != 0 && detected != clusterCfg.Version:
+		return Entry{}, ErrVersionMismatch(name, clusterCfg.Version, detected)
+	}
+	return entry, nil
+}
+
+// probeEntry checks that cluster of entry answers GET / within versionDetectTimeout.
+func probeEntry(name string, entry Entry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), versionDetectTimeout)
+	defer cancel()
+	if _, err := detectVersion(ctx, entry.ES); err != nil {
+		return errors.Wrapf(err, "cluster %q is unreachable", name)
+	}
+	return nil
+}
+
+// detectVersion returns major version reported by cluster root endpoint.
+func detectVersion(ctx context.Context, es ESClient) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create info request")
+	}
+	resp, err := es.Do(ctx, req)
+	if err != nil {
+		return 0, errors.Wrap(err, "info request failed")
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, &StatusError{Op: "info", StatusCode: resp.StatusCode}
+	}
+	var info struct {
+		Version struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return 0, errors.Wrap(err, "failed to decode info response")
+	}
+	major, _, _ := strings.Cut(info.Version.Number, ".")
+	version, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, errors.Errorf("unexpected version number %q", info.Version.Number)
+	}
+	return version, nil
+}
+
+// encodeAPIKey returns API key in base64 encoded "id:key" form expected by ES clients.
+// Already encoded key is returned as is (base64 alphabet has no colon).
+func encodeAPIKey(apiKey string) string {
+	if !strings.Contains(apiKey, ":") {
+		return apiKey
+	}
+	return base64.StdEncoding.EncodeToString([]byte(apiKey))
+}
+
+// GetClient returns pre-created ES client by cluster name.
+// Returns error if cluster not found or *DegradedClusterError if cluster is degraded.
+func (r *Registry) GetClient(clusterName string) (ESClient, error) {
+	entry, err := r.GetEntry(clusterName)
+	if err != nil {
+		return nil, err
 	}
 
 	return entry.ES, nil
 }
 
 // GetEntry returns full entry (client + metadata) by cluster name.
+// Returns *DegradedClusterError if cluster is degraded.
 func (r *Registry) GetEntry(clusterName string) (Entry, error) {
 	if clusterName == "" {
 		clusterName = r.defaultName
 	}
 
-	entry, ok := r.byName[clusterName]
+	entry, ok := r.entry(clusterName)
 	if !ok {
 		return Entry{}, ErrClusterNotFound(clusterName)
 	}
 
+	if entry.Err != nil {
+		return Entry{}, &DegradedClusterError{Cluster: clusterName, Err: entry.Err}
+	}
+
 	return entry, nil
 }
 
+// Degraded returns client creation errors of degraded clusters by cluster name.
+func (r *Registry) Degraded() map[string]error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]error)
+	for name, entry := range r.byName {
+		if entry.Err != nil {
+			result[name] = entry.Err
+		}
+	}
+	return result
+}
+
+// GetTypedClient returns typed client for cluster by name.
+func (r *Registry) GetTypedClient(clusterName string) (*Client, error) {
+	entry, err := r.GetEntry(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(entry.ES, entry.BaseURL, r.entryOpts(entry)...)
+}
+
+// entryOpts returns options of typed client of registered cluster.
+func (r *Registry) entryOpts(entry Entry) []ClientOption {
+	sizer, _ := r.bulkSizes.LoadOrStore(entry.Name, &bulkSizer{})
+	stats, _ := r.stats.LoadOrStore(entry.Name, &requestStats{})
+	opts := append([]ClientOption{
+		withVersion(entry.Version),
+		withBulkSizer(sizer.(*bulkSizer)),
+		withRequestStats(stats.(*requestStats)),
+	}, r.clientOpts...)
+	// Cluster config is more specific than registry-wide options
+	cfg, _ := r.config(entry.Name)
+	if cfg.GzipThreshold > 0 {
+		opts = append(opts, WithCompression(cfg.GzipThreshold))
+	}
+	if timeouts := r.timeouts.merge(cfg.Timeouts); !timeouts.isZero() {
+		opts = append(opts, WithOperationTimeouts(timeouts))
+	}
+	if fallback := r.readFallback(entry.Name, cfg); fallback != nil {
+		opts = append(opts, withFallback(fallback))
+	}
+	return opts
+}
+
+// readFallback returns fallback of typed clients of cluster, nil if not configured or
+// fallback cluster is not usable (removed at runtime or degraded).
+func (r *Registry) readFallback(name string, cfg ClusterConfig) *readFallback {
+	if cfg.FallbackCluster == "" {
+		return nil
+	}
+	entry, ok := r.entry(cfg.FallbackCluster)
+	if !ok || entry.ES == nil {
+		return nil
+	}
+	baseURL, err := parseBaseURL(entry.BaseURL)
+	if err != nil {
+		return nil
+	}
+
+	threshold := cfg.FallbackThreshold
+	if threshold <= 0 {
+		threshold = defaultFallbackThreshold
+	}
+	state, _ := r.fallbacks.LoadOrStore(name, &fallbackState{})
+	return &readFallback{
+		primary:   name,
+		cluster:   entry.Name,
+		es:        entry.ES,
+		baseURL:   baseURL,
+		threshold: threshold,
+		state:     state.(*fallbackState),
+	}
+}
+
 // Default returns the default cluster client.
 func (r *Registry) Default() (ESClient, error) {
 	return r.GetClient(r.defaultName)
@@ -136,9 +395,115 @@ func (r *Registry) Default() (ESClient, error) {
 
 // ListClusters returns list of all registered cluster names.
 func (r *Registry) ListClusters() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	names := make([]string, 0, len(r.byName))
 	for name := range r.byName {
 		names = append(names, name)
 	}
 	return names
 }
+
+// AddCluster creates client of new cluster and registers it under cfg.Name,
+// so new tier can be brought online without restart.
+func (r *Registry) AddCluster(cfg ClusterConfig) error {
+	entry, err := r.newRuntimeEntry(cfg)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byName[cfg.Name]; ok {
+		return errors.Errorf("cluster %q is already registered", cfg.Name)
+	}
+	r.register(entry, cfg)
+	return nil
+}
+
+// ReplaceCluster creates new client of registered cluster cfg.Name (e.g., with rotated credentials
+// or new addresses) and swaps it in. Typed clients obtained before keep using old client.
+func (r *Registry) ReplaceCluster(cfg ClusterConfig) error {
+	entry, err := r.newRuntimeEntry(cfg)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byName[cfg.Name]; !ok {
+		return ErrClusterNotFound(cfg.Name)
+	}
+	r.register(entry, cfg)
+	r.bulkSizes.Delete(cfg.Name)
+	r.fallbacks.Delete(cfg.Name)
+	return nil
+}
+
+// RemoveCluster unregisters cluster. Default cluster cannot be removed.
+// Typed clients obtained before keep working until their owners drop them.
+func (r *Registry) RemoveCluster(name string) error {
+	if name == r.defaultName {
+		return errors.Errorf("default cluster %q cannot be removed", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byName[name]; !ok {
+		return ErrClusterNotFound(name)
+	}
+	delete(r.byName, name)
+	delete(r.configs, name)
+	r.bulkSizes.Delete(name)
+	r.fallbacks.Delete(name)
+	r.stats.Delete(name)
+	r.generation++
+	return nil
+}
+
+// newRuntimeEntry validates cluster config and creates its entry.
+func (r *Registry) newRuntimeEntry(cfg ClusterConfig) (Entry, error) {
+	if err := (&MultiError{Errors: cfg.validate(cfg.Name, r.detectVersion)}).errOrNil(); err != nil {
+		return Entry{}, errors.Wrap(err, "invalid cluster config")
+	}
+	if r.detectVersion {
+		return newDetectedEntry(cfg.Name, cfg, r.log)
+	}
+	return newEntry(cfg.Name, cfg, r.log)
+}
+
+// register stores entry and config of cluster; caller holds write lock.
+func (r *Registry) register(entry Entry, cfg ClusterConfig) {
+	cfg = cfg.clone()
+	if cfg.Version == 0 {
+		cfg.Version = entry.Version
+	}
+	r.byName[entry.Name] = entry
+	r.configs[entry.Name] = cfg
+	r.generation++
+}
+
+// entry returns entry of cluster, including degraded one.
+func (r *Registry) entry(name string) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.byName[name]
+	return entry, ok
+}
+
+// config returns config of cluster.
+func (r *Registry) config(name string) (ClusterConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cfg, ok := r.configs[name]
+	return cfg, ok
+}
+
+// currentGeneration returns counter incremented on every runtime cluster change;
+// components caching clients of registry compare it to detect stale clients.
+func (r *Registry) currentGeneration() uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.generation
+}
@@ -0,0 +1,168 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ReindexOptions configures ReindexAcrossClusters.
+type ReindexOptions struct {
+	Query             map[string]any // Source query selecting documents, optional (default: all)
+	BatchSize         int            // Documents per scroll batch, optional
+	OpType            string         // Destination op_type: "index" (default) or "create"
+	RequestsPerSecond *float64       // Throttle in sub-requests per second, optional
+	PollInterval      time.Duration  // Task polling interval (default: 5s)
+
+	// OnPoll is called with task status after every poll, e.g. to report progress.
+	OnPoll func(task *GetTaskResponse)
+
+	// RemoteHost overrides source host as reachable from destination cluster.
+	// Default is the first address of source cluster config.
+	RemoteHost string
+}
+
+// ReindexResult represents result of completed reindex task.
+type ReindexResult struct {
+	TaskID           string                   `json:"-"`
+	Took             int                      `json:"took"`
+	TimedOut         bool                     `json:"timed_out"`
+	Total            int                      `json:"total"`
+	Created          int                      `json:"created"`
+	Updated          int                      `json:"updated"`
+	Deleted          int                      `json:"deleted"`
+	Batches          int                      `json:"batches"`
+	VersionConflicts int                      `json:"version_conflicts"`
+	Failures         []map[string]interface{} `json:"failures"`
+}
+
+// ReindexAcrossClusters copies srcIndex of srcCluster into dstIndex of dstCluster.
+// Reindex runs on destination cluster using remote source configured from source
+// ClusterConfig (host, credentials and headers), then the task is polled until completion.
+// Destination cluster must whitelist source host in reindex.remote.whitelist.
+func (r *Registry) ReindexAcrossClusters(ctx context.Context, srcCluster, srcIndex, dstCluster, dstIndex string, opts *ReindexOptions) (*ReindexResult, error) {
+	if srcIndex == "" || dstIndex == "" {
+		return nil, errors.New("source and destination index names are required")
+	}
+	if opts == nil {
+		opts = &ReindexOptions{}
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+
+	srcEntry, err := r.GetEntry(srcCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get source cluster")
+	}
+	dst, err := r.GetTypedClient(dstCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get destination cluster")
+	}
+	dstEntry, err := r.GetEntry(dstCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get destination cluster")
+	}
+
+	source := map[string]any{
+		"index": srcIndex,
+	}
+	if opts.Query != nil {
+		source["query"] = opts.Query
+	}
+	if opts.BatchSize > 0 {
+		source["size"] = opts.BatchSize
+	}
+	if srcEntry.Name != dstEntry.Name {
+		remote, err := r.remoteSource(srcEntry, opts.RemoteHost)
+		if err != nil {
+			return nil, err
+		}
+		source["remote"] = remote
+	}
+
+	dest := map[string]any{
+		"index": dstIndex,
+	}
+	if opts.OpType != "" {
+		dest["op_type"] = opts.OpType
+	}
+
+	query := url.Values{}
+	query.Set("wait_for_completion", "false")
+	if opts.RequestsPerSecond != nil {
+		query.Set("requests_per_second", strconv.FormatFloat(*opts.RequestsPerSecond, 'f', -1, 64))
+	}
+
+	var started struct {
+		Task string `json:"task"`
+	}
+	body := map[string]any{"source": source, "dest": dest}
+	status, errBody, err := dst.doJSONRequest(ctx, http.MethodPost, "/_reindex", query, body, &started)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "reindex", StatusCode: status, body: errBody}
+	}
+
+	r.log.DebugWithCtx(ctx, "elasticsearch reindex started", map[string]interface{}{
+		"task_id":     started.Task,
+		"src_cluster": srcEntry.Name,
+		"src_index":   srcIndex,
+		"dst_cluster": dstEntry.Name,
+		"dst_index":   dstIndex,
+	})
+
+	return dst.waitReindex(ctx, started.Task, opts.PollInterval, opts.OnPoll)
+}
+
+// remoteSource builds remote source block from cluster config.
+// API key and service token are sent as Authorization header; SigV4 signing is not
+// supported by remote reindex, so SigV4 source clusters are rejected.
+func (r *Registry) remoteSource(entry Entry, host string) (map[string]any, error) {
+	cfg, _ := r.config(entry.Name)
+	if cfg.SigV4 != nil {
+		return nil, ErrRemoteReindexSigV4(entry.Name)
+	}
+	if host == "" {
+		host = entry.BaseURL
+	}
+
+	remote := map[string]any{
+		"host": host,
+	}
+	if cfg.Username != "" {
+		remote["username"] = cfg.Username
+		remote["password"] = cfg.Password
+	}
+
+	headers := make(map[string]string, len(cfg.Headers)+1)
+	for k := range cfg.Headers {
+		headers[k] = cfg.Headers.Get(k)
+	}
+	switch {
+	case cfg.APIKey != "":
+		headers["Authorization"] = "ApiKey " + encodeAPIKey(cfg.APIKey)
+	case cfg.ServiceToken != "":
+		headers["Authorization"] = "Bearer " + cfg.ServiceToken
+	}
+	if len(headers) > 0 {
+		remote["headers"] = headers
+	}
+	return remote, nil
+}
+
+// waitReindex polls reindex task until it completes.
+// onPoll, if set, is called with every polled task status.
+func (c *Client) waitReindex(ctx context.Context, taskID string, interval time.Duration, onPoll func(*GetTaskResponse)) (*ReindexResult, error) {
+	result := &ReindexResult{TaskID: taskID}
+	if err := c.waitTask(ctx, taskID, interval, onPoll, result); err != nil {
+		return nil, errors.Wrap(err, "reindex")
+	}
+	return result, nil
+}
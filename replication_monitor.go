@@ -0,0 +1,164 @@
+package esclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultReplicationCheckInterval = time.Minute
+
+// ReplicationPair is index dual-written to primary and secondary cluster.
+type ReplicationPair struct {
+	Primary   ResolvedTarget
+	Secondary ResolvedTarget
+}
+
+// ReplicationMonitorConfig configures replication monitor.
+type ReplicationMonitorConfig struct {
+	Pairs []ReplicationPair // Dual-written indices, e.g. from Resolution.Writes
+
+	// MaxDocDiff is max difference of primary document counts before divergence is reported.
+	// Counts of recent writes differ until both clusters refresh, so allow for write rate * refresh interval.
+	MaxDocDiff int64
+	// MaxWriteDiff is max difference of documents indexed since previous check, 0 disables check.
+	MaxWriteDiff int64
+
+	OnCheck      func(ctx context.Context, status ReplicationStatus) // Called after every check of pair, e.g. to export metrics
+	OnDivergence func(ctx context.Context, status ReplicationStatus) // Called when pair diverged above threshold
+}
+
+// ReplicationStatus represents comparison of dual-written index on both clusters.
+type ReplicationStatus struct {
+	Pair            ReplicationPair
+	PrimaryDocs     int64 // Primary document count on primary cluster
+	SecondaryDocs   int64 // Primary document count on secondary cluster
+	DocDiff         int64 // PrimaryDocs - SecondaryDocs
+	PrimaryWrites   int64 // Documents indexed on primary cluster since previous check
+	SecondaryWrites int64 // Documents indexed on secondary cluster since previous check
+	WriteDiff       int64 // PrimaryWrites - SecondaryWrites, 0 on first check
+	Diverged        bool
+	CheckedAt       time.Time
+}
+
+// ReplicationMonitor periodically compares dual-written indices between clusters.
+type ReplicationMonitor struct {
+	cfg  ReplicationMonitorConfig
+	mu   sync.Mutex
+	prev map[int][2]int64 // pair index -> index_total of primary and secondary at previous check
+}
+
+// NewReplicationMonitor creates replication monitor.
+func NewReplicationMonitor(cfg ReplicationMonitorConfig) (*ReplicationMonitor, error) {
+	if len(cfg.Pairs) == 0 {
+		return nil, errors.New("at least one replication pair is required")
+	}
+	for i, pair := range cfg.Pairs {
+		if pair.Primary.Client == nil || pair.Secondary.Client == nil {
+			return nil, errors.Errorf("replication pair %d has no client", i)
+		}
+		if pair.Primary.Index == "" || pair.Secondary.Index == "" {
+			return nil, errors.Errorf("replication pair %d has no index", i)
+		}
+	}
+
+	return &ReplicationMonitor{cfg: cfg, prev: make(map[int][2]int64)}, nil
+}
+
+// Check compares every pair once and fires callbacks. Pairs failed to check are
+// reported in returned error; other pairs are still checked.
+func (m *ReplicationMonitor) Check(ctx context.Context) ([]ReplicationStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs MultiError
+	result := make([]ReplicationStatus, 0, len(m.cfg.Pairs))
+	for i, pair := range m.cfg.Pairs {
+		status, err := m.check(ctx, i, pair)
+		if err != nil {
+			errs.Errors = append(errs.Errors, errors.Wrapf(err, "failed to check %s/%s", pair.Primary.ClusterName, pair.Primary.Index))
+			continue
+		}
+
+		if m.cfg.OnCheck != nil {
+			m.cfg.OnCheck(ctx, status)
+		}
+		if status.Diverged && m.cfg.OnDivergence != nil {
+			m.cfg.OnDivergence(ctx, status)
+		}
+		result = append(result, status)
+	}
+
+	return result, errs.errOrNil()
+}
+
+// Run checks pairs every interval (default: 1m) until ctx is done.
+func (m *ReplicationMonitor) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultReplicationCheckInterval
+	}
+
+	clock := m.cfg.Pairs[0].Primary.Client.clock
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-clock.After(interval):
+			_, _ = m.Check(ctx)
+		}
+	}
+}
+
+// check compares single pair.
+func (m *ReplicationMonitor) check(ctx context.Context, i int, pair ReplicationPair) (ReplicationStatus, error) {
+	primary, err := primaryIndexStats(ctx, pair.Primary)
+	if err != nil {
+		return ReplicationStatus{}, err
+	}
+	secondary, err := primaryIndexStats(ctx, pair.Secondary)
+	if err != nil {
+		return ReplicationStatus{}, err
+	}
+
+	status := ReplicationStatus{
+		Pair:          pair,
+		PrimaryDocs:   primary.Docs.Count,
+		SecondaryDocs: secondary.Docs.Count,
+		DocDiff:       primary.Docs.Count - secondary.Docs.Count,
+		CheckedAt:     pair.Primary.Client.clock.Now(),
+	}
+
+	// Indexing counters are per node lifetime and reset on restart; negative delta skips comparison
+	if prev, ok := m.prev[i]; ok {
+		status.PrimaryWrites = primary.Indexing.IndexTotal - prev[0]
+		status.SecondaryWrites = secondary.Indexing.IndexTotal - prev[1]
+		if status.PrimaryWrites >= 0 && status.SecondaryWrites >= 0 {
+			status.WriteDiff = status.PrimaryWrites - status.SecondaryWrites
+		}
+	}
+	m.prev[i] = [2]int64{primary.Indexing.IndexTotal, secondary.Indexing.IndexTotal}
+
+	status.Diverged = abs64(status.DocDiff) > m.cfg.MaxDocDiff ||
+		(m.cfg.MaxWriteDiff > 0 && abs64(status.WriteDiff) > m.cfg.MaxWriteDiff)
+
+	return status, nil
+}
+
+// primaryIndexStats returns aggregated primaries stats of target index.
+func primaryIndexStats(ctx context.Context, target ResolvedTarget) (*IndexStats, error) {
+	stats, err := target.Client.IndexStats(ctx, target.Index)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get stats of %s/%s", target.ClusterName, target.Index)
+	}
+	return &stats.All.Primaries, nil
+}
+
+// abs64 returns absolute value of n.
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,57 @@
+package esclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicationMonitor_Check(t *testing.T) {
+	primaryES := &scriptedES{responses: []scriptedResponse{
+		{body: `{"_all": {"primaries": {"docs": {"count": 1000}, "indexing": {"index_total": 5000}}}}`},
+		{body: `{"_all": {"primaries": {"docs": {"count": 1100}, "indexing": {"index_total": 5100}}}}`},
+	}}
+	secondaryES := &scriptedES{responses: []scriptedResponse{
+		{body: `{"_all": {"primaries": {"docs": {"count": 995}, "indexing": {"index_total": 800}}}}`},
+		{body: `{"_all": {"primaries": {"docs": {"count": 1020}, "indexing": {"index_total": 820}}}}`},
+	}}
+	primary, err := NewClient(primaryES, "http://primary:9200")
+	require.NoError(t, err)
+	secondary, err := NewClient(secondaryES, "http://secondary:9200")
+	require.NoError(t, err)
+
+	var diverged []ReplicationStatus
+	monitor, err := NewReplicationMonitor(ReplicationMonitorConfig{
+		Pairs: []ReplicationPair{{
+			Primary:   ResolvedTarget{ClusterName: "old", Index: "orders_c1", Client: primary},
+			Secondary: ResolvedTarget{ClusterName: "new", Index: "orders_c1", Client: secondary},
+		}},
+		MaxDocDiff:   10,
+		MaxWriteDiff: 10,
+		OnDivergence: func(ctx context.Context, status ReplicationStatus) {
+			diverged = append(diverged, status)
+		},
+	})
+	require.NoError(t, err)
+
+	// Within threshold
+	statuses, err := monitor.Check(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, int64(5), statuses[0].DocDiff)
+	assert.False(t, statuses[0].Diverged)
+	assert.Empty(t, diverged)
+
+	// Secondary acknowledged 20 of 100 recent writes
+	statuses, err = monitor.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), statuses[0].PrimaryWrites)
+	assert.Equal(t, int64(20), statuses[0].SecondaryWrites)
+	assert.Equal(t, int64(80), statuses[0].WriteDiff)
+	assert.True(t, statuses[0].Diverged)
+	require.Len(t, diverged, 1)
+
+	assert.Equal(t, "GET /orders_c1/_stats/docs,store,indexing,search", primaryES.paths[0])
+}
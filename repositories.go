@@ -0,0 +1,155 @@
+package esclient
+
+import (
+	"context"
+	"time"
+)
+
+// Index types of reference repositories.
+const (
+	IndexTypeOrders   = "orders"
+	IndexTypeProducts = "products"
+)
+
+// Order is document of orders index.
+type Order struct {
+	ID        string    `json:"id"`
+	CompanyID string    `json:"company_id"`
+	ShopID    string    `json:"shop_id,omitempty"`
+	Number    string    `json:"number"`
+	Status    string    `json:"status"`
+	Total     float64   `json:"total"`
+	Currency  string    `json:"currency,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Product is document of products index.
+type Product struct {
+	ID          string    `json:"id"`
+	CompanyID   string    `json:"company_id"`
+	Name        string    `json:"name"`
+	SKU         string    `json:"sku,omitempty"`
+	Barcodes    []string  `json:"barcodes,omitempty"`
+	CategoryIDs []string  `json:"category_ids,omitempty"`
+	Status      string    `json:"status"`
+	Price       float64   `json:"price"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// OrderMappings returns default mappings of orders index.
+func OrderMappings() map[string]any {
+	return map[string]any{
+		"properties": map[string]any{
+			"id":         map[string]any{"type": "keyword"},
+			"company_id": companyIDMapping(),
+			"shop_id":    map[string]any{"type": "keyword"},
+			"number":     map[string]any{"type": "keyword"},
+			"status":     map[string]any{"type": "keyword"},
+			"total":      map[string]any{"type": "scaled_float", "scaling_factor": 100},
+			"currency":   map[string]any{"type": "keyword"},
+			"created_at": map[string]any{"type": "date"},
+			"updated_at": map[string]any{"type": "date"},
+		},
+	}
+}
+
+// ProductMappings returns default mappings of products index.
+func ProductMappings() map[string]any {
+	return map[string]any{
+		"properties": map[string]any{
+			"id":           map[string]any{"type": "keyword"},
+			"company_id":   companyIDMapping(),
+			"name":         map[string]any{"type": "text", "fields": map[string]any{"keyword": map[string]any{"type": "keyword", "ignore_above": 256}}},
+			"sku":          map[string]any{"type": "keyword"},
+			"barcodes":     map[string]any{"type": "keyword"},
+			"category_ids": map[string]any{"type": "keyword"},
+			"status":       map[string]any{"type": "keyword"},
+			"price":        map[string]any{"type": "scaled_float", "scaling_factor": 100},
+			"created_at":   map[string]any{"type": "date"},
+			"updated_at":   map[string]any{"type": "date"},
+		},
+	}
+}
+
+// companyIDMapping returns company_id mapping matching company filter on company_id.keyword.
+func companyIDMapping() map[string]any {
+	return map[string]any{
+		"type":   "text",
+		"fields": map[string]any{"keyword": map[string]any{"type": "keyword"}},
+	}
+}
+
+// OrderRepository is reference repository of orders.
+type OrderRepository struct {
+	*Repository[Order]
+}
+
+// NewOrderRepository creates orders repository with default mappings.
+func NewOrderRepository(resolver IndexResolver) *OrderRepository {
+	return &OrderRepository{Repository: NewRepository[Order](resolver, IndexTypeOrders, OrderMappings())}
+}
+
+// ByDateRange returns orders created in [from, to), newest first.
+func (r *OrderRepository) ByDateRange(ctx context.Context, companyID string, from, to time.Time, size int) (*SearchResult[Order], error) {
+	return r.Search(ctx, companyID, dateRangeRequest(from, to, size))
+}
+
+// ByStatus returns orders with status, newest first.
+func (r *OrderRepository) ByStatus(ctx context.Context, companyID, status string, size int) (*SearchResult[Order], error) {
+	return r.Search(ctx, companyID, statusRequest(status, size))
+}
+
+// ProductRepository is reference repository of products.
+type ProductRepository struct {
+	*Repository[Product]
+}
+
+// NewProductRepository creates products repository with default mappings.
+func NewProductRepository(resolver IndexResolver) *ProductRepository {
+	return &ProductRepository{Repository: NewRepository[Product](resolver, IndexTypeProducts, ProductMappings())}
+}
+
+// ByDateRange returns products created in [from, to), newest first.
+func (r *ProductRepository) ByDateRange(ctx context.Context, companyID string, from, to time.Time, size int) (*SearchResult[Product], error) {
+	return r.Search(ctx, companyID, dateRangeRequest(from, to, size))
+}
+
+// ByStatus returns products with status, newest first.
+func (r *ProductRepository) ByStatus(ctx context.Context, companyID, status string, size int) (*SearchResult[Product], error) {
+	return r.Search(ctx, companyID, statusRequest(status, size))
+}
+
+// dateRangeRequest builds search of documents created in [from, to), newest first.
+func dateRangeRequest(from, to time.Time, size int) *SearchRequest {
+	return &SearchRequest{
+		Query: map[string]any{
+			"query": map[string]any{
+				"range": map[string]any{
+					"created_at": map[string]any{
+						"gte": from.Format(time.RFC3339Nano),
+						"lt":  to.Format(time.RFC3339Nano),
+					},
+				},
+			},
+		},
+		Size:               &size,
+		Sort:               []SortClause{{Field: "created_at", Order: "desc"}},
+		WithTrackTotalHits: true,
+	}
+}
+
+// statusRequest builds search of documents with status, newest first.
+func statusRequest(status string, size int) *SearchRequest {
+	return &SearchRequest{
+		Query: map[string]any{
+			"query": map[string]any{
+				"term": map[string]any{"status": status},
+			},
+		},
+		Size:               &size,
+		Sort:               []SortClause{{Field: "created_at", Order: "desc"}},
+		WithTrackTotalHits: true,
+	}
+}
@@ -0,0 +1,152 @@
+package esclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// IndexResolver resolves typed client and index of company and index type.
+// Implemented by Resolver.
+type IndexResolver interface {
+	ResolveSearch(ctx context.Context, companyID, indexType string) (*Client, string, error)
+	ResolveWrite(ctx context.Context, companyID, indexType string) (*Client, string, error)
+}
+
+// SearchTargetResolver is optional extension of IndexResolver. If implemented (as by *Resolver),
+// read target is resolved with its index target, so company index patterns and lists are searched
+// without company filter and routing.
+type SearchTargetResolver interface {
+	ResolveSearchTarget(ctx context.Context, companyID, indexType string) (*ResolvedTarget, error)
+}
+
+// resolveSearchTarget resolves read target of company, detecting index target from index name
+// if resolver doesn't implement SearchTargetResolver.
+func resolveSearchTarget(ctx context.Context, resolver IndexResolver, companyID, indexType string) (*ResolvedTarget, error) {
+	if r, ok := resolver.(SearchTargetResolver); ok {
+		return r.ResolveSearchTarget(ctx, companyID, indexType)
+	}
+	client, index, err := resolver.ResolveSearch(ctx, companyID, indexType)
+	if err != nil {
+		return nil, err
+	}
+	return &ResolvedTarget{Index: index, Target: DetectIndexTarget(index), Client: client}, nil
+}
+
+// Repository provides typed document access to company index of one index type.
+// Documents are JSON-encoded T; company filter, routing and company_id stamping
+// are applied as by Client operations.
+type Repository[T any] struct {
+	resolver  IndexResolver
+	indexType string
+	mappings  map[string]any // mappings of index created by EnsureIndex, optional
+}
+
+// SearchResult is typed search result.
+type SearchResult[T any] struct {
+	Total int      // Total number of matching documents (lower bound if not tracked)
+	IDs   []string // Document IDs in hit order
+	Items []T      // Decoded documents in hit order
+
+	Aggregations map[string]interface{} // Aggregations of search response, if requested
+}
+
+// NewRepository creates repository of index type. Mappings are used by EnsureIndex.
+func NewRepository[T any](resolver IndexResolver, indexType string, mappings map[string]any) *Repository[T] {
+	return &Repository[T]{
+		resolver:  resolver,
+		indexType: indexType,
+		mappings:  mappings,
+	}
+}
+
+// IndexType returns index type of repository.
+func (r *Repository[T]) IndexType() string {
+	return r.indexType
+}
+
+// EnsureIndex creates company write index with repository mappings if it does not exist.
+func (r *Repository[T]) EnsureIndex(ctx context.Context, companyID string) (bool, error) {
+	client, index, err := r.resolver.ResolveWrite(ctx, companyID, r.indexType)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to resolve %s index", r.indexType)
+	}
+
+	return client.EnsureIndex(ctx, &CreateIndexRequest{
+		Index:    index,
+		Mappings: r.mappings,
+	})
+}
+
+// Save creates or replaces document with ID in company index.
+func (r *Repository[T]) Save(ctx context.Context, companyID, id string, doc T) error {
+	client, index, err := r.resolver.ResolveWrite(ctx, companyID, r.indexType)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve %s index", r.indexType)
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode %s document", r.indexType)
+	}
+
+	_, err = client.CreateDocument(ctx, &CreateDocumentRequest{
+		Index:          index,
+		DocumentID:     id,
+		Body:           bytes.NewReader(body),
+		CompanyID:      companyID,
+		StampCompanyID: true,
+	})
+	return err
+}
+
+// Search searches company index with query body and decodes hits.
+func (r *Repository[T]) Search(ctx context.Context, companyID string, req *SearchRequest) (*SearchResult[T], error) {
+	target, err := resolveSearchTarget(ctx, r.resolver, companyID, r.indexType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve %s index", r.indexType)
+	}
+
+	reqCopy := *req
+	reqCopy.Index = target.Index
+	reqCopy.Target = target.Target
+	reqCopy.CompanyID = companyID
+
+	resp, err := target.Client.Search(ctx, &reqCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeHits[T](resp)
+}
+
+// decodeHits decodes _source of search hits into T.
+func decodeHits[T any](resp *SearchResponse) (*SearchResult[T], error) {
+	result := &SearchResult[T]{
+		Total: resp.Hits.Total.Value,
+		IDs:   make([]string, 0, len(resp.Hits.Hits)),
+		Items: make([]T, 0, len(resp.Hits.Hits)),
+
+		Aggregations: resp.Aggregations,
+	}
+
+	for _, hit := range resp.Hits.Hits {
+		raw, err := json.Marshal(hit["_source"])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode hit source")
+		}
+
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, errors.Wrap(err, "failed to decode hit source")
+		}
+
+		id, _ := hit["_id"].(string)
+		result.IDs = append(result.IDs, id)
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,227 @@
+package esclient
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticResolver resolves every company to one client and index.
+type staticResolver struct {
+	client *Client
+	index  string
+}
+
+func (r *staticResolver) ResolveSearch(ctx context.Context, companyID, indexType string) (*Client, string, error) {
+	return r.client, r.index, nil
+}
+
+func (r *staticResolver) ResolveWrite(ctx context.Context, companyID, indexType string) (*Client, string, error) {
+	return r.client, r.index, nil
+}
+
+func TestOrderRepository_ByDateRange(t *testing.T) {
+	es := &fakeES{response: `{"hits": {"total": {"value": 1}, "hits": [
+		{"_id": "o1", "_source": {"id": "o1", "company_id": "c1", "status": "paid", "total": 12.5, "created_at": "2025-01-02T10:00:00Z"}}
+	]}}`}
+	repo := NewOrderRepository(&staticResolver{client: newTestClient(t, es), index: "orders_shared"})
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err := repo.ByDateRange(context.Background(), "c1", from, from.AddDate(0, 1, 0), 50)
+	require.NoError(t, err)
+
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, []string{"o1"}, result.IDs)
+	assert.Equal(t, "paid", result.Items[0].Status)
+	assert.Equal(t, 12.5, result.Items[0].Total)
+	assert.Equal(t, 1, result.Total)
+
+	assert.Equal(t, "/orders_shared/_search", es.requests[0].URL.Path)
+	assert.Equal(t, "c1", es.requests[0].URL.Query().Get("routing"))
+	assert.Contains(t, es.bodies[0], `"created_at":{"gte":"2025-01-01T00:00:00Z","lt":"2025-02-01T00:00:00Z"}`)
+	assert.Contains(t, es.bodies[0], `"company_id.keyword":"c1"`)
+}
+
+func TestProductRepository_Save(t *testing.T) {
+	es := &fakeES{status: 201}
+	repo := NewProductRepository(&staticResolver{client: newTestClient(t, es), index: "products_shared"})
+
+	err := repo.Save(context.Background(), "c1", "p1", Product{ID: "p1", Name: "Phone", Status: "active"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/products_shared/_doc/p1", es.requests[0].URL.Path)
+	assert.Contains(t, es.bodies[0], `"company_id":"c1"`)
+}
+
+func TestProductRepository_Find_Facets(t *testing.T) {
+	es := &fakeES{response: `{"hits": {"total": {"value": 1}, "hits": [
+		{"_id": "p1", "_source": {"id": "p1", "company_id": "c1", "name": "Phone", "status": "active", "price": 199}}
+	]}, "aggregations": {
+		"categories": {"buckets": [{"key": "phones", "doc_count": 3}, {"key": "cases", "doc_count": 1}]},
+		"statuses": {"buckets": [{"key": "active", "doc_count": 4}]},
+		"price": {"count": 4, "min": 9.5, "max": 199}
+	}}`}
+	repo := NewProductRepository(&staticResolver{client: newTestClient(t, es), index: "products_shared"})
+
+	result, err := repo.Find(context.Background(), "c1", ProductSearchParams{
+		Text:        "phone",
+		CategoryIDs: []string{"phones"},
+		Facets:      true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p1"}, result.IDs)
+
+	// Company filter limits query, so facets only count company products; attribute filters don't narrow facets
+	assert.Contains(t, es.bodies[0], `"filter":[{"term":{"company_id.keyword":"c1"}}]`)
+	assert.Contains(t, es.bodies[0], `"post_filter":{"bool":{"filter":[{"terms":{"category_ids":["phones"]}}]}}`)
+
+	facets := ParseProductFacets(result.Aggregations)
+	assert.Equal(t, map[string]int{"phones": 3, "cases": 1}, facets.Categories)
+	assert.Equal(t, map[string]int{"active": 4}, facets.Statuses)
+	assert.Equal(t, 9.5, facets.MinPrice)
+	assert.Equal(t, 199.0, facets.MaxPrice)
+}
+
+func TestProductSimilarQuery(t *testing.T) {
+	req := ProductSimilarQuery(&Product{ID: "p1", Name: "Red phone case", CategoryIDs: []string{"cases"}}, 5)
+
+	assert.Empty(t, LintQuery(req.Query, IndexTargetShared))
+	assert.Equal(t, 5, *req.Size)
+	assert.Equal(t, []any{map[string]any{"ids": map[string]any{"values": []string{"p1"}}}},
+		req.Query["query"].(map[string]any)["bool"].(map[string]any)["must_not"])
+}
+
+func TestProductRepository_Autocomplete(t *testing.T) {
+	es := &fakeES{response: `{"hits": {"total": {"value": 3}, "hits": [
+		{"_id": "p1", "_source": {"id": "p1", "name": "Milk 3.2%"}},
+		{"_id": "p2", "_source": {"id": "p2", "name": "milk  3.2%"}},
+		{"_id": "p3", "_source": {"id": "p3", "name": "Milk 1.5%"}}
+	]}}`}
+	repo := NewProductRepository(&staticResolver{client: newTestClient(t, es), index: "products_shared"})
+
+	result, err := repo.Autocomplete(context.Background(), "c1", ProductAutocompleteParams{
+		Prefix:   "mlik",
+		Size:     2,
+		Contexts: map[string][]string{"category_ids": {"dairy"}},
+		Fuzzy:    &SuggestFuzzy{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p1", "p3"}, result.IDs)
+	assert.Len(t, result.Items, 2)
+
+	// Twice the size is requested to fill it after collapsing duplicates
+	assert.Equal(t, "4", es.requests[0].URL.Query().Get("size"))
+	assert.Contains(t, es.bodies[0], `{"match":{"name":{"fuzziness":"AUTO","operator":"and","prefix_length":1,"query":"mlik"}}}`)
+	assert.Contains(t, es.bodies[0], `{"terms":{"category_ids":["dairy"]}}`)
+	assert.Contains(t, es.bodies[0], `{"term":{"company_id.keyword":"c1"}}`)
+
+	result, err = repo.Autocomplete(context.Background(), "c1", ProductAutocompleteParams{Prefix: "mi", Fuzzy: &SuggestFuzzy{}, KeepDuplicates: true})
+	require.NoError(t, err)
+	assert.Len(t, result.IDs, 3)
+	assert.Equal(t, "10", es.requests[1].URL.Query().Get("size"))
+	// Prefix is shorter than fuzzy min length
+	assert.NotContains(t, es.bodies[1], "fuzziness")
+}
+
+func TestTenantClient(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewTenantClient(&staticResolver{}, "")
+	require.Error(t, err)
+
+	es := &fakeES{response: `{"hits": {"total": {"value": 0}, "hits": []}}`}
+	tenant, err := NewTenantClient(&staticResolver{client: newTestClient(t, es), index: "orders_shared"}, "c1")
+	require.NoError(t, err)
+
+	_, err = tenant.Search(ctx, "orders", &TenantSearchRequest{Query: map[string]any{"query": map[string]any{"match_all": map[string]any{}}}})
+	require.NoError(t, err)
+	assert.Equal(t, "/orders_shared/_search", es.requests[0].URL.Path)
+	assert.Equal(t, "c1", es.requests[0].URL.Query().Get("routing"))
+	assert.Contains(t, es.bodies[0], `"company_id.keyword":"c1"`)
+
+	// Global aggregation would expose other companies
+	_, err = tenant.Search(ctx, "orders", &TenantSearchRequest{Query: map[string]any{
+		"aggs": map[string]any{"all": map[string]any{"global": map[string]any{}}},
+	}})
+	assert.ErrorIs(t, err, ErrUnsafeQuery)
+	assert.Len(t, es.requests, 1)
+
+	// kNN and suggesters are not covered by company filter
+	for _, key := range []string{"knn", "suggest"} {
+		_, err = tenant.Search(ctx, "orders", &TenantSearchRequest{Query: map[string]any{
+			"query": map[string]any{"match_all": map[string]any{}},
+			key:     map[string]any{"field": "embedding"},
+		}})
+		assert.ErrorIs(t, err, ErrUnsafeQuery, key)
+		assert.ErrorContains(t, err, key)
+	}
+	_, err = tenant.Count(ctx, "orders", map[string]any{"suggest": map[string]any{}})
+	assert.ErrorIs(t, err, ErrUnsafeQuery)
+	assert.Len(t, es.requests, 1)
+
+	es.response = `{"errors": false, "items": []}`
+	_, err = tenant.Bulk(ctx, "orders", []TenantBulkOperation{
+		{ID: "o1", Body: map[string]any{"status": "paid"}},
+		{ID: "o2", Delete: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, es.requests, 3)
+	assert.Equal(t, "/orders_shared/_mget", es.requests[1].URL.Path)
+	assert.Equal(t, "c1", es.requests[1].URL.Query().Get("routing"))
+	assert.Equal(t, `{"ids":["o1","o2"]}`, es.bodies[1])
+	assert.Equal(t, "/orders_shared/_bulk", es.requests[2].URL.Path)
+	assert.Equal(t, "c1", es.requests[2].URL.Query().Get("routing"))
+	// Missing documents are created only if still missing
+	assert.Equal(t, `{"create":{"_id":"o1"}}`+"\n"+`{"company_id":"c1","status":"paid"}`+"\n"+`{"delete":{"_id":"o2"}}`+"\n", es.bodies[2])
+}
+
+func TestTenantClient_WriteOwnership(t *testing.T) {
+	ctx := context.Background()
+	const docs = `{"docs": [
+		{"_id": "own", "found": true, "_seq_no": 7, "_primary_term": 2, "_source": {"company_id": "c1"}},
+		{"_id": "foreign", "found": true, "_seq_no": 3, "_primary_term": 1, "_source": {"company_id": "c2"}},
+		{"_id": "new", "found": false}
+	]}`
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: docs},
+		{body: docs},
+		{body: docs},
+		{body: `{"_id": "own", "result": "updated"}`},
+		{body: docs},
+		{body: `{"errors": false, "items": []}`},
+	}}
+	tenant, err := NewTenantClient(&staticResolver{client: newTestClient(t, es), index: "orders_shared"}, "c1")
+	require.NoError(t, err)
+
+	// Other company's document is neither replaced nor deleted
+	_, err = tenant.Index(ctx, "orders", "foreign", map[string]any{"status": "paid"})
+	assert.ErrorIs(t, err, ErrDocumentNotOwned)
+	_, err = tenant.Bulk(ctx, "orders", []TenantBulkOperation{{ID: "new", Body: map[string]any{}}, {ID: "foreign", Delete: true}})
+	assert.ErrorIs(t, err, ErrDocumentNotOwned)
+	assert.Equal(t, []string{"POST /orders_shared/_mget", "POST /orders_shared/_mget"}, es.paths)
+
+	// Company's document is replaced only if unchanged since check
+	_, err = tenant.Index(ctx, "orders", "own", map[string]any{"status": "paid"})
+	require.NoError(t, err)
+	assert.Equal(t, "PUT /orders_shared/_doc/own", es.paths[3])
+	query, err := url.ParseQuery(es.queries[3])
+	require.NoError(t, err)
+	assert.Equal(t, "7", query.Get("if_seq_no"))
+	assert.Equal(t, "2", query.Get("if_primary_term"))
+	assert.Empty(t, query.Get("op_type"))
+
+	_, err = tenant.Bulk(ctx, "orders", []TenantBulkOperation{{ID: "own", Delete: true}, {ID: "new", Body: map[string]any{}}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"delete":{"_id":"own","if_primary_term":2,"if_seq_no":7}}`+"\n"+`{"create":{"_id":"new"}}`+"\n"+`{"company_id":"c1"}`+"\n", es.bodies[5])
+
+	// Company-split indices hold no other companies, so they are not checked
+	es = &scriptedES{}
+	tenant, err = NewTenantClient(&staticResolver{client: newTestClient(t, es), index: "orders_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87"}, "c1")
+	require.NoError(t, err)
+	_, err = tenant.Index(ctx, "orders", "foreign", map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PUT /orders_5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87/_doc/foreign"}, es.paths)
+}
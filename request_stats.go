@@ -0,0 +1,184 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBounds are upper bounds of latency histogram buckets.
+var latencyBounds = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyBucket is cumulative bucket of latency histogram.
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      int64         `json:"count"` // Requests with latency <= UpperBound
+}
+
+// LatencyHistogram is distribution of request latencies. Requests slower than
+// the last bucket bound are counted only in Count.
+type LatencyHistogram struct {
+	Buckets []LatencyBucket `json:"buckets"`
+	Count   int64           `json:"count"`
+	Sum     time.Duration   `json:"sum"`
+}
+
+// OperationStats is request statistics of one operation or whole cluster.
+type OperationStats struct {
+	Requests int64            `json:"requests"`
+	Errors   int64            `json:"errors"` // Connection errors and 429/5xx responses
+	Latency  LatencyHistogram `json:"latency"`
+}
+
+// RequestStats is request statistics of cluster since registry (or client) creation.
+type RequestStats struct {
+	Total      OperationStats            `json:"total"`
+	Operations map[string]OperationStats `json:"operations"` // By operation, e.g. "POST _search", "PUT _doc", "HEAD {index}"
+}
+
+// requestStats counts requests of cluster by operation. Shared by all clients of cluster.
+type requestStats struct {
+	mu  sync.Mutex
+	ops map[string]*operationCounters
+}
+
+// operationCounters are counters of one operation; buckets are not cumulative,
+// the last one counts requests above all bounds.
+type operationCounters struct {
+	requests int64
+	errors   int64
+	sum      time.Duration
+	buckets  []int64
+}
+
+// observe records request of operation.
+func (s *requestStats) observe(op string, duration time.Duration, failed bool) {
+	bucket := sort.Search(len(latencyBounds), func(i int) bool { return duration <= latencyBounds[i] })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ops == nil {
+		s.ops = make(map[string]*operationCounters)
+	}
+	counters, ok := s.ops[op]
+	if !ok {
+		counters = &operationCounters{buckets: make([]int64, len(latencyBounds)+1)}
+		s.ops[op] = counters
+	}
+	counters.requests++
+	if failed {
+		counters.errors++
+	}
+	counters.sum += duration
+	counters.buckets[bucket]++
+}
+
+// snapshot returns statistics recorded so far.
+func (s *requestStats) snapshot() RequestStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := operationCounters{buckets: make([]int64, len(latencyBounds)+1)}
+	result := RequestStats{Operations: make(map[string]OperationStats, len(s.ops))}
+	for op, counters := range s.ops {
+		result.Operations[op] = counters.stats()
+		total.requests += counters.requests
+		total.errors += counters.errors
+		total.sum += counters.sum
+		for i, n := range counters.buckets {
+			total.buckets[i] += n
+		}
+	}
+	result.Total = total.stats()
+	return result
+}
+
+// stats converts counters to exported statistics with cumulative buckets.
+func (c *operationCounters) stats() OperationStats {
+	histogram := LatencyHistogram{
+		Buckets: make([]LatencyBucket, len(latencyBounds)),
+		Count:   c.requests,
+		Sum:     c.sum,
+	}
+	var cumulative int64
+	for i, bound := range latencyBounds {
+		cumulative += c.buckets[i]
+		histogram.Buckets[i] = LatencyBucket{UpperBound: bound, Count: cumulative}
+	}
+	return OperationStats{Requests: c.requests, Errors: c.errors, Latency: histogram}
+}
+
+// withRequestStats sets request statistics shared by clients of the same cluster.
+func withRequestStats(stats *requestStats) ClientOption {
+	return func(c *Client) {
+		c.stats = stats
+	}
+}
+
+// Stats returns request statistics of client. Clients of the same registry cluster
+// share statistics, so it covers all of them.
+func (c *Client) Stats() RequestStats {
+	return c.stats.snapshot()
+}
+
+// Stats returns request statistics of typed clients by cluster name, for scraping into
+// metrics pipeline. Counters are cumulative and survive ReplaceCluster.
+func (r *Registry) Stats() map[string]RequestStats {
+	clusters := r.ListClusters()
+	result := make(map[string]RequestStats, len(clusters))
+	for _, name := range clusters {
+		stats, _ := r.stats.LoadOrStore(name, &requestStats{})
+		result[name] = stats.(*requestStats).snapshot()
+	}
+	return result
+}
+
+// statsClient records requests into request statistics.
+type statsClient struct {
+	es    ESClient
+	stats *requestStats
+	clock Clock
+}
+
+// Do executes request and records its operation, duration and outcome.
+func (sc *statsClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	start := sc.clock.Now()
+	resp, err := sc.es.Do(ctx, req)
+
+	failed := err != nil || resp == nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	sc.stats.observe(requestOperation(req), sc.clock.Now().Sub(start), failed)
+
+	return resp, err
+}
+
+// requestOperation names operation of request by method and its first endpoint segment
+// (e.g., "POST _search"), so index names and document IDs don't multiply operations.
+// Requests to index itself are named "<METHOD> {index}".
+func requestOperation(req *http.Request) string {
+	endpoint := "/"
+	for _, segment := range strings.Split(strings.Trim(req.URL.Path, "/"), "/") {
+		if strings.HasPrefix(segment, "_") {
+			endpoint = segment
+			break
+		}
+		if segment != "" {
+			endpoint = "{index}"
+		}
+	}
+	return req.Method + " " + endpoint
+}
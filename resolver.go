@@ -6,30 +6,174 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
 )
 
+// ResolutionSource tells where resolved routing information came from.
+type ResolutionSource string
+
+const (
+	ResolutionSourceCache    ResolutionSource = "cache"    // Redis cache hit
+	ResolutionSourceSync     ResolutionSource = "sync"     // Fetched from sync service
+	ResolutionSourceFallback ResolutionSource = "fallback" // Sync service has no routing, fallback policy applied
+)
+
+// SupportedSchemaVersion is the latest sync service settings schema version understood by this library.
+// Responses without schema_version are treated as version 1. Unknown fields are ignored, so
+// sync service may add fields without bumping version; version is bumped only on incompatible changes.
+const SupportedSchemaVersion = 1
+
+// syncErrorBodyLimit is max size of sync service error response body included into error.
+const syncErrorBodyLimit = 4 << 10
+
 // ClusterInfo represents routing information from sync service.
 type ClusterInfo struct {
+	SchemaVersion int              `json:"schema_version,omitempty"`
+	ClusterName   string           `json:"cluster_name"`
+	ClusterID     int              `json:"cluster_id"`
+	IndexName     string           `json:"index_name"`
+	Indices       []string         `json:"indices,omitempty"`       // All indices of company/index type (e.g., monthly indices)
+	IndexPattern  string           `json:"index_pattern,omitempty"` // Pattern covering all indices (e.g., "orders_<companyID>_*")
+	WriteIndex    string           `json:"write_index,omitempty"`   // Designated write index
+	Read          *IndexLocation   `json:"read,omitempty"`          // Read target override (e.g., old cluster during live migration)
+	DualWrites    []IndexLocation  `json:"dual_writes,omitempty"`   // Additional write targets during live migration
+	CachedAt      time.Time        `json:"cached_at,omitzero"`      // Time entry was cached, set by resolver
+	Source        ResolutionSource `json:"-"`                       // Provenance of resolution, set by resolver
+}
+
+// IndexLocation points to index on a specific cluster.
+type IndexLocation struct {
 	ClusterName string `json:"cluster_name"`
-	ClusterID   int    `json:"cluster_id"`
 	IndexName   string `json:"index_name"`
 }
 
+// ResolvedTarget is an index on a cluster with its typed client.
+type ResolvedTarget struct {
+	ClusterName string
+	Index       string
+	Target      IndexTarget // Index target of Index; pass as request Target, since patterns and lists of company indices look shared by name
+	Client      *Client
+}
+
+// Resolution contains distinct read and write targets of company index.
+// Outside of live migrations Read and the only element of Writes point to the same cluster.
+type Resolution struct {
+	Read   ResolvedTarget
+	Writes []ResolvedTarget // Primary write target first, then dual-write targets
+	Source ResolutionSource
+}
+
+// ReadLocation returns cluster and index serving reads: Read override if set,
+// otherwise ClusterName with SearchTarget.
+func (ci *ClusterInfo) ReadLocation() IndexLocation {
+	if ci.Read != nil && ci.Read.ClusterName != "" {
+		loc := *ci.Read
+		if loc.IndexName == "" {
+			loc.IndexName = ci.SearchTarget()
+		}
+		return loc
+	}
+	return IndexLocation{ClusterName: ci.ClusterName, IndexName: ci.SearchTarget()}
+}
+
+// WriteLocations returns all write targets: primary (ClusterName with WriteTarget) first,
+// then dual-write targets.
+func (ci *ClusterInfo) WriteLocations() []IndexLocation {
+	locs := []IndexLocation{{ClusterName: ci.ClusterName, IndexName: ci.WriteTarget()}}
+	for _, loc := range ci.DualWrites {
+		if loc.IndexName == "" {
+			loc.IndexName = ci.WriteTarget()
+		}
+		locs = append(locs, loc)
+	}
+	return locs
+}
+
+// SearchTarget returns index expression for reads: index pattern if set,
+// otherwise comma-separated list of indices, otherwise IndexName.
+// Patterns and lists are detected as shared indices by DetectIndexTarget, so requests
+// must carry Target from IndexTarget to skip company filter and routing.
+func (ci *ClusterInfo) SearchTarget() string {
+	if ci.IndexPattern != "" {
+		return ci.IndexPattern
+	}
+	if len(ci.Indices) > 0 {
+		return strings.Join(ci.Indices, ",")
+	}
+	return ci.IndexName
+}
+
+// IndexTarget returns index target of index of info's location. Company's own split target
+// (SearchTarget or WriteTarget of company split into index list or pattern) is per-company, since
+// it never holds other companies; any other index, e.g. Read override or dual write pointing at
+// shared index, is detected from its name.
+func (ci *ClusterInfo) IndexTarget(index string) IndexTarget {
+	split := ci.IndexPattern != "" || len(ci.Indices) > 0
+	if split && (index == ci.SearchTarget() || index == ci.WriteTarget()) {
+		return IndexTargetPerCompany
+	}
+	return DetectIndexTarget(index)
+}
+
+// WriteTarget returns index for writes: designated write index if set, otherwise IndexName.
+func (ci *ClusterInfo) WriteTarget() string {
+	if ci.WriteIndex != "" {
+		return ci.WriteIndex
+	}
+	return ci.IndexName
+}
+
+// checkSchemaVersion returns error if info uses schema version newer than supported.
+func (ci *ClusterInfo) checkSchemaVersion() error {
+	if ci.SchemaVersion > SupportedSchemaVersion {
+		return errors.Wrapf(ErrUnsupportedSchemaVersion, "got schema version %d, supported up to %d (upgrade elasticsearch-cluster library)",
+			ci.SchemaVersion, SupportedSchemaVersion)
+	}
+	return nil
+}
+
+// FallbackMode defines what resolver does when sync service has no routing for index
+// (e.g., index not migrated yet).
+type FallbackMode string
+
+const (
+	FallbackDefaultCluster FallbackMode = "default_cluster" // Use default cluster with <prefix><companyID> index
+	FallbackCluster        FallbackMode = "cluster"         // Use specific cluster with <prefix><companyID> index
+	FallbackError          FallbackMode = "error"           // Return ErrNoRouting
+)
+
+// FallbackPolicy configures resolver fallback for an index type.
+type FallbackPolicy struct {
+	Mode    FallbackMode // Fallback mode
+	Cluster string       // Cluster name for FallbackCluster mode
+}
+
 // Resolver resolves cluster and index for company using Redis cache and sync service.
 type Resolver struct {
-	registry       *Registry
-	redis          *redis.Client
-	syncURL        string
-	cacheTTL       time.Duration
-	httpClient     *http.Client
-	defaultClient  *Client            // cached default client
-	clients        map[string]*Client // cached clients by cluster name
-	log            Logger             // logger for debugging
-	indexPrefixMap map[string]string  // mapping: indexType -> index name prefix
+	registry        *Registry
+	redis           *redis.Client
+	syncURL         string
+	syncToken       string
+	cacheTTL        time.Duration
+	httpClient      *http.Client
+	clientsMu       sync.RWMutex              // guards clients and generation
+	clients         map[string]*Client        // cached clients by cluster name
+	generation      uint64                    // registry generation clients were created at
+	clientLog       Logger                    // logger of typed clients, nil keeps registry options
+	log             Logger                    // logger for debugging
+	indexPrefixMap  map[string]string         // mapping: indexType -> index name prefix
+	fallbacks       map[string]FallbackPolicy // fallback policies by index type
+	defaultFallback FallbackPolicy            // fallback policy for other index types
+	fallbackMu      sync.Mutex
+	fallbackCounts  map[string]fallbackCount   // fallback resolutions by company ID
+	fallbackPruned  time.Time                  // last time expired fallback counters were dropped
+	resolutions     map[ResolutionSource]int64 // resolutions by source, guarded by fallbackMu
+	clock           Clock
 }
 
 // ResolverConfig configures the resolver.
@@ -37,10 +181,19 @@ type ResolverConfig struct {
 	Registry       *Registry         // Registry with pre-created clients
 	Redis          *redis.Client     // Redis client for caching
 	SyncURL        string            // Sync service URL (e.g., "http://sync-service:8080")
+	SyncToken      string            // Bearer token of sync service requests (optional)
 	CacheTTL       time.Duration     // Cache TTL (default: 24h)
 	HTTPClient     *http.Client      // HTTP client for sync calls (optional)
 	Logger         Logger            // Logger for debugging (optional)
 	IndexPrefixMap map[string]string // Optional custom mapping: indexType -> index name prefix
+
+	// FallbackPolicies configures fallback per index type when sync service has no routing.
+	// Index types not listed use DefaultFallback.
+	FallbackPolicies map[string]FallbackPolicy
+	// DefaultFallback is used for index types without explicit policy (default: FallbackDefaultCluster).
+	DefaultFallback FallbackPolicy
+	// Clock is time source of cache TTL (default: system clock). Redis expires entries by CacheTTL too.
+	Clock Clock
 }
 
 // NewResolver creates a new resolver with Redis caching.
@@ -58,6 +211,9 @@ func NewResolver(cfg ResolverConfig) (*Resolver, error) {
 	if cfg.CacheTTL == 0 {
 		cfg.CacheTTL = 24 * time.Hour
 	}
+	if cfg.Clock == nil {
+		cfg.Clock = systemClock{}
+	}
 
 	if cfg.HTTPClient == nil {
 		cfg.HTTPClient = &http.Client{
@@ -73,45 +229,108 @@ func NewResolver(cfg ResolverConfig) (*Resolver, error) {
 		}
 	}
 
+	if cfg.DefaultFallback.Mode == "" {
+		cfg.DefaultFallback.Mode = FallbackDefaultCluster
+	}
+	if err := cfg.DefaultFallback.validate(cfg.Registry); err != nil {
+		return nil, errors.Wrap(err, "invalid default fallback policy")
+	}
+	for indexType, policy := range cfg.FallbackPolicies {
+		if err := policy.validate(cfg.Registry); err != nil {
+			return nil, errors.Wrapf(err, "invalid fallback policy for index type %q", indexType)
+		}
+	}
+
 	// Pre-create all clients from registry
+	generation := cfg.Registry.currentGeneration()
 	clusterNames := cfg.Registry.ListClusters()
 	clients := make(map[string]*Client, len(clusterNames))
 
 	for _, clusterName := range clusterNames {
 		entry, err := cfg.Registry.GetEntry(clusterName)
+		if IsDegraded(err) {
+			// Degraded clusters have no client; getClient reports registry error for them
+			continue
+		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to get entry for cluster %q", clusterName)
 		}
 
-		baseURL, err := parseBaseURL(entry.BaseURL)
+		client, err := newResolverClient(cfg.Registry, entry, cfg.Logger)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse base URL for cluster %q", clusterName)
-		}
-
-		clients[clusterName] = &Client{
-			es:      entry.ES,
-			baseURL: baseURL,
-			log:     safeLogger(cfg.Logger),
+			return nil, err
 		}
+		clients[clusterName] = client
 	}
 
-	// Get default client
-	defaultEntry, err := cfg.Registry.GetEntry("")
-	if err != nil {
+	if _, err := cfg.Registry.GetEntry(""); err != nil {
 		return nil, errors.Wrap(err, "failed to get default cluster entry")
 	}
-	defaultClient := clients[defaultEntry.Name]
 
 	return &Resolver{
-		registry:       cfg.Registry,
-		redis:          cfg.Redis,
-		syncURL:        cfg.SyncURL,
-		cacheTTL:       cfg.CacheTTL,
-		httpClient:     cfg.HTTPClient,
-		defaultClient:  defaultClient,
-		clients:        clients,
-		log:            safeLogger(cfg.Logger),
-		indexPrefixMap: indexPrefixMap,
+		registry:        cfg.Registry,
+		redis:           cfg.Redis,
+		syncURL:         cfg.SyncURL,
+		syncToken:       cfg.SyncToken,
+		cacheTTL:        cfg.CacheTTL,
+		httpClient:      cfg.HTTPClient,
+		clients:         clients,
+		generation:      generation,
+		clientLog:       cfg.Logger,
+		log:             safeLogger(cfg.Logger),
+		indexPrefixMap:  indexPrefixMap,
+		fallbacks:       cfg.FallbackPolicies,
+		defaultFallback: cfg.DefaultFallback,
+		fallbackCounts:  make(map[string]fallbackCount),
+		resolutions:     make(map[ResolutionSource]int64),
+		clock:           cfg.Clock,
+	}, nil
+}
+
+// validate checks fallback policy against registry.
+func (p FallbackPolicy) validate(reg *Registry) error {
+	switch p.Mode {
+	case FallbackDefaultCluster, FallbackError:
+		return nil
+	case FallbackCluster:
+		if p.Cluster == "" {
+			return errors.New("fallback cluster name is required for cluster fallback mode")
+		}
+		if _, ok := reg.entry(p.Cluster); !ok {
+			return ErrClusterNotFound(p.Cluster)
+		}
+		return nil
+	default:
+		return errors.Errorf("unknown fallback mode %q", p.Mode)
+	}
+}
+
+// fallback returns routing info for index without routing in sync service according to policy.
+func (r *Resolver) fallback(companyID, indexType string) (*ClusterInfo, error) {
+	policy, ok := r.fallbacks[indexType]
+	if !ok {
+		policy = r.defaultFallback
+	}
+
+	var clusterName string
+	switch policy.Mode {
+	case FallbackError:
+		return nil, errors.Wrapf(ErrNoRouting, "company %q, index type %q", companyID, indexType)
+	case FallbackCluster:
+		clusterName = policy.Cluster
+	default:
+		defaultEntry, err := r.registry.GetEntry("")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get default cluster entry")
+		}
+		clusterName = defaultEntry.Name
+	}
+
+	prefix := r.getIndexPrefix(indexType)
+	return &ClusterInfo{
+		ClusterName: clusterName,
+		ClusterID:   0,
+		IndexName:   fmt.Sprintf("%s%s", prefix, companyID),
 	}, nil
 }
 
@@ -126,14 +345,134 @@ func (r *Resolver) getIndexPrefix(indexType string) string {
 
 // Resolve resolves cluster and index for company and index type.
 // Returns typed client and index name.
-// If sync service returns empty response (index not migrated yet),
-// returns default cluster client and index name in format: <indexType>_<companyID>
+// If sync service returns empty response (index not migrated yet), fallback policy
+// of index type applies: by default returns default cluster client and index name
+// in format: <prefix><companyID>
 func (r *Resolver) Resolve(ctx context.Context, companyID, indexType string) (*Client, string, error) {
+	info, err := r.resolveInfo(ctx, companyID, indexType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := r.getClient(info.ClusterName)
+	return client, info.IndexName, err
+}
+
+// ResolveSearch resolves client and index expression for reads.
+// For companies split into multiple indices it returns pattern or list covering all of them.
+// During live migration it returns read target override (see ClusterInfo.Read).
+func (r *Resolver) ResolveSearch(ctx context.Context, companyID, indexType string) (*Client, string, error) {
+	info, err := r.resolveInfo(ctx, companyID, indexType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	loc := info.ReadLocation()
+	client, err := r.getClient(loc.ClusterName)
+	return client, loc.IndexName, err
+}
+
+// ResolveSearchTarget resolves read target like ResolveSearch, together with its index target.
+// Repository and TenantClient use it to search company index patterns and lists without
+// company filter and routing.
+func (r *Resolver) ResolveSearchTarget(ctx context.Context, companyID, indexType string) (*ResolvedTarget, error) {
+	info, err := r.resolveInfo(ctx, companyID, indexType)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := r.resolveTarget(info.ReadLocation(), info)
+	if err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// ResolveWrite resolves client and index for writes.
+// For companies split into multiple indices it returns designated write index.
+// Only primary write target is returned; use ResolveTargets to dual-write during live migration.
+func (r *Resolver) ResolveWrite(ctx context.Context, companyID, indexType string) (*Client, string, error) {
+	info, err := r.resolveInfo(ctx, companyID, indexType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := r.getClient(info.ClusterName)
+	return client, info.WriteTarget(), err
+}
+
+// ResolveTargets resolves distinct read and write targets of company index.
+// Required during live migrations where reads stay on the old cluster while writes go to both.
+func (r *Resolver) ResolveTargets(ctx context.Context, companyID, indexType string) (*Resolution, error) {
+	info, err := r.resolveInfo(ctx, companyID, indexType)
+	if err != nil {
+		return nil, err
+	}
+
+	read, err := r.resolveTarget(info.ReadLocation(), info)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Resolution{
+		Read:   read,
+		Source: info.Source,
+	}
+	for _, loc := range info.WriteLocations() {
+		target, err := r.resolveTarget(loc, info)
+		if err != nil {
+			return nil, err
+		}
+		res.Writes = append(res.Writes, target)
+	}
+
+	return res, nil
+}
+
+// resolveTarget attaches typed client and index target to index location of info.
+func (r *Resolver) resolveTarget(loc IndexLocation, info *ClusterInfo) (ResolvedTarget, error) {
+	client, err := r.getClient(loc.ClusterName)
+	if err != nil {
+		return ResolvedTarget{}, err
+	}
+	return ResolvedTarget{ClusterName: loc.ClusterName, Index: loc.IndexName, Target: info.IndexTarget(loc.IndexName), Client: client}, nil
+}
+
+// ResolveRaw resolves cluster info without creating client.
+// Useful when you need just the cluster name and index.
+// Source of returned info tells where routing came from (cache, sync or fallback).
+// If sync service returns empty response (index not migrated yet), fallback policy
+// of index type applies (see Resolve).
+func (r *Resolver) ResolveRaw(ctx context.Context, companyID, indexType string) (*ClusterInfo, error) {
+	return r.resolveInfo(ctx, companyID, indexType)
+}
+
+// resolveInfo resolves routing info and applies cluster pin of context.
+func (r *Resolver) resolveInfo(ctx context.Context, companyID, indexType string) (*ClusterInfo, error) {
+	info, err := r.lookupInfo(ctx, companyID, indexType)
+	if err != nil {
+		return nil, err
+	}
+
+	pin, ok := ClusterPin(ctx)
+	if !ok {
+		return info, nil
+	}
+	r.log.DebugWithCtx(ctx, "elasticsearch resolver cluster pinned", map[string]interface{}{
+		"cluster_name":   pin,
+		"routed_cluster": info.ClusterName,
+		"index_name":     info.IndexName,
+	})
+	return info.pinned(pin), nil
+}
+
+// lookupInfo resolves routing info via cache, sync service and fallback policy.
+func (r *Resolver) lookupInfo(ctx context.Context, companyID, indexType string) (*ClusterInfo, error) {
 	if companyID == "" {
-		return nil, "", errors.New("company ID is required")
+		return nil, errors.New("company ID is required")
 	}
 	if indexType == "" {
-		return nil, "", errors.New("index type is required")
+		return nil, errors.New("index type is required")
 	}
 
 	r.log.DebugWithCtx(ctx, "elasticsearch resolver resolve", map[string]interface{}{
@@ -144,12 +483,13 @@ func (r *Resolver) Resolve(ctx context.Context, companyID, indexType string) (*C
 	// 1. Try Redis cache
 	info, err := r.getFromCache(ctx, companyID, indexType)
 	if err == nil && info != nil && info.ClusterName != "" {
+		info.Source = ResolutionSourceCache
+		r.countResolution(ResolutionSourceCache)
 		r.log.DebugWithCtx(ctx, "elasticsearch resolver cache hit", map[string]interface{}{
 			"cluster_name": info.ClusterName,
 			"index_name":   info.IndexName,
 		})
-		client, err := r.getClient(info.ClusterName)
-		return client, info.IndexName, err
+		return info, nil
 	}
 
 	r.log.DebugWithCtx(ctx, "elasticsearch resolver cache miss", nil)
@@ -157,84 +497,113 @@ func (r *Resolver) Resolve(ctx context.Context, companyID, indexType string) (*C
 	// 2. Fetch from sync service
 	info, err = r.fetchFromSync(ctx, companyID, indexType)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "failed to fetch from sync service")
+		return nil, errors.Wrap(err, "failed to fetch from sync service")
 	}
 
-	// 3. If sync returned empty info, index not migrated yet - use default cluster
+	// 3. If sync returned empty info, index not migrated yet - apply fallback policy
 	// DON'T cache this - we want to check sync service again after migration
 	if info == nil || info.ClusterName == "" {
-		prefix := r.getIndexPrefix(indexType)
-		indexName := fmt.Sprintf("%s%s", prefix, companyID)
-		r.log.DebugWithCtx(ctx, "elasticsearch resolver using default cluster (not migrated)", map[string]interface{}{
-			"index_name": indexName,
+		info, err = r.fallback(companyID, indexType)
+		if err != nil {
+			return nil, err
+		}
+		info.Source = ResolutionSourceFallback
+
+		count := r.countFallback(companyID)
+		logWarn(ctx, r.log, "elasticsearch resolver using fallback routing (not migrated)", map[string]interface{}{
+			"company_id":     companyID,
+			"index_type":     indexType,
+			"cluster_name":   info.ClusterName,
+			"index_name":     info.IndexName,
+			"fallback_count": count,
 		})
-		return r.defaultClient, indexName, nil
+		return info, nil
 	}
 
+	info.Source = ResolutionSourceSync
+	r.countResolution(ResolutionSourceSync)
 	r.log.DebugWithCtx(ctx, "elasticsearch resolver resolved from sync", map[string]interface{}{
 		"cluster_name": info.ClusterName,
 		"index_name":   info.IndexName,
 	})
 
 	// 4. Save to cache asynchronously with timeout (only cache migrated indices)
+	cached := *info
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
-		_ = r.saveToCache(ctx, companyID, indexType, info)
+		_ = r.saveToCache(ctx, companyID, indexType, &cached)
 	}()
 
-	// 5. Get cached client
-	client, err := r.getClient(info.ClusterName)
-	return client, info.IndexName, err
+	return info, nil
 }
 
-// ResolveRaw resolves cluster info without creating client.
-// Useful when you need just the cluster name and index.
-// If sync service returns empty response (index not migrated yet),
-// returns default cluster info with index name in format: <indexType>_<companyID>
-func (r *Resolver) ResolveRaw(ctx context.Context, companyID, indexType string) (*ClusterInfo, error) {
-	if companyID == "" {
-		return nil, errors.New("company ID is required")
-	}
-	if indexType == "" {
-		return nil, errors.New("index type is required")
-	}
+// fallbackCountTTL is how long fallback counter of company is kept after its last fallback
+// resolution, so counters of migrated companies don't pile up in long-running resolver.
+const fallbackCountTTL = time.Hour
 
-	// Try cache first
-	info, err := r.getFromCache(ctx, companyID, indexType)
-	if err == nil && info != nil && info.ClusterName != "" {
-		return info, nil
-	}
+// fallbackCount is fallback counter of company.
+type fallbackCount struct {
+	count int64
+	last  time.Time // Time of last fallback resolution
+}
 
-	// Fetch from sync
-	info, err = r.fetchFromSync(ctx, companyID, indexType)
-	if err != nil {
-		return nil, err
+// countFallback increments and returns fallback counter of company.
+// Counters not incremented for fallbackCountTTL are dropped.
+func (r *Resolver) countFallback(companyID string) int64 {
+	r.fallbackMu.Lock()
+	defer r.fallbackMu.Unlock()
+
+	now := r.clock.Now()
+	if now.Sub(r.fallbackPruned) >= fallbackCountTTL {
+		for id, c := range r.fallbackCounts {
+			if now.Sub(c.last) >= fallbackCountTTL {
+				delete(r.fallbackCounts, id)
+			}
+		}
+		r.fallbackPruned = now
 	}
 
-	// If sync returned empty info, index not migrated yet - use default cluster
-	// DON'T cache this - we want to check sync service again after migration
-	if info == nil || info.ClusterName == "" {
-		defaultEntry, err := r.registry.GetEntry("")
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to get default cluster entry")
-		}
-		prefix := r.getIndexPrefix(indexType)
-		return &ClusterInfo{
-			ClusterName: defaultEntry.Name,
-			ClusterID:   0,
-			IndexName:   fmt.Sprintf("%s%s", prefix, companyID),
-		}, nil
+	c := r.fallbackCounts[companyID]
+	c.count++
+	c.last = now
+	r.fallbackCounts[companyID] = c
+	r.resolutions[ResolutionSourceFallback]++
+	return c.count
+}
+
+// countResolution increments counter of resolution source.
+func (r *Resolver) countResolution(source ResolutionSource) {
+	r.fallbackMu.Lock()
+	defer r.fallbackMu.Unlock()
+
+	r.resolutions[source]++
+}
+
+// ResolutionCounts returns number of resolutions per source since resolver creation.
+// Ratio of cache to sync resolutions is hit rate of Redis cache.
+func (r *Resolver) ResolutionCounts() map[ResolutionSource]int64 {
+	r.fallbackMu.Lock()
+	defer r.fallbackMu.Unlock()
+
+	result := make(map[ResolutionSource]int64, len(r.resolutions))
+	for source, count := range r.resolutions {
+		result[source] = count
 	}
+	return result
+}
 
-	// Cache asynchronously with timeout (only cache migrated indices)
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-		_ = r.saveToCache(ctx, companyID, indexType, info)
-	}()
+// FallbackCounts returns number of fallback resolutions per company that fell back within
+// last fallbackCountTTL (1h). Companies with growing counters are stuck on fallback routing.
+func (r *Resolver) FallbackCounts() map[string]int64 {
+	r.fallbackMu.Lock()
+	defer r.fallbackMu.Unlock()
 
-	return info, nil
+	result := make(map[string]int64, len(r.fallbackCounts))
+	for companyID, c := range r.fallbackCounts {
+		result[companyID] = c.count
+	}
+	return result
 }
 
 // getFromCache retrieves cluster info from Redis.
@@ -254,6 +623,15 @@ func (r *Resolver) getFromCache(ctx context.Context, companyID, indexType string
 		return nil, errors.Wrap(err, "failed to unmarshal cached info")
 	}
 
+	// Entry written by newer library version; treat as miss so sync service response decides
+	if err := info.checkSchemaVersion(); err != nil {
+		return nil, err
+	}
+
+	if !info.CachedAt.IsZero() && r.clock.Now().Sub(info.CachedAt) >= r.cacheTTL {
+		return nil, errors.New("cache entry expired")
+	}
+
 	return &info, nil
 }
 
@@ -261,6 +639,7 @@ func (r *Resolver) getFromCache(ctx context.Context, companyID, indexType string
 func (r *Resolver) saveToCache(ctx context.Context, companyID, indexType string, info *ClusterInfo) error {
 	key := fmt.Sprintf("es_settings_%s_%s", companyID, indexType)
 
+	info.CachedAt = r.clock.Now()
 	data, err := json.Marshal(info)
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal info")
@@ -293,6 +672,9 @@ func (r *Resolver) fetchFromSync(ctx context.Context, companyID, indexType strin
 		return nil, errors.Wrap(err, "failed to create HTTP request")
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if r.syncToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.syncToken)
+	}
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -306,7 +688,7 @@ func (r *Resolver) fetchFromSync(ctx context.Context, companyID, indexType strin
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, syncErrorBodyLimit))
 		return nil, fmt.Errorf("sync service returned status %d: %s", resp.StatusCode, string(body))
 	}
 
@@ -315,6 +697,10 @@ func (r *Resolver) fetchFromSync(ctx context.Context, companyID, indexType strin
 		return nil, errors.Wrap(err, "failed to decode sync response")
 	}
 
+	if err := info.checkSchemaVersion(); err != nil {
+		return nil, err
+	}
+
 	// If cluster name is empty, sync returned empty response (not migrated yet)
 	if info.ClusterName == "" {
 		return nil, nil
@@ -324,14 +710,51 @@ func (r *Resolver) fetchFromSync(ctx context.Context, companyID, indexType strin
 }
 
 // getClient returns cached client from map by cluster name.
+// Clients are recreated after clusters of registry change at runtime.
 func (r *Resolver) getClient(clusterName string) (*Client, error) {
+	generation := r.registry.currentGeneration()
+
+	r.clientsMu.RLock()
 	client, ok := r.clients[clusterName]
-	if !ok {
-		return nil, errors.Errorf("cluster %q not found in clients map", clusterName)
+	fresh := r.generation == generation
+	r.clientsMu.RUnlock()
+	if ok && fresh {
+		return client, nil
+	}
+
+	entry, err := r.registry.GetEntry(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	client, err = newResolverClient(r.registry, entry, r.clientLog)
+	if err != nil {
+		return nil, err
+	}
+
+	r.clientsMu.Lock()
+	defer r.clientsMu.Unlock()
+	if r.generation != generation {
+		r.clients = make(map[string]*Client)
+		r.generation = generation
 	}
+	r.clients[clusterName] = client
 	return client, nil
 }
 
+// newResolverClient creates typed client of registered cluster for resolver.
+func newResolverClient(reg *Registry, entry Entry, log Logger) (*Client, error) {
+	baseURL, err := parseBaseURL(entry.BaseURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse base URL for cluster %q", entry.Name)
+	}
+
+	opts := reg.entryOpts(entry)
+	if log != nil {
+		opts = append(opts, WithLogger(log))
+	}
+	return newClient(entry.ES, baseURL, opts...), nil
+}
+
 // InvalidateCache removes cached cluster info for company and index type.
 func (r *Resolver) InvalidateCache(ctx context.Context, companyID, indexType string) error {
 	key := fmt.Sprintf("es_settings_%s_%s", companyID, indexType)
@@ -0,0 +1,338 @@
+package esclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterInfo_Targets(t *testing.T) {
+	info := &ClusterInfo{
+		ClusterName:  "cluster-b",
+		IndexName:    "orders_c1",
+		IndexPattern: "orders_c1_*",
+		WriteIndex:   "orders_c1_2026-10",
+	}
+
+	assert.Equal(t, "orders_c1_*", info.SearchTarget())
+	assert.Equal(t, "orders_c1_2026-10", info.WriteTarget())
+	assert.Equal(t, IndexLocation{ClusterName: "cluster-b", IndexName: "orders_c1_*"}, info.ReadLocation())
+	assert.Equal(t, []IndexLocation{{ClusterName: "cluster-b", IndexName: "orders_c1_2026-10"}}, info.WriteLocations())
+}
+
+func TestClusterInfo_LiveMigration(t *testing.T) {
+	info := &ClusterInfo{
+		ClusterName: "cluster-b",
+		IndexName:   "orders_c1",
+		Read:        &IndexLocation{ClusterName: "cluster-a"},
+		DualWrites:  []IndexLocation{{ClusterName: "cluster-a", IndexName: "orders_old_c1"}},
+	}
+
+	assert.Equal(t, IndexLocation{ClusterName: "cluster-a", IndexName: "orders_c1"}, info.ReadLocation())
+	assert.Equal(t, []IndexLocation{
+		{ClusterName: "cluster-b", IndexName: "orders_c1"},
+		{ClusterName: "cluster-a", IndexName: "orders_old_c1"},
+	}, info.WriteLocations())
+}
+
+func TestResolver_FetchFromSync_Token(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"cluster_name": "tier-gold", "index_name": "orders_c1"}`))
+	}))
+	defer server.Close()
+
+	r := &Resolver{syncURL: server.URL, syncToken: "sync-token", httpClient: server.Client()}
+	info, err := r.fetchFromSync(context.Background(), "c1", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "tier-gold", info.ClusterName)
+	assert.Equal(t, "Bearer sync-token", auth)
+}
+
+func TestResolver_ClusterPin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"cluster_name": "tier-gold", "index_name": "orders_c1",
+			"read": {"cluster_name": "tier-silver"}, "dual_writes": [{"cluster_name": "tier-silver"}]}`))
+	}))
+	defer server.Close()
+
+	reg := NewRegistry("tier-gold")
+	reg.byName["tier-gold"] = Entry{Name: "tier-gold", Version: 8, BaseURL: "http://gold:9200", ES: &fakeES{}}
+	reg.byName["tier-replay"] = Entry{Name: "tier-replay", Version: 8, BaseURL: "http://replay:9200", ES: &fakeES{}}
+
+	r, err := NewResolver(ResolverConfig{
+		Registry:   reg,
+		Redis:      unavailableRedis(),
+		SyncURL:    server.URL,
+		HTTPClient: server.Client(),
+	})
+	require.NoError(t, err)
+
+	_, ok := ClusterPin(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithClusterPin(context.Background(), "tier-replay")
+	res, err := r.ResolveTargets(ctx, "c1", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "tier-replay", res.Read.ClusterName)
+	assert.Equal(t, "orders_c1", res.Read.Index)
+	require.Len(t, res.Writes, 1)
+	assert.Equal(t, "tier-replay", res.Writes[0].ClusterName)
+
+	_, _, err = r.ResolveWrite(WithClusterPin(context.Background(), "missing"), "c1", "orders")
+	assert.Error(t, err)
+}
+
+func TestResolver_FallbackPolicy(t *testing.T) {
+	// Sync service has no routing for any company
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	reg := NewRegistry("tier-gold")
+	reg.byName["tier-gold"] = Entry{Name: "tier-gold", Version: 8, BaseURL: "http://gold:9200", ES: &fakeES{}}
+	reg.byName["tier-silver"] = Entry{Name: "tier-silver", Version: 8, BaseURL: "http://silver:9200", ES: &fakeES{}}
+
+	tests := []struct {
+		name        string
+		policies    map[string]FallbackPolicy
+		defaultPol  FallbackPolicy
+		indexType   string
+		wantCluster string
+		wantIndex   string
+		wantErr     error
+		wantNewErr  string
+	}{
+		{name: "default cluster by default", indexType: "orders", wantCluster: "tier-gold", wantIndex: "orders_c1"},
+		{name: "default cluster prefix map", indexType: "product_tree", wantCluster: "tier-gold", wantIndex: "products_c1"},
+		{
+			name:       "default error",
+			defaultPol: FallbackPolicy{Mode: FallbackError},
+			indexType:  "orders",
+			wantErr:    ErrNoRouting,
+		},
+		{
+			name:        "default cluster policy",
+			defaultPol:  FallbackPolicy{Mode: FallbackCluster, Cluster: "tier-silver"},
+			indexType:   "orders",
+			wantCluster: "tier-silver",
+			wantIndex:   "orders_c1",
+		},
+		{
+			name:      "index type error",
+			policies:  map[string]FallbackPolicy{"orders": {Mode: FallbackError}},
+			indexType: "orders",
+			wantErr:   ErrNoRouting,
+		},
+		{
+			name:        "index type cluster",
+			policies:    map[string]FallbackPolicy{"orders": {Mode: FallbackCluster, Cluster: "tier-silver"}},
+			defaultPol:  FallbackPolicy{Mode: FallbackError},
+			indexType:   "orders",
+			wantCluster: "tier-silver",
+			wantIndex:   "orders_c1",
+		},
+		{
+			name:        "other index type uses default",
+			policies:    map[string]FallbackPolicy{"orders": {Mode: FallbackError}},
+			indexType:   "clients",
+			wantCluster: "tier-gold",
+			wantIndex:   "clients_c1",
+		},
+		{
+			name:       "cluster mode without cluster",
+			defaultPol: FallbackPolicy{Mode: FallbackCluster},
+			wantNewErr: "invalid default fallback policy: fallback cluster name is required",
+		},
+		{
+			name:       "unknown cluster",
+			policies:   map[string]FallbackPolicy{"orders": {Mode: FallbackCluster, Cluster: "tier-bronze"}},
+			wantNewErr: `invalid fallback policy for index type "orders": cluster "tier-bronze" not found`,
+		},
+		{
+			name:       "unknown mode",
+			defaultPol: FallbackPolicy{Mode: "nearest"},
+			wantNewErr: `unknown fallback mode "nearest"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewResolver(ResolverConfig{
+				Registry:         reg,
+				Redis:            unavailableRedis(),
+				SyncURL:          server.URL,
+				HTTPClient:       server.Client(),
+				FallbackPolicies: tt.policies,
+				DefaultFallback:  tt.defaultPol,
+			})
+			if tt.wantNewErr != "" {
+				assert.ErrorContains(t, err, tt.wantNewErr)
+				return
+			}
+			require.NoError(t, err)
+
+			target, err := r.ResolveSearchTarget(context.Background(), "c1", tt.indexType)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, r.FallbackCounts())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCluster, target.ClusterName)
+			assert.Equal(t, tt.wantIndex, target.Index)
+			assert.Equal(t, map[string]int64{"c1": 1}, r.FallbackCounts())
+		})
+	}
+}
+
+func TestResolver_FallbackCountsExpire(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	reg := NewRegistry("tier-gold")
+	reg.byName["tier-gold"] = Entry{Name: "tier-gold", Version: 8, BaseURL: "http://gold:9200", ES: &fakeES{}}
+	clock := NewFakeClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	r, err := NewResolver(ResolverConfig{Registry: reg, Redis: unavailableRedis(), SyncURL: server.URL, HTTPClient: server.Client(), Clock: clock})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, companyID := range []string{"c1", "c2", "c2"} {
+		_, err = r.ResolveSearchTarget(ctx, companyID, "orders")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, map[string]int64{"c1": 1, "c2": 2}, r.FallbackCounts())
+
+	// c1 got routing and stopped falling back, c2 is still stuck
+	clock.Advance(fallbackCountTTL - time.Minute)
+	_, err = r.ResolveSearchTarget(ctx, "c2", "orders")
+	require.NoError(t, err)
+	clock.Advance(2 * time.Minute)
+	_, err = r.ResolveSearchTarget(ctx, "c2", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"c2": 4}, r.FallbackCounts())
+}
+
+func TestResolver_CheckSchemaVersion(t *testing.T) {
+	var response string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		response string
+		wantErr  bool
+	}{
+		{name: "no version", response: `{"cluster_name": "tier-gold", "index_name": "orders_c1"}`},
+		{name: "supported version", response: `{"schema_version": 1, "cluster_name": "tier-gold", "index_name": "orders_c1"}`},
+		{name: "newer version", response: `{"schema_version": 2, "cluster_name": "tier-gold", "index_name": "orders_c1"}`, wantErr: true},
+		{name: "newer version without routing", response: `{"schema_version": 2}`, wantErr: true},
+	}
+
+	r := &Resolver{syncURL: server.URL, httpClient: server.Client()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response = tt.response
+			info, err := r.fetchFromSync(context.Background(), "c1", "orders")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+				assert.ErrorContains(t, err, "got schema version 2, supported up to 1")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "orders_c1", info.IndexName)
+		})
+	}
+}
+
+func TestTenantClient_SearchIndexPattern(t *testing.T) {
+	const companyID = "5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"cluster_name": "tier-gold", "index_name": "orders_` + companyID + `",
+			"index_pattern": "orders_` + companyID + `_*", "write_index": "orders_` + companyID + `_2026-10"}`))
+	}))
+	defer server.Close()
+
+	es := &fakeES{response: `{"hits": {"hits": []}, "count": 0}`}
+	reg := NewRegistry("tier-gold")
+	reg.byName["tier-gold"] = Entry{Name: "tier-gold", Version: 8, BaseURL: "http://gold:9200", ES: es}
+	r, err := NewResolver(ResolverConfig{Registry: reg, Redis: unavailableRedis(), SyncURL: server.URL, HTTPClient: server.Client()})
+	require.NoError(t, err)
+
+	target, err := r.ResolveSearchTarget(context.Background(), companyID, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "orders_"+companyID+"_*", target.Index)
+	assert.Equal(t, IndexTargetPerCompany, target.Target)
+	// Name alone looks shared
+	assert.Equal(t, IndexTargetShared, DetectIndexTarget(target.Index))
+
+	tenant, err := NewTenantClient(r, companyID)
+	require.NoError(t, err)
+	_, err = tenant.Search(context.Background(), "orders", &TenantSearchRequest{
+		Query: map[string]any{"query": map[string]any{"term": map[string]any{"status": "paid"}}},
+	})
+	require.NoError(t, err)
+	_, err = tenant.Count(context.Background(), "orders", nil)
+	require.NoError(t, err)
+
+	require.Len(t, es.requests, 2)
+	for i, req := range es.requests {
+		assert.Equal(t, "/orders_"+companyID+"_*/"+[]string{"_search", "_count"}[i], req.URL.Path)
+		assert.Empty(t, req.URL.Query().Get("routing"))
+		assert.NotContains(t, es.bodies[i], "company_id")
+	}
+}
+
+func TestTenantClient_SearchReadOverrideShared(t *testing.T) {
+	const companyID = "5f0c7a4e-2b1d-4c8e-9a3f-6d2e1b0c9a87"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"cluster_name": "tier-gold", "index_name": "orders_` + companyID + `",
+			"index_pattern": "orders_` + companyID + `_*", "read": {"cluster_name": "tier-gold", "index_name": "orders_shared"}}`))
+	}))
+	defer server.Close()
+
+	es := &fakeES{response: `{"hits": {"hits": []}}`}
+	reg := NewRegistry("tier-gold")
+	reg.byName["tier-gold"] = Entry{Name: "tier-gold", Version: 8, BaseURL: "http://gold:9200", ES: es}
+	r, err := NewResolver(ResolverConfig{Registry: reg, Redis: unavailableRedis(), SyncURL: server.URL, HTTPClient: server.Client()})
+	require.NoError(t, err)
+
+	target, err := r.ResolveSearchTarget(context.Background(), companyID, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "orders_shared", target.Index)
+	assert.Equal(t, IndexTargetShared, target.Target)
+
+	tenant, err := NewTenantClient(r, companyID)
+	require.NoError(t, err)
+	_, err = tenant.Search(context.Background(), "orders", &TenantSearchRequest{
+		Query: map[string]any{"query": map[string]any{"term": map[string]any{"status": "paid"}}},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, es.requests, 1)
+	assert.Equal(t, "/orders_shared/_search", es.requests[0].URL.Path)
+	assert.Equal(t, companyID, es.requests[0].URL.Query().Get("routing"))
+	assert.Contains(t, es.bodies[0], `"company_id.keyword":"`+companyID+`"`)
+}
+
+// unavailableRedis returns Redis client failing every command, so resolver always misses cache.
+func unavailableRedis() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		MaxRetries:         -1,
+		DialerRetries:      1,
+		DialerRetryTimeout: time.Millisecond,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return nil, errors.New("redis unavailable")
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+)
+
+// ResponseMeta holds selected headers of Elasticsearch response.
+// Embedded in typed responses; not part of JSON body.
+type ResponseMeta struct {
+	Product  string   // X-Elastic-Product, "Elasticsearch" for genuine clusters (ES 7.14+)
+	OpaqueID string   // X-Opaque-Id echoed from request, if set
+	Warnings []string // Warning headers, e.g. deprecation warnings of used APIs or settings
+	Cluster  string   // Cluster that served request if ClusterConfig.FallbackCluster is set, empty otherwise
+}
+
+// Deprecated reports whether response carries deprecation warnings.
+func (m *ResponseMeta) Deprecated() bool {
+	return len(m.Warnings) > 0
+}
+
+// responseMeta returns meta of typed response to fill.
+func (m *ResponseMeta) responseMeta() *ResponseMeta {
+	return m
+}
+
+// metaHolder is typed response embedding ResponseMeta.
+type metaHolder interface {
+	responseMeta() *ResponseMeta
+}
+
+// captureMeta fills response meta of out from headers if out embeds ResponseMeta
+// and logs warnings on debug level.
+func (c *Client) captureMeta(ctx context.Context, req *http.Request, header http.Header, out interface{}) {
+	warnings := header.Values("Warning")
+	if len(warnings) > 0 {
+		c.log.DebugWithCtx(ctx, "elasticsearch response warnings", map[string]interface{}{
+			"method":   req.Method,
+			"path":     req.URL.Path,
+			"warnings": warnings,
+		})
+	}
+
+	holder, ok := out.(metaHolder)
+	if !ok {
+		return
+	}
+	*holder.responseMeta() = ResponseMeta{
+		Product:  header.Get("X-Elastic-Product"),
+		OpaqueID: header.Get("X-Opaque-Id"),
+		Warnings: warnings,
+		Cluster:  header.Get(servedByHeader),
+	}
+}
@@ -0,0 +1,194 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultPITKeepAlive is keep alive of point-in-time when not specified.
+const defaultPITKeepAlive = time.Minute
+
+// deadlineSafetyMargin is subtracted from remaining context deadline when deriving
+// server-side timeout, leaving time for response transfer and decoding.
+const deadlineSafetyMargin = 100 * time.Millisecond
+
+// buildSearchBody applies typed SearchRequest options to query body.
+func buildSearchBody(ctx context.Context, body map[string]any, req *SearchRequest) {
+	if req.Highlight != nil {
+		body["highlight"] = req.Highlight.body()
+	}
+	if len(req.Sort) > 0 {
+		sort := make([]any, 0, len(req.Sort))
+		for _, clause := range req.Sort {
+			sort = append(sort, clause.body())
+		}
+		body["sort"] = sort
+	}
+	if timeout := searchTimeout(ctx, req.Timeout); timeout > 0 {
+		body["timeout"] = formatDuration(timeout)
+	}
+	if req.PointInTime != nil {
+		keepAlive := req.PITKeepAlive
+		if keepAlive <= 0 {
+			keepAlive = defaultPITKeepAlive
+		}
+		body["pit"] = map[string]any{
+			"id":         *req.PointInTime,
+			"keep_alive": formatDuration(keepAlive),
+		}
+	}
+	if req.SearchAfter != nil {
+		body["search_after"] = req.SearchAfter
+	}
+	if req.TerminateAfter > 0 {
+		body["terminate_after"] = req.TerminateAfter
+	}
+	if req.MinScore != nil {
+		body["min_score"] = *req.MinScore
+	}
+}
+
+// searchTimeout returns server-side search timeout.
+// If context has deadline, timeout is capped by remaining time minus safety margin,
+// so ES stops work when caller has already given up.
+func searchTimeout(ctx context.Context, explicit time.Duration) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return explicit
+	}
+
+	remaining := time.Until(deadline) - deadlineSafetyMargin
+	if remaining <= 0 {
+		// Too little time left to derive meaningful budget; let context cancellation handle it
+		return explicit
+	}
+	if explicit > 0 && explicit < remaining {
+		return explicit
+	}
+	return remaining
+}
+
+// formatDuration formats duration as ES time unit in milliseconds (e.g., "1500ms").
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+// body converts sort clause to ES sort element.
+func (s SortClause) body() map[string]any {
+	opts := make(map[string]any)
+	if s.Order != "" {
+		opts["order"] = s.Order
+	}
+	if s.Missing != nil {
+		opts["missing"] = s.Missing
+	}
+	if s.Mode != "" {
+		opts["mode"] = s.Mode
+	}
+	if s.NestedPath != "" {
+		nested := map[string]any{"path": s.NestedPath}
+		if s.NestedFilter != nil {
+			nested["filter"] = s.NestedFilter
+		}
+		opts["nested"] = nested
+	}
+
+	return map[string]any{s.Field: opts}
+}
+
+// body converts highlight configuration to ES highlight section.
+func (h *Highlight) body() map[string]any {
+	fields := make(map[string]any, len(h.Fields))
+	for _, field := range h.Fields {
+		fields[field] = map[string]any{}
+	}
+
+	result := map[string]any{
+		"fields": fields,
+	}
+	if len(h.PreTags) > 0 {
+		result["pre_tags"] = h.PreTags
+	}
+	if len(h.PostTags) > 0 {
+		result["post_tags"] = h.PostTags
+	}
+	if h.FragmentSize != nil {
+		result["fragment_size"] = *h.FragmentSize
+	}
+
+	return result
+}
+
+// Highlights returns highlight snippets of every hit keyed by field name.
+// Result is aligned with Hits.Hits; hits without highlight have nil entry.
+func (r *SearchResponse) Highlights() []map[string][]string {
+	result := make([]map[string][]string, len(r.Hits.Hits))
+	for i, hit := range r.Hits.Hits {
+		result[i] = HitHighlight(hit)
+	}
+	return result
+}
+
+// HitHighlight extracts highlight snippets keyed by field name from a single search hit.
+func HitHighlight(hit map[string]interface{}) map[string][]string {
+	raw, ok := hit["highlight"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string][]string, len(raw))
+	for field, val := range raw {
+		fragments, ok := val.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, fragment := range fragments {
+			if s, ok := fragment.(string); ok {
+				result[field] = append(result[field], s)
+			}
+		}
+	}
+
+	return result
+}
+
+// Total returns total hits and whether it is exact. Total is a lower bound if relation is "gte"
+// (more than track_total_hits limit, 10000 by default) or if total hits were not tracked.
+func (r *SearchResponse) Total() (int, bool) {
+	return r.Hits.Total.Value, r.Hits.Total.Relation == "eq"
+}
+
+// ExactTotal returns exact total hits of search, running follow-up count of req query
+// if resp total is only a lower bound.
+func (c *Client) ExactTotal(ctx context.Context, req *SearchRequest, resp *SearchResponse) (int, error) {
+	if total, exact := resp.Total(); exact {
+		return total, nil
+	}
+
+	q, _ := req.Query["query"].(map[string]any)
+	if req.crossTenant != nil && indexTarget(req.Target, req.Index) == IndexTargetShared {
+		// Bypass was already validated and audited by search
+		count, err := c.countAll(ctx, req.Index, q)
+		return int(count), err
+	}
+
+	var query map[string]any
+	if q != nil {
+		query = map[string]any{"query": q}
+	}
+
+	count, err := c.Count(ctx, &CountRequest{
+		Index:             req.Index,
+		Target:            req.Target,
+		Query:             query,
+		CompanyID:         req.CompanyID,
+		IgnoreUnavailable: req.IgnoreUnavailable,
+		AllowNoIndices:    req.AllowNoIndices,
+		Routing:           req.Routing,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.Count, nil
+}
@@ -0,0 +1,88 @@
+package esclient
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// defaultIDsChunkSize is number of IDs per query, kept within default index.max_result_window.
+const defaultIDsChunkSize = 10000
+
+// SearchByIDsRequest represents search of documents by IDs.
+type SearchByIDsRequest struct {
+	Index     string   // Index name or pattern
+	IDs       []string // Document IDs, duplicates are fetched once
+	CompanyID string   // Company ID for shared index
+	Routing   string   // Routing value; defaults to CompanyID for shared indices
+	Source    []string // Source fields to return, all fields if empty
+	ChunkSize int      // Number of IDs per query (default: 10000)
+}
+
+// SearchByIDs fetches documents by IDs. Large ID lists are split into chunks queried one by one,
+// since a single ids/terms query with tens of thousands of values exceeds query and result limits.
+// Hits of all chunks are merged into one response in chunk order; missing documents are skipped.
+func (c *Client) SearchByIDs(ctx context.Context, req *SearchByIDsRequest) (*SearchResponse, error) {
+	if req.Index == "" {
+		return nil, errors.New("index name is required")
+	}
+
+	chunkSize := req.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultIDsChunkSize
+	}
+
+	ids := uniqueStrings(req.IDs)
+	merged := &SearchResponse{}
+	merged.Hits.Hits = make([]map[string]interface{}, 0, len(ids))
+
+	for start := 0; start < len(ids); start += chunkSize {
+		end := min(start+chunkSize, len(ids))
+		chunk := ids[start:end]
+
+		query := map[string]any{
+			"query": map[string]any{
+				"ids": map[string]any{"values": chunk},
+			},
+		}
+		if len(req.Source) > 0 {
+			query["_source"] = req.Source
+		}
+
+		size := len(chunk)
+		resp, err := c.Search(ctx, &SearchRequest{
+			Index:     req.Index,
+			Query:     query,
+			CompanyID: req.CompanyID,
+			Routing:   req.Routing,
+			Size:      &size,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to search IDs chunk %d-%d", start, end)
+		}
+
+		merged.Took += resp.Took
+		merged.TimedOut = merged.TimedOut || resp.TimedOut
+		merged.Hits.Hits = append(merged.Hits.Hits, resp.Hits.Hits...)
+		merged.Product = resp.Product
+		merged.Warnings = append(merged.Warnings, resp.Warnings...)
+	}
+
+	merged.Hits.Total.Value = len(merged.Hits.Hits)
+	merged.Hits.Total.Relation = "eq"
+	return merged, nil
+}
+
+// uniqueStrings returns values without duplicates, preserving order.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
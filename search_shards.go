@@ -0,0 +1,109 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// SearchShardsRequest represents request of shards that would serve search.
+type SearchShardsRequest struct {
+	Index      string // Index name or pattern
+	Routing    string // Routing value, optional
+	Preference string // Shard copy preference, optional
+}
+
+// SearchShardsResponse represents _search_shards response.
+type SearchShardsResponse struct {
+	Nodes  map[string]SearchShardsNode `json:"nodes"`  // Node ID -> node
+	Shards [][]ShardRouting            `json:"shards"` // Shard groups; one copy of each group serves search
+}
+
+// SearchShardsNode represents node holding searched shards.
+type SearchShardsNode struct {
+	Name             string `json:"name"`
+	TransportAddress string `json:"transport_address"`
+}
+
+// ShardRouting represents shard copy allocation.
+type ShardRouting struct {
+	Index   string `json:"index"`
+	Shard   int    `json:"shard"`
+	Node    string `json:"node"` // Node ID
+	Primary bool   `json:"primary"`
+	State   string `json:"state"`
+}
+
+// WithShardDiagnostics enables logging of nodes and shards serving every Search on debug level.
+// Each search is preceded by _search_shards request, so use only to diagnose hot shards
+// caused by company routing.
+func WithShardDiagnostics(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.shardDiagnostics = enabled
+	}
+}
+
+// SearchShards returns nodes and shards that would serve search with given routing and preference.
+func (c *Client) SearchShards(ctx context.Context, req *SearchShardsRequest) (*SearchShardsResponse, error) {
+	if req.Index == "" {
+		return nil, errors.New("index name is required")
+	}
+
+	query := url.Values{}
+	setRouting(query, req.Routing)
+	if req.Preference != "" {
+		query.Set("preference", req.Preference)
+	}
+
+	var resp SearchShardsResponse
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("/%s/_search_shards", req.Index), query, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "search_shards", StatusCode: status, body: errBody}
+	}
+
+	return &resp, nil
+}
+
+// logSearchShards logs shards and nodes that would serve search. Errors are logged, not returned,
+// so diagnostics never fail search itself.
+func (c *Client) logSearchShards(ctx context.Context, req *SearchShardsRequest) {
+	resp, err := c.SearchShards(ctx, req)
+	if err != nil {
+		c.log.DebugWithCtx(ctx, "elasticsearch search shards failed", map[string]interface{}{
+			"index": req.Index,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	shards := make([]string, 0, len(resp.Shards))
+	nodes := make(map[string]int)
+	for _, group := range resp.Shards {
+		// First copy of group is the one picked by preference
+		if len(group) == 0 {
+			continue
+		}
+		shard := group[0]
+		node := shard.Node
+		if n, ok := resp.Nodes[shard.Node]; ok {
+			node = n.Name
+		}
+		shards = append(shards, fmt.Sprintf("%s[%d]@%s", shard.Index, shard.Shard, node))
+		nodes[node]++
+	}
+
+	c.log.DebugWithCtx(ctx, "elasticsearch search shards", map[string]interface{}{
+		"index":      req.Index,
+		"routing":    req.Routing,
+		"preference": req.Preference,
+		"shards":     shards,
+		"nodes":      nodes,
+	})
+}
@@ -0,0 +1,155 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// SearchStream performs search and returns raw JSON response body without buffering it,
+// for export queries whose responses are too large to hold in memory. Caller must close body.
+// Request is built like in Search, including tenant filter and routing.
+func (c *Client) SearchStream(ctx context.Context, req *SearchRequest) (io.ReadCloser, error) {
+	httpReq, err := c.newSearchRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.send(ctx, httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close() //nolint:errcheck
+		body, err := c.readErrorBody(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		if blockErr := parseIndexBlockError(res.StatusCode, body.data); blockErr != nil {
+			return nil, blockErr
+		}
+		return nil, &StatusError{Op: "search", StatusCode: res.StatusCode, body: body}
+	}
+
+	return res.Body, nil
+}
+
+// SearchEachHit performs search and calls fn for every hit while response is being read,
+// so only one hit is held in memory at a time. Error returned by fn stops reading.
+// Returns response with everything except hits (took, total, aggregations).
+func (c *Client) SearchEachHit(ctx context.Context, req *SearchRequest, fn func(hit json.RawMessage) error) (*SearchResponse, error) {
+	body, err := c.SearchStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close() //nolint:errcheck
+
+	return StreamHits(body, fn)
+}
+
+// StreamHits reads search response JSON from r and calls fn for every hit as it is decoded.
+// Returns response with everything except hits.
+func StreamHits(r io.Reader, fn func(hit json.RawMessage) error) (*SearchResponse, error) {
+	dec := json.NewDecoder(r)
+	rest := make(map[string]json.RawMessage)
+
+	err := walkObject(dec, func(key string) error {
+		if key != "hits" {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			rest[key] = raw
+			return nil
+		}
+
+		hitsRest := make(map[string]json.RawMessage)
+		err := walkObject(dec, func(key string) error {
+			if key != "hits" {
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return err
+				}
+				hitsRest[key] = raw
+				return nil
+			}
+			return walkArray(dec, fn)
+		})
+		if err != nil {
+			return err
+		}
+		raw, err := json.Marshal(hitsRest)
+		if err != nil {
+			return err
+		}
+		rest["hits"] = raw
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to stream search response")
+	}
+
+	raw, err := json.Marshal(rest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode search response")
+	}
+	var resp SearchResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode search response")
+	}
+	return &resp, nil
+}
+
+// walkObject reads JSON object from dec, calling fn for every key with decoder positioned at its value.
+// fn must consume the value.
+func walkObject(dec *json.Decoder, fn func(key string) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return errors.Errorf("unexpected object key %v", tok)
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// walkArray reads JSON array from dec, calling fn with every element.
+func walkArray(dec *json.Decoder, fn func(elem json.RawMessage) error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for dec.More() {
+		var elem json.RawMessage
+		if err := dec.Decode(&elem); err != nil {
+			return err
+		}
+		if err := fn(elem); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim reads next token and checks it is delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return errors.Errorf("expected %q, got %v", delim, tok)
+	}
+	return nil
+}
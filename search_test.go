@@ -0,0 +1,426 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTimeout(t *testing.T) {
+	assert.Equal(t, time.Duration(0), searchTimeout(context.Background(), 0))
+	assert.Equal(t, 2*time.Second, searchTimeout(context.Background(), 2*time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Derived from deadline minus safety margin
+	timeout := searchTimeout(ctx, 0)
+	assert.Greater(t, timeout, 4*time.Second)
+	assert.LessOrEqual(t, timeout, 5*time.Second-deadlineSafetyMargin)
+
+	// Explicit shorter timeout wins
+	assert.Equal(t, time.Second, searchTimeout(ctx, time.Second))
+
+	// Explicit longer timeout is capped by deadline
+	assert.LessOrEqual(t, searchTimeout(ctx, time.Minute), 5*time.Second)
+}
+
+func TestClient_SearchByIDs(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"took": 2, "hits": {"hits": [{"_id": "1"}, {"_id": "2"}]}}`},
+		{body: `{"took": 3, "hits": {"hits": [{"_id": "3"}]}}`},
+	}}
+	client, err := NewClient(es, "http://localhost:9200")
+	require.NoError(t, err)
+
+	resp, err := client.SearchByIDs(context.Background(), &SearchByIDsRequest{
+		Index:     "orders_shared",
+		IDs:       []string{"1", "2", "2", "3"},
+		CompanyID: "c1",
+		ChunkSize: 2,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, es.bodies, 2)
+	assert.Contains(t, es.bodies[0], `"ids":{"values":["1","2"]}`)
+	assert.Contains(t, es.bodies[1], `"ids":{"values":["3"]}`)
+	assert.Equal(t, 5, resp.Took)
+	assert.Equal(t, 3, resp.Hits.Total.Value)
+	assert.Len(t, resp.Hits.Hits, 3)
+}
+
+func TestClient_Search_ShardDiagnostics(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{
+			"nodes": {"n1": {"name": "es-gold-1"}},
+			"shards": [[{"index": "orders_shared", "shard": 3, "node": "n1", "primary": true, "state": "STARTED"}]]
+		}`},
+		{body: `{"hits": {"hits": []}}`},
+	}}
+	client, err := NewClient(es, "http://localhost:9200", WithShardDiagnostics(true))
+	require.NoError(t, err)
+
+	_, err = client.Search(context.Background(), &SearchRequest{Index: "orders_shared", CompanyID: "c1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"GET /orders_shared/_search_shards", "POST /orders_shared/_search"}, es.paths)
+}
+
+func TestClient_MTermVectors(t *testing.T) {
+	es := &fakeES{response: `{"docs": [
+		{"_index": "products_shared", "_id": "p1", "found": true, "term_vectors": {
+			"name": {"terms": {"phone": {"term_freq": 2, "doc_freq": 10, "ttf": 40}}}
+		}},
+		{"_index": "products_shared", "_id": "p2", "found": false}
+	]}`}
+	client := newTestClient(t, es)
+
+	resp, err := client.MTermVectors(context.Background(), []TermVectorsRequest{
+		{Index: "products_shared", ID: "p1", CompanyID: "c1", Fields: []string{"name"}, TermStatistics: true},
+		{Index: "products_shared", ID: "p2", CompanyID: "c1", Fields: []string{"name"}, TermStatistics: true},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp, 2)
+	assert.Equal(t, 2, resp[0].TermVectors["name"].Terms["phone"].TermFreq)
+	assert.Equal(t, int64(10), resp[0].TermVectors["name"].Terms["phone"].DocFreq)
+	assert.False(t, resp[1].Found)
+
+	assert.Equal(t, "/_mtermvectors", es.requests[0].URL.Path)
+	assert.JSONEq(t, `{"docs": [
+		{"_index": "products_shared", "_id": "p1", "routing": "c1", "fields": ["name"], "term_statistics": true},
+		{"_index": "products_shared", "_id": "p2", "routing": "c1", "fields": ["name"], "term_statistics": true}
+	]}`, es.bodies[0])
+}
+
+func TestClient_SQLQuery(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"columns": [{"name": "status", "type": "keyword"}, {"name": "cnt", "type": "long"}], "rows": [["paid", 10]], "cursor": "c-1"}`},
+		{body: `{"rows": [["new", 3]]}`},
+	}}
+	client, err := NewClient(es, "http://localhost:9200")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	page, err := client.SQLQuery(ctx, "SELECT status, COUNT(*) AS cnt FROM orders_shared WHERE total > ? GROUP BY status",
+		[]any{100}, &SQLOptions{CompanyID: "c1", FetchSize: 1})
+	require.NoError(t, err)
+	assert.Len(t, page.Columns, 2)
+	assert.Equal(t, "c-1", page.Cursor)
+
+	assert.JSONEq(t, `{
+		"query": "SELECT status, COUNT(*) AS cnt FROM orders_shared WHERE total > ? GROUP BY status",
+		"params": [100],
+		"fetch_size": 1,
+		"filter": {"bool": {"filter": [{"term": {"company_id.keyword": "c1"}}]}}
+	}`, es.bodies[0])
+
+	page, err = client.SQLNextPage(ctx, page.Cursor)
+	require.NoError(t, err)
+	assert.Empty(t, page.Cursor)
+	assert.Equal(t, []any{"new", float64(3)}, page.Rows[0])
+	assert.JSONEq(t, `{"cursor": "c-1"}`, es.bodies[1])
+	assert.Equal(t, []string{"POST /_sql", "POST /_sql"}, es.paths)
+}
+
+func TestClient_Search_Knn(t *testing.T) {
+	const companyID = "01234567-89ab-cdef-0123-456789abcdef"
+	knn := &KnnSearch{
+		Field:       "embedding",
+		QueryVector: []float32{0.1, 0.2},
+		K:           5,
+		Filter:      map[string]any{"term": map[string]any{"status": "active"}},
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		want    string
+	}{
+		{"v8_explicit_candidates", 8, `{"knn": {
+			"field": "embedding", "query_vector": [0.1, 0.2], "k": 5, "num_candidates": 7,
+			"filter": [{"term": {"status": "active"}}, {"term": {"company_id.keyword": "` + companyID + `"}}]
+		}}`},
+		{"v9_server_defaults", 9, `{"knn": {
+			"field": "embedding", "query_vector": [0.1, 0.2], "k": 5,
+			"filter": [{"term": {"status": "active"}}, {"term": {"company_id.keyword": "` + companyID + `"}}]
+		}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &fakeES{}
+			client, err := NewClient(es, "http://localhost:9200", withVersion(tt.version))
+			require.NoError(t, err)
+
+			_, err = client.Search(context.Background(), &SearchRequest{
+				Index:     "products",
+				CompanyID: companyID,
+				Knn:       knn,
+			})
+			require.NoError(t, err)
+			// Pure kNN search on shared index is isolated by knn filter, without query section
+			assert.JSONEq(t, tt.want, es.bodies[0])
+		})
+	}
+}
+
+func TestClient_Percolate(t *testing.T) {
+	const companyID = "01234567-89ab-cdef-0123-456789abcdef"
+	es := &fakeES{response: `{"hits": {"total": {"value": 1}, "hits": [
+		{"_id": "alert-1", "_source": {"name": "new orders"}, "fields": {"_percolator_document_slot": [0, 2]}}
+	]}}`}
+	client := newTestClient(t, es)
+
+	resp, err := client.Percolate(context.Background(), &PercolateRequest{
+		Index:     "order_alerts",
+		CompanyID: companyID,
+		Documents: []any{map[string]any{"status": "new"}, map[string]any{"status": "paid"}, map[string]any{"status": "new"}},
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"query": {"bool": {"filter": [
+		{"percolate": {"field": "query", "documents": [{"status": "new"}, {"status": "paid"}, {"status": "new"}]}},
+		{"term": {"company_id.keyword": "`+companyID+`"}}
+	]}}}`, es.bodies[0])
+	require.Len(t, resp.Matches, 1)
+	assert.Equal(t, "alert-1", resp.Matches[0].ID)
+	assert.Equal(t, []int{0, 2}, resp.Matches[0].Slots)
+}
+
+func TestClient_ExactTotal(t *testing.T) {
+	const companyID = "01234567-89ab-cdef-0123-456789abcdef"
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"hits": {"total": {"value": 10000, "relation": "gte"}, "hits": []}}`},
+		{body: `{"count": 12345}`},
+	}}
+	client := newTestClient(t, es)
+	req := &SearchRequest{
+		Index:     "orders",
+		CompanyID: companyID,
+		Query:     map[string]any{"query": map[string]any{"match": map[string]any{"status": "new"}}, "size": 10},
+	}
+
+	resp, err := client.Search(context.Background(), req)
+	require.NoError(t, err)
+	total, exact := resp.Total()
+	assert.Equal(t, 10000, total)
+	assert.False(t, exact)
+
+	total, err = client.ExactTotal(context.Background(), req, resp)
+	require.NoError(t, err)
+	assert.Equal(t, 12345, total)
+	assert.Equal(t, "POST /orders/_count", es.paths[1])
+	assert.JSONEq(t, `{"query": {"bool": {
+		"must": [{"match": {"status": "new"}}],
+		"filter": [{"term": {"company_id.keyword": "`+companyID+`"}}]
+	}}}`, es.bodies[1])
+
+	// Exact total needs no follow-up count
+	resp.Hits.Total.Relation = "eq"
+	total, err = client.ExactTotal(context.Background(), req, resp)
+	require.NoError(t, err)
+	assert.Equal(t, 10000, total)
+	assert.Len(t, es.paths, 2)
+}
+
+func TestClient_TerminateAfter(t *testing.T) {
+	es := &fakeES{response: `{"count": 1, "terminated_early": true}`}
+	client := newTestClient(t, es)
+	minScore := 0.5
+
+	_, err := client.Search(context.Background(), &SearchRequest{
+		Index:          "orders_01234567-89ab-cdef-0123-456789abcdef",
+		Query:          map[string]any{"query": map[string]any{"match": map[string]any{"status": "new"}}},
+		TerminateAfter: 1,
+		MinScore:       &minScore,
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"query": {"match": {"status": "new"}}, "terminate_after": 1, "min_score": 0.5}`, es.bodies[0])
+
+	count, err := client.Count(context.Background(), &CountRequest{
+		Index:          "orders_01234567-89ab-cdef-0123-456789abcdef",
+		TerminateAfter: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1", es.requests[1].URL.Query().Get("terminate_after"))
+	assert.True(t, count.TerminatedEarly)
+}
+
+func TestClient_SearchEachHit(t *testing.T) {
+	es := &fakeES{response: `{"took": 5, "hits": {"total": {"value": 3, "relation": "eq"}, "hits": [
+		{"_id": "1", "_source": {"n": 1}}, {"_id": "2", "_source": {"n": 2}}, {"_id": "3", "_source": {"n": 3}}
+	]}, "aggregations": {"sum": {"value": 6}}}`}
+	client := newTestClient(t, es)
+
+	var ids []string
+	resp, err := client.SearchEachHit(context.Background(), &SearchRequest{Index: "orders", CompanyID: "c1"}, func(hit json.RawMessage) error {
+		var doc struct {
+			ID string `json:"_id"`
+		}
+		if err := json.Unmarshal(hit, &doc); err != nil {
+			return err
+		}
+		ids = append(ids, doc.ID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+	assert.Equal(t, 5, resp.Took)
+	assert.Equal(t, 3, resp.Hits.Total.Value)
+	assert.Empty(t, resp.Hits.Hits)
+	assert.Equal(t, map[string]any{"value": 6.0}, resp.Aggregations["sum"])
+	assert.Contains(t, es.bodies[0], `"company_id.keyword":"c1"`)
+
+	// Error of callback stops reading
+	stop := errors.New("stop")
+	_, err = client.SearchEachHit(context.Background(), &SearchRequest{Index: "orders", CompanyID: "c1"}, func(hit json.RawMessage) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestClient_Search_MoreLikeThis(t *testing.T) {
+	es := &fakeES{}
+	client := newTestClient(t, es)
+	minTermFreq := 1
+
+	_, err := client.Search(context.Background(), &SearchRequest{
+		Index:     "products",
+		CompanyID: "c1",
+		Query:     map[string]any{"query": map[string]any{"term": map[string]any{"status": "active"}}},
+		MoreLikeThis: &MoreLikeThis{
+			Fields:      []string{"name"},
+			LikeIDs:     []string{"p1"},
+			LikeTexts:   []string{"red case"},
+			MinTermFreq: &minTermFreq,
+		},
+	})
+	require.NoError(t, err)
+
+	// Liked document is fetched with company routing; company filter applies to results
+	assert.JSONEq(t, `{"query": {"bool": {
+		"must": [
+			{"term": {"status": "active"}},
+			{"more_like_this": {"fields": ["name"], "like": [{"_id": "p1", "routing": "c1"}, "red case"], "min_term_freq": 1}}
+		],
+		"filter": [{"term": {"company_id.keyword": "c1"}}]
+	}}}`, es.bodies[0])
+}
+
+func TestClient_Search_TenantBoosts(t *testing.T) {
+	boosts := NewTenantBoosts()
+	maxBoost := 5.0
+	boosts.Set("c1", "products", ScoreBoost{
+		Functions: []map[string]any{{"filter": map[string]any{"term": map[string]any{"in_stock": true}}, "weight": 2}},
+		BoostMode: "multiply",
+		MaxBoost:  &maxBoost,
+	})
+	boosts.Set("c1", "orders", ScoreBoost{Functions: []map[string]any{{"weight": 3}}})
+
+	es := &fakeES{}
+	client := newTestClient(t, es).With(WithTenantBoosts(boosts))
+
+	for _, companyID := range []string{"c1", "c2"} {
+		_, err := client.Search(context.Background(), &SearchRequest{
+			Index:     "products",
+			CompanyID: companyID,
+			Query:     map[string]any{"query": map[string]any{"match": map[string]any{"name": "case"}}},
+		})
+		require.NoError(t, err)
+	}
+
+	// Company filter stays inside boosted query; boosts of other index prefixes are not applied
+	assert.JSONEq(t, `{"query": {"function_score": {
+		"query": {"bool": {
+			"must": [{"match": {"name": "case"}}],
+			"filter": [{"term": {"company_id.keyword": "c1"}}]
+		}},
+		"functions": [{"filter": {"term": {"in_stock": true}}, "weight": 2}],
+		"boost_mode": "multiply",
+		"max_boost": 5
+	}}}`, es.bodies[0])
+	assert.NotContains(t, es.bodies[1], "function_score")
+}
+
+func TestPageTokens(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	tokens, err := NewPageTokens(PageTokenConfig{Secret: []byte("secret"), TTL: time.Minute, Clock: clock})
+	require.NoError(t, err)
+
+	resp := &SearchResponse{PitID: "pit-1"}
+	resp.Hits.Hits = []map[string]interface{}{
+		{"_id": "1", "sort": []interface{}{1700000000000.0, "a"}},
+		{"_id": "2", "sort": []interface{}{1700000000001.0, "b"}},
+	}
+
+	token, err := tokens.Next(resp, "c1", 2)
+	require.NoError(t, err)
+	assert.NotContains(t, token, "pit-1")
+
+	var req SearchRequest
+	require.NoError(t, tokens.Apply(token, "c1", &req))
+	assert.Equal(t, "pit-1", *req.PointInTime)
+	assert.Equal(t, []any{1700000000001.0, "b"}, req.SearchAfter)
+
+	// Bound to company and signed
+	assert.ErrorIs(t, tokens.Apply(token, "c2", &req), ErrInvalidPageToken)
+	assert.ErrorIs(t, tokens.Apply("x"+token, "c1", &req), ErrInvalidPageToken)
+
+	clock.Advance(time.Minute)
+	assert.ErrorIs(t, tokens.Apply(token, "c1", &req), ErrPageTokenExpired)
+
+	// Short page is the last one
+	token, err = tokens.Next(resp, "c1", 3)
+	require.NoError(t, err)
+	assert.Empty(t, token)
+}
+
+func TestClient_Suggest(t *testing.T) {
+	es := &fakeES{response: `{"hits": {"hits": []}, "suggest": {"autocomplete": [{"text": "mil", "options": [
+		{"text": "Milk", "_index": "products_shared", "_id": "p1", "_score": 10},
+		{"text": "milk ", "_index": "products_shared", "_id": "p2", "_score": 8},
+		{"text": "Milk 3.2%", "_index": "products_shared", "_id": "p3", "_score": 5, "_source": {"sku": "M32"}},
+		{"text": "Millet", "_index": "products_shared", "_id": "p4", "_score": 1}
+	]}]}}`}
+	client := newTestClient(t, es)
+
+	suggestions, err := client.Suggest(context.Background(), &SuggestRequest{
+		Index:     "products_shared",
+		Field:     "name_suggest",
+		Prefix:    "mil",
+		Size:      2,
+		CompanyID: "c1",
+		Contexts:  map[string][]string{"company_id": {"c2"}, "category": {"dairy"}},
+		Fuzzy:     &SuggestFuzzy{},
+		Source:    []string{"sku"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Suggestion{
+		{Text: "Milk", ID: "p1", Index: "products_shared", Score: 10},
+		{Text: "Milk 3.2%", ID: "p3", Index: "products_shared", Score: 5, Source: json.RawMessage(`{"sku":"M32"}`)},
+	}, suggestions)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal([]byte(es.bodies[0]), &body))
+	assert.Equal(t, map[string]any{
+		"field":           "name_suggest",
+		"size":            float64(4),
+		"skip_duplicates": true,
+		"fuzzy":           map[string]any{"fuzziness": "AUTO"},
+		"contexts":        map[string]any{"company_id": []any{"c1"}, "category": []any{"dairy"}},
+	}, body["suggest"].(map[string]any)["autocomplete"].(map[string]any)["completion"])
+	assert.Equal(t, "c1", es.requests[0].URL.Query().Get("routing"))
+
+	suggestions, err = client.Suggest(context.Background(), &SuggestRequest{
+		Index: "products_shared", Field: "name_suggest", Prefix: "mil", CompanyID: "c1", KeepDuplicates: true,
+	})
+	require.NoError(t, err)
+	assert.Len(t, suggestions, 4)
+}
@@ -0,0 +1,239 @@
+package esclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	sigV4Algorithm      = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat     = "20060102T150405Z"
+	sigV4DateFormat     = "20060102"
+	defaultSigV4Service = "es"
+)
+
+// AWSCredentials are AWS access key credentials of SigV4 signing.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Temporary credentials only
+}
+
+// SigV4Config configures AWS SigV4 request signing of cluster
+// (Amazon OpenSearch Service domain or OpenSearch Serverless collection).
+type SigV4Config struct {
+	Region  string // AWS region (e.g., "eu-central-1")
+	Service string // "es" for OpenSearch Service domains, "aoss" for Serverless (default: "es")
+
+	// Static credentials; if empty, Credentials or AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+	// and AWS_SESSION_TOKEN environment variables are used.
+	Credentials AWSCredentials
+
+	// CredentialsFunc returns credentials for every request, for rotating credentials
+	// (e.g., assumed role), optional. It should cache credentials until they expire.
+	CredentialsFunc func(ctx context.Context) (AWSCredentials, error)
+}
+
+// credentials returns credentials of request.
+func (c *SigV4Config) credentials(ctx context.Context) (AWSCredentials, error) {
+	if c.CredentialsFunc != nil {
+		return c.CredentialsFunc(ctx)
+	}
+	if c.Credentials.AccessKeyID != "" {
+		return c.Credentials, nil
+	}
+	creds := AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return AWSCredentials{}, errors.New("AWS credentials are not configured")
+	}
+	return creds, nil
+}
+
+// sigV4Transport signs every request with AWS SigV4 before delegating to next transport.
+type sigV4Transport struct {
+	next  http.RoundTripper
+	cfg   SigV4Config
+	clock Clock
+}
+
+// newSigV4Transport wraps transport with SigV4 signing; nil transport means http.DefaultTransport.
+func newSigV4Transport(next http.RoundTripper, cfg SigV4Config) (*sigV4Transport, error) {
+	if cfg.Region == "" {
+		return nil, errors.New("SigV4 region is required")
+	}
+	if cfg.Service == "" {
+		cfg.Service = defaultSigV4Service
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &sigV4Transport{next: next, cfg: cfg, clock: systemClock{}}, nil
+}
+
+// RoundTrip signs copy of request and sends it.
+func (t *sigV4Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	creds, err := t.cfg.credentials(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := req.Clone(req.Context())
+	if body != nil {
+		signed.Body = io.NopCloser(bytes.NewReader(body))
+		signed.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	payloadHash := sha256Hex(body)
+	// Required by OpenSearch Serverless, accepted by OpenSearch Service
+	signed.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signSigV4(signed, payloadHash, creds, t.cfg.Region, t.cfg.Service, t.clock.Now())
+
+	return t.next.RoundTrip(signed)
+}
+
+// readRequestBody returns request body without consuming it for caller.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read request body for signing")
+		}
+		defer body.Close() //nolint:errcheck
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request body for signing")
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// signSigV4 sets X-Amz-Date, X-Amz-Security-Token and Authorization headers of request.
+// Signed headers are host, x-amz-date and x-amz-* headers set on request.
+func signSigV4(req *http.Request, payloadHash string, creds AWSCredentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(sigV4TimeFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		sigV4Escape(path, false),
+		sigV4Query(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(sigV4DateFormat), region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{sigV4Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), now.Format(sigV4DateFormat))
+	for _, part := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", sigV4Algorithm+" Credential="+creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// sigV4Query returns canonical query string: keys and values escaped and sorted.
+func sigV4Query(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, sigV4Escape(key, true)+"="+sigV4Escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// sigV4Escape percent-encodes everything except unreserved characters (and slash in paths).
+func sigV4Escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~', ch == '/' && !encodeSlash:
+			b.WriteByte(ch)
+		default:
+			b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{ch})))
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,102 @@
+package esclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSlowQueryThreshold = time.Second
+	defaultSlowQueryLogSize   = 100
+)
+
+// SlowQuery is request slower than threshold of SlowQueryLog.
+type SlowQuery struct {
+	At         time.Time     `json:"at"`
+	Method     string        `json:"method"`
+	Path       string        `json:"path"`
+	StatusCode int           `json:"status_code"`
+	Duration   time.Duration `json:"duration"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// SlowQueryLogConfig configures SlowQueryLog.
+type SlowQueryLogConfig struct {
+	Threshold time.Duration // Min duration of recorded request (default: 1s)
+	Size      int           // Number of most recent slow requests kept (default: 100)
+	Next      Metrics       // Metrics receiving every request after recording, optional
+	Clock     Clock         // Time source of recording time (default: system clock)
+}
+
+// SlowQueryLog is Metrics keeping most recent slow requests in memory for DebugSnapshot.
+// Pass it to WithMetrics; existing Metrics can be chained as Next.
+type SlowQueryLog struct {
+	cfg     SlowQueryLogConfig
+	mu      sync.Mutex
+	entries []SlowQuery // ring buffer
+	next    int         // position of next entry in ring buffer
+}
+
+// NewSlowQueryLog creates a new slow query log.
+func NewSlowQueryLog(cfg SlowQueryLogConfig) *SlowQueryLog {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = defaultSlowQueryThreshold
+	}
+	if cfg.Size <= 0 {
+		cfg.Size = defaultSlowQueryLogSize
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = systemClock{}
+	}
+	return &SlowQueryLog{cfg: cfg, entries: make([]SlowQuery, 0, cfg.Size)}
+}
+
+// ObserveRequest records request if it is slower than threshold.
+func (l *SlowQueryLog) ObserveRequest(ctx context.Context, method, path string, statusCode int, duration time.Duration, err error) {
+	if duration >= l.cfg.Threshold {
+		entry := SlowQuery{
+			At:         l.cfg.Clock.Now(),
+			Method:     method,
+			Path:       path,
+			StatusCode: statusCode,
+			Duration:   duration,
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+
+		l.mu.Lock()
+		if len(l.entries) < l.cfg.Size {
+			l.entries = append(l.entries, entry)
+		} else {
+			l.entries[l.next] = entry
+		}
+		l.next = (l.next + 1) % l.cfg.Size
+		l.mu.Unlock()
+	}
+
+	if l.cfg.Next != nil {
+		l.cfg.Next.ObserveRequest(ctx, method, path, statusCode, duration, err)
+	}
+}
+
+// Recent returns recorded slow requests, oldest first.
+func (l *SlowQueryLog) Recent() []SlowQuery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := make([]SlowQuery, 0, len(l.entries))
+	if len(l.entries) < l.cfg.Size {
+		return append(result, l.entries...)
+	}
+	result = append(result, l.entries[l.next:]...)
+	return append(result, l.entries[:l.next]...)
+}
+
+// ObserveDeadlineUsage forwards deadline usage to Next if it implements DeadlineMetrics.
+func (l *SlowQueryLog) ObserveDeadlineUsage(ctx context.Context, method, path string, used float64) {
+	if next, ok := l.cfg.Next.(DeadlineMetrics); ok {
+		next.ObserveDeadlineUsage(ctx, method, path, used)
+	}
+}
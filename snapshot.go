@@ -0,0 +1,249 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotRepository represents snapshot repository registration.
+type SnapshotRepository struct {
+	Name     string         // Repository name
+	Type     string         // Repository type (e.g., "fs", "s3", "gcs")
+	Settings map[string]any // Type-specific settings (e.g., "location", "bucket")
+	Verify   *bool          // Verify repository on all nodes, default: true
+}
+
+// CreateSnapshotRequest represents create snapshot request.
+type CreateSnapshotRequest struct {
+	Repository         string         // Repository name
+	Snapshot           string         // Snapshot name
+	Indices            []string       // Indices to snapshot, all indices if empty
+	IgnoreUnavailable  bool           // Ignore missing or closed indices
+	IncludeGlobalState bool           // Include cluster state (templates, pipelines, etc.)
+	Metadata           map[string]any // Arbitrary metadata stored with snapshot
+	WaitForCompletion  bool           // Block until snapshot is finished
+}
+
+// RestoreSnapshotRequest represents restore snapshot request.
+type RestoreSnapshotRequest struct {
+	Repository         string         // Repository name
+	Snapshot           string         // Snapshot name
+	Indices            []string       // Indices to restore, all snapshot indices if empty
+	IgnoreUnavailable  bool           // Ignore indices missing in snapshot
+	RenamePattern      string         // Regexp applied to restored index names
+	RenameReplacement  string         // Replacement of RenamePattern (e.g., "restored_$1")
+	IndexSettings      map[string]any // Settings overridden on restored indices
+	IncludeAliases     *bool          // Restore aliases, default: true
+	IncludeGlobalState bool           // Restore cluster state
+	WaitForCompletion  bool           // Block until restore is finished
+}
+
+// SnapshotInfo represents snapshot state.
+type SnapshotInfo struct {
+	Snapshot  string         `json:"snapshot"`
+	UUID      string         `json:"uuid"`
+	State     string         `json:"state"` // IN_PROGRESS, SUCCESS, FAILED, PARTIAL
+	Indices   []string       `json:"indices"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	StartTime string         `json:"start_time,omitempty"`
+	EndTime   string         `json:"end_time,omitempty"`
+	Shards    struct {
+		Total      int `json:"total"`
+		Successful int `json:"successful"`
+		Failed     int `json:"failed"`
+	} `json:"shards"`
+	Failures []map[string]any `json:"failures,omitempty"`
+}
+
+// Snapshot states.
+const (
+	SnapshotStateInProgress = "IN_PROGRESS"
+	SnapshotStateSuccess    = "SUCCESS"
+	SnapshotStateFailed     = "FAILED"
+	SnapshotStatePartial    = "PARTIAL"
+)
+
+// PutSnapshotRepository registers or updates snapshot repository.
+func (c *Client) PutSnapshotRepository(ctx context.Context, repo *SnapshotRepository) error {
+	if repo.Name == "" {
+		return errors.New("repository name is required")
+	}
+	if repo.Type == "" {
+		return errors.New("repository type is required")
+	}
+
+	query := url.Values{}
+	if repo.Verify != nil && !*repo.Verify {
+		query.Set("verify", "false")
+	}
+
+	body := map[string]any{
+		"type":     repo.Type,
+		"settings": repo.Settings,
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("/_snapshot/%s", repo.Name), query, body, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "put_snapshot_repository", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// CreateSnapshot starts snapshot of indices.
+// Without WaitForCompletion snapshot state must be polled with GetSnapshot.
+func (c *Client) CreateSnapshot(ctx context.Context, req *CreateSnapshotRequest) (*SnapshotInfo, error) {
+	if err := validateSnapshotName(req.Repository, req.Snapshot); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if req.WaitForCompletion {
+		query.Set("wait_for_completion", "true")
+	}
+
+	body := map[string]any{
+		"include_global_state": req.IncludeGlobalState,
+	}
+	if len(req.Indices) > 0 {
+		body["indices"] = strings.Join(req.Indices, ",")
+	}
+	if req.IgnoreUnavailable {
+		body["ignore_unavailable"] = true
+	}
+	if len(req.Metadata) > 0 {
+		body["metadata"] = req.Metadata
+	}
+
+	var resp struct {
+		Snapshot *SnapshotInfo `json:"snapshot"`
+	}
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPut, snapshotPath(req.Repository, req.Snapshot), query, body, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "create_snapshot", StatusCode: status, body: errBody}
+	}
+
+	if resp.Snapshot == nil {
+		// Snapshot is accepted and runs in background
+		return &SnapshotInfo{Snapshot: req.Snapshot, State: SnapshotStateInProgress}, nil
+	}
+	return resp.Snapshot, nil
+}
+
+// GetSnapshot returns snapshot state.
+func (c *Client) GetSnapshot(ctx context.Context, repository, snapshot string) (*SnapshotInfo, error) {
+	if err := validateSnapshotName(repository, snapshot); err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Snapshots []SnapshotInfo `json:"snapshots"`
+	}
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, snapshotPath(repository, snapshot), nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "get_snapshot", StatusCode: status, body: errBody}
+	}
+
+	if len(resp.Snapshots) == 0 {
+		return nil, &StatusError{Op: "get_snapshot", StatusCode: http.StatusNotFound}
+	}
+	return &resp.Snapshots[0], nil
+}
+
+// RestoreSnapshot restores indices from snapshot.
+// Restored indices must not exist or be closed, use RenamePattern to restore side by side.
+func (c *Client) RestoreSnapshot(ctx context.Context, req *RestoreSnapshotRequest) error {
+	if err := validateSnapshotName(req.Repository, req.Snapshot); err != nil {
+		return err
+	}
+	if (req.RenamePattern == "") != (req.RenameReplacement == "") {
+		return errors.New("rename_pattern and rename_replacement must be set together")
+	}
+
+	query := url.Values{}
+	if req.WaitForCompletion {
+		query.Set("wait_for_completion", "true")
+	}
+
+	body := map[string]any{
+		"include_global_state": req.IncludeGlobalState,
+	}
+	if len(req.Indices) > 0 {
+		body["indices"] = strings.Join(req.Indices, ",")
+	}
+	if req.IgnoreUnavailable {
+		body["ignore_unavailable"] = true
+	}
+	if req.RenamePattern != "" {
+		body["rename_pattern"] = req.RenamePattern
+		body["rename_replacement"] = req.RenameReplacement
+	}
+	if len(req.IndexSettings) > 0 {
+		body["index_settings"] = req.IndexSettings
+	}
+	if req.IncludeAliases != nil {
+		body["include_aliases"] = *req.IncludeAliases
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, snapshotPath(req.Repository, req.Snapshot)+"/_restore", query, body, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK && status != http.StatusAccepted {
+		return &StatusError{Op: "restore_snapshot", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// DeleteSnapshot deletes snapshot from repository.
+func (c *Client) DeleteSnapshot(ctx context.Context, repository, snapshot string) error {
+	if err := validateSnapshotName(repository, snapshot); err != nil {
+		return err
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodDelete, snapshotPath(repository, snapshot), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "delete_snapshot", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// validateSnapshotName checks that repository and snapshot names are set.
+func validateSnapshotName(repository, snapshot string) error {
+	if repository == "" {
+		return errors.New("repository name is required")
+	}
+	if snapshot == "" {
+		return errors.New("snapshot name is required")
+	}
+	return nil
+}
+
+// snapshotPath returns snapshot endpoint path.
+func snapshotPath(repository, snapshot string) string {
+	return fmt.Sprintf("/_snapshot/%s/%s", repository, snapshot)
+}
@@ -0,0 +1,116 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// SQLOptions configures SQL query.
+type SQLOptions struct {
+	// CompanyID limits rows to company documents via company_id filter.
+	// SQL bypasses index target detection, so set it for queries over shared indices.
+	CompanyID string
+	Filter    map[string]any // Query DSL filter applied before SQL query, optional
+	FetchSize int            // Rows per page (ES default: 1000)
+	TimeZone  string         // Time zone of date functions (e.g., "Asia/Tashkent")
+}
+
+// SQLColumn represents column of SQL result.
+type SQLColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// SQLResponse represents page of SQL result. Columns are set on first page only.
+type SQLResponse struct {
+	Columns []SQLColumn `json:"columns,omitempty"`
+	Rows    [][]any     `json:"rows"`
+	Cursor  string      `json:"cursor,omitempty"` // Cursor of next page, empty on last page
+}
+
+// SQLQuery executes SQL query with positional parameters ("?" placeholders) via _sql API.
+// If response has Cursor, next pages are fetched with SQLNextPage; cursor not read till
+// the end must be closed with SQLCloseCursor.
+func (c *Client) SQLQuery(ctx context.Context, query string, params []any, opts *SQLOptions) (*SQLResponse, error) {
+	if query == "" {
+		return nil, errors.New("SQL query is required")
+	}
+	if opts == nil {
+		opts = &SQLOptions{}
+	}
+
+	body := map[string]any{"query": query}
+	if len(params) > 0 {
+		body["params"] = params
+	}
+	if opts.FetchSize > 0 {
+		body["fetch_size"] = opts.FetchSize
+	}
+	if opts.TimeZone != "" {
+		body["time_zone"] = opts.TimeZone
+	}
+
+	filter := deepCopyMap(opts.Filter)
+	if opts.CompanyID != "" {
+		wrapper := map[string]any{}
+		if filter != nil {
+			wrapper["query"] = filter
+		}
+		if err := NewQueryMutator().InjectCompanyFilter(wrapper, opts.CompanyID, IndexTargetShared); err != nil {
+			return nil, errors.Wrap(err, "failed to inject company filter")
+		}
+		filter = wrapper["query"].(map[string]any)
+	}
+	if filter != nil {
+		body["filter"] = filter
+	}
+
+	return c.sql(ctx, "sql_query", body)
+}
+
+// SQLNextPage fetches next page of SQL result by cursor.
+func (c *Client) SQLNextPage(ctx context.Context, cursor string) (*SQLResponse, error) {
+	if cursor == "" {
+		return nil, errors.New("cursor is required")
+	}
+	return c.sql(ctx, "sql_next_page", map[string]any{"cursor": cursor})
+}
+
+// SQLCloseCursor releases server resources of SQL cursor.
+func (c *Client) SQLCloseCursor(ctx context.Context, cursor string) error {
+	if cursor == "" {
+		return errors.New("cursor is required")
+	}
+
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, "/_sql/close", nil, map[string]any{"cursor": cursor}, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "sql_close_cursor", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// sql posts body to _sql API in JSON format.
+func (c *Client) sql(ctx context.Context, op string, body map[string]any) (*SQLResponse, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+
+	var resp SQLResponse
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, "/_sql", query, body, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: op, StatusCode: status, body: errBody}
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,136 @@
+package esclient
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// companyIDField is document field used by company filter on shared indices.
+const companyIDField = "company_id"
+
+// stampDocument sets company_id field of JSON document body.
+func stampDocument(body io.Reader, companyID string) (io.Reader, error) {
+	if body == nil {
+		return nil, errors.New("document body is required for company_id stamping")
+	}
+
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read document body")
+	}
+
+	stamped, err := stampSource(raw, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(stamped), nil
+}
+
+// stampSource sets company_id field of a single JSON object, preserving number precision.
+func stampSource(raw []byte, companyID string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "failed to decode document for company_id stamping")
+	}
+	if doc == nil {
+		return nil, errors.New("document must be JSON object for company_id stamping")
+	}
+
+	doc[companyIDField] = companyID
+	return json.Marshal(doc)
+}
+
+// stampBulkBody sets company_id field of documents in NDJSON bulk body.
+// Sources of index/create actions and doc/upsert of update actions are stamped
+// when action targets a shared index (action _index or defaultIndex).
+func stampBulkBody(body io.Reader, defaultIndex, companyID string) (io.Reader, error) {
+	if body == nil {
+		return nil, errors.New("bulk body is required")
+	}
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 100<<20)
+
+	pending := "" // action whose source line is expected next
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if pending != "" {
+			stamped, err := stampBulkSource(line, pending, companyID)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(stamped)
+			out.WriteByte('\n')
+			pending = ""
+			continue
+		}
+
+		var action map[string]struct {
+			Index string `json:"_index"`
+		}
+		if err := json.Unmarshal(line, &action); err != nil {
+			return nil, errors.Wrap(err, "failed to decode bulk action")
+		}
+		for op, meta := range action {
+			if op == "delete" {
+				break
+			}
+			index := meta.Index
+			if index == "" {
+				index = defaultIndex
+			}
+			pending = "skip"
+			if DetectIndexTarget(index) == IndexTargetShared {
+				pending = op
+			}
+		}
+
+		out.Write(line)
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read bulk body")
+	}
+	if pending != "" {
+		return nil, errors.New("bulk body ends with action without source")
+	}
+
+	return &out, nil
+}
+
+// stampBulkSource stamps source line of bulk action.
+func stampBulkSource(line []byte, op, companyID string) ([]byte, error) {
+	switch op {
+	case "index", "create":
+		return stampSource(line, companyID)
+	case "update":
+		var src map[string]json.RawMessage
+		if err := json.Unmarshal(line, &src); err != nil {
+			return nil, errors.Wrap(err, "failed to decode bulk update source")
+		}
+		for _, key := range []string{"doc", "upsert"} {
+			if raw, ok := src[key]; ok {
+				stamped, err := stampSource(raw, companyID)
+				if err != nil {
+					return nil, err
+				}
+				src[key] = stamped
+			}
+		}
+		return json.Marshal(src)
+	default:
+		return line, nil
+	}
+}
@@ -0,0 +1,177 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// suggestName is name of completion suggestion in search body of Suggest.
+const suggestName = "autocomplete"
+
+// SuggestRequest represents completion suggester request, see Client.Suggest.
+type SuggestRequest struct {
+	Index  string // Index name
+	Field  string // Completion field (see CompletionMapping)
+	Prefix string // Typed prefix
+	Size   int    // Number of suggestions (default: 10)
+
+	// CompanyID is company of suggestions. Completion suggester ignores query filters, so for shared
+	// index it is required and sent as company_id context.
+	CompanyID string
+
+	Contexts       map[string][]string // Additional category contexts (e.g., {"category": {"c1"}}), optional
+	Fuzzy          *SuggestFuzzy       // Tolerate typos in prefix, optional
+	Source         []string            // Source fields of suggested documents to return, optional
+	KeepDuplicates bool                // Keep suggestions differing only in case and whitespace
+}
+
+// SuggestFuzzy configures fuzzy matching of completion suggester.
+type SuggestFuzzy struct {
+	Fuzziness    string // Allowed edits: "AUTO" (default), "0", "1" or "2"
+	MinLength    int    // Min prefix length before fuzzy matching applies (ES default: 3)
+	PrefixLength int    // Leading characters that must match exactly (ES default: 1)
+}
+
+// Suggestion is single completion suggestion.
+type Suggestion struct {
+	Text   string          // Suggested input
+	ID     string          // ID of suggested document
+	Index  string          // Index of suggested document
+	Score  float64         // Suggestion weight, boosted by contexts
+	Source json.RawMessage // Requested source fields, if any
+}
+
+// CompletionMapping returns mapping of completion field with category contexts read from
+// document fields of the same name, e.g. CompletionMapping("company_id") for shared indices.
+// Documents set field as {"input": ["Milk 3.2%"], "weight": 10}.
+func CompletionMapping(contexts ...string) map[string]any {
+	mapping := map[string]any{"type": "completion"}
+	if len(contexts) > 0 {
+		defs := make([]any, 0, len(contexts))
+		for _, name := range contexts {
+			defs = append(defs, map[string]any{"name": name, "type": "category", "path": name})
+		}
+		mapping["contexts"] = defs
+	}
+	return mapping
+}
+
+// Suggest returns completion suggestions for typed prefix, for storefront search boxes.
+// Suggestions with the same text up to case and whitespace are collapsed to the best scored one
+// unless KeepDuplicates is set; more suggestions are requested to fill Size after that.
+func (c *Client) Suggest(ctx context.Context, req *SuggestRequest) ([]Suggestion, error) {
+	if req.Field == "" {
+		return nil, errors.New("completion field is required")
+	}
+	if req.Prefix == "" {
+		return nil, nil
+	}
+	size := req.Size
+	if size <= 0 {
+		size = 10
+	}
+
+	completion := map[string]any{
+		"field":           req.Field,
+		"size":            size,
+		"skip_duplicates": true,
+	}
+	if !req.KeepDuplicates {
+		// skip_duplicates compares exact text only
+		completion["size"] = size * 2
+	}
+	if req.Fuzzy != nil {
+		fuzzy := map[string]any{"fuzziness": "AUTO"}
+		if req.Fuzzy.Fuzziness != "" {
+			fuzzy["fuzziness"] = req.Fuzzy.Fuzziness
+		}
+		if req.Fuzzy.MinLength > 0 {
+			fuzzy["min_length"] = req.Fuzzy.MinLength
+		}
+		if req.Fuzzy.PrefixLength > 0 {
+			fuzzy["prefix_length"] = req.Fuzzy.PrefixLength
+		}
+		completion["fuzzy"] = fuzzy
+	}
+
+	contexts := make(map[string]any, len(req.Contexts)+1)
+	for name, values := range req.Contexts {
+		contexts[name] = values
+	}
+	if DetectIndexTarget(req.Index) == IndexTargetShared && req.CompanyID != "" {
+		// Overrides caller context, so other companies' suggestions cannot be requested
+		contexts[companyIDField] = []string{req.CompanyID}
+	}
+	if len(contexts) > 0 {
+		completion["contexts"] = contexts
+	}
+
+	var source any = false
+	if len(req.Source) > 0 {
+		source = req.Source
+	}
+	noHits := 0
+	resp, err := c.Search(ctx, &SearchRequest{
+		Index: req.Index,
+		Query: map[string]any{
+			"_source": source,
+			"suggest": map[string]any{
+				suggestName: map[string]any{"prefix": req.Prefix, "completion": completion},
+			},
+		},
+		CompanyID: req.CompanyID,
+		Size:      &noHits,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSuggestions(resp.Suggest[suggestName], size, !req.KeepDuplicates)
+}
+
+// parseSuggestions returns at most size options of completion suggestion, collapsing
+// options with the same normalized text if dedup is set.
+func parseSuggestions(raw interface{}, size int, dedup bool) ([]Suggestion, error) {
+	entries, _ := raw.([]interface{})
+	seen := make(map[string]bool)
+	var result []Suggestion
+	for _, entry := range entries {
+		entryMap, _ := entry.(map[string]interface{})
+		options, _ := entryMap["options"].([]interface{})
+		for _, option := range options {
+			if len(result) == size {
+				return result, nil
+			}
+			opt, _ := option.(map[string]interface{})
+			s := Suggestion{}
+			s.Text, _ = opt["text"].(string)
+			s.ID, _ = opt["_id"].(string)
+			s.Index, _ = opt["_index"].(string)
+			s.Score, _ = opt["_score"].(float64)
+			if dedup {
+				key := suggestionKey(s.Text)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			if src, ok := opt["_source"]; ok {
+				encoded, err := json.Marshal(src)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to encode suggestion source")
+				}
+				s.Source = encoded
+			}
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// suggestionKey normalizes suggestion text for deduplication, ignoring case and whitespace.
+func suggestionKey(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
@@ -0,0 +1,163 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TaskInfo represents a running or completed Elasticsearch task.
+type TaskInfo struct {
+	Node               string                 `json:"node"`
+	ID                 int64                  `json:"id"`
+	Type               string                 `json:"type"`
+	Action             string                 `json:"action"` // e.g., "indices:data/write/update/byquery"
+	Description        string                 `json:"description"`
+	StartTimeInMillis  int64                  `json:"start_time_in_millis"`
+	RunningTimeInNanos int64                  `json:"running_time_in_nanos"`
+	Cancellable        bool                   `json:"cancellable"`
+	Cancelled          bool                   `json:"cancelled"`
+	ParentTaskID       string                 `json:"parent_task_id,omitempty"`
+	Status             map[string]interface{} `json:"status,omitempty"` // Progress (total, updated, deleted, ...)
+}
+
+// TaskID returns task identifier in "node:id" format.
+func (t *TaskInfo) TaskID() string {
+	return fmt.Sprintf("%s:%d", t.Node, t.ID)
+}
+
+// GetTaskResponse represents get task response.
+type GetTaskResponse struct {
+	Completed bool                   `json:"completed"`
+	Task      TaskInfo               `json:"task"`
+	Response  map[string]interface{} `json:"response,omitempty"` // Task result if completed
+	Error     map[string]interface{} `json:"error,omitempty"`    // Task error if failed
+}
+
+// ListTasksRequest represents list tasks request.
+type ListTasksRequest struct {
+	Actions      []string // Action patterns (e.g., "*byquery", "*reindex"), optional
+	Nodes        []string // Node IDs, optional
+	ParentTaskID string   // Parent task ID in "node:id" format, optional
+	Detailed     bool     // Include detailed status and description
+}
+
+// GetTask returns task status by ID in "node:id" format.
+func (c *Client) GetTask(ctx context.Context, taskID string) (*GetTaskResponse, error) {
+	if taskID == "" {
+		return nil, errors.New("task ID is required")
+	}
+
+	var resp GetTaskResponse
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("/_tasks/%s", taskID), nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "get_task", StatusCode: status, body: errBody}
+	}
+
+	return &resp, nil
+}
+
+// ListTasks returns tasks currently running on the cluster.
+func (c *Client) ListTasks(ctx context.Context, req *ListTasksRequest) ([]TaskInfo, error) {
+	query := url.Values{}
+	query.Set("group_by", "none")
+	if req != nil {
+		if len(req.Actions) > 0 {
+			query.Set("actions", strings.Join(req.Actions, ","))
+		}
+		if len(req.Nodes) > 0 {
+			query.Set("nodes", strings.Join(req.Nodes, ","))
+		}
+		if req.ParentTaskID != "" {
+			query.Set("parent_task_id", req.ParentTaskID)
+		}
+		if req.Detailed {
+			query.Set("detailed", "true")
+		}
+	}
+
+	var resp struct {
+		Tasks []TaskInfo `json:"tasks"`
+	}
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, "/_tasks", query, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "list_tasks", StatusCode: status, body: errBody}
+	}
+
+	return resp.Tasks, nil
+}
+
+// CancelTask cancels task by ID in "node:id" format.
+// Only cancellable tasks (e.g., update_by_query, reindex) can be cancelled.
+func (c *Client) CancelTask(ctx context.Context, taskID string) error {
+	if taskID == "" {
+		return errors.New("task ID is required")
+	}
+
+	var resp struct {
+		NodeFailures []map[string]interface{} `json:"node_failures"`
+	}
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, fmt.Sprintf("/_tasks/%s/_cancel", taskID), nil, nil, &resp)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "cancel_task", StatusCode: status, body: errBody}
+	}
+
+	if len(resp.NodeFailures) > 0 {
+		return errors.Errorf("failed to cancel task %s: %v", taskID, resp.NodeFailures[0]["reason"])
+	}
+
+	return nil
+}
+
+// waitTask polls task until it completes and decodes its response into out.
+// onPoll, if set, is called with every polled task status.
+func (c *Client) waitTask(ctx context.Context, taskID string, interval time.Duration, onPoll func(*GetTaskResponse), out any) error {
+	for {
+		task, err := c.GetTask(ctx, taskID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get task %s", taskID)
+		}
+		if onPoll != nil {
+			onPoll(task)
+		}
+
+		if task.Completed {
+			if task.Error != nil {
+				return errors.Errorf("task %s failed: %v", taskID, task.Error["reason"])
+			}
+
+			raw, err := json.Marshal(task.Response)
+			if err != nil {
+				return errors.Wrap(err, "failed to encode task response")
+			}
+			if err := json.Unmarshal(raw, out); err != nil {
+				return errors.Wrap(err, "failed to decode task response")
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "task %s still running", taskID)
+		case <-c.clock.After(interval):
+		}
+	}
+}
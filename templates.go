@@ -0,0 +1,143 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// IndexBody represents settings, mappings and aliases of index.
+type IndexBody struct {
+	Settings map[string]any `json:"settings,omitempty"`
+	Mappings map[string]any `json:"mappings,omitempty"`
+	Aliases  map[string]any `json:"aliases,omitempty"`
+}
+
+// TemplateRegistry stores logical index definitions with per-version body variants,
+// since the same logical mapping may need different bodies for v8 and v9 clusters.
+// Safe for concurrent use.
+type TemplateRegistry struct {
+	mu        sync.RWMutex
+	templates map[string]map[int]IndexBody // template name -> ES version (0 = any) -> body
+}
+
+// NewTemplateRegistry creates empty template registry.
+func NewTemplateRegistry() *TemplateRegistry {
+	return &TemplateRegistry{
+		templates: make(map[string]map[int]IndexBody),
+	}
+}
+
+// Register registers body variant of template for ES major version.
+// Version 0 registers default variant used for versions without own variant.
+func (t *TemplateRegistry) Register(name string, version int, body IndexBody) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.templates[name] == nil {
+		t.templates[name] = make(map[int]IndexBody)
+	}
+	t.templates[name][version] = body
+}
+
+// Body returns template body variant for ES major version, falling back to default variant.
+func (t *TemplateRegistry) Body(name string, version int) (IndexBody, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	variants, ok := t.templates[name]
+	if !ok {
+		return IndexBody{}, errors.Errorf("index template %q not found", name)
+	}
+	if body, ok := variants[version]; ok {
+		return body, nil
+	}
+	if body, ok := variants[0]; ok {
+		return body, nil
+	}
+	return IndexBody{}, errors.Errorf("index template %q has no variant for ES version %d", name, version)
+}
+
+// WithTemplates sets template registry used by CreateIndex and EnsureIndex for requests with Template.
+func WithTemplates(templates *TemplateRegistry) ClientOption {
+	return func(c *Client) {
+		c.templates = templates
+	}
+}
+
+// withVersion sets ES major version of cluster, used to pick template variants.
+func withVersion(version int) ClientOption {
+	return func(c *Client) {
+		c.version = version
+	}
+}
+
+// Version returns ES major version of cluster if client was created by Registry or Resolver, otherwise 0.
+func (c *Client) Version() int {
+	return c.version
+}
+
+// EnsureIndex creates index if it does not exist. Returns true if index was created.
+// Concurrent creation by another process is not an error.
+func (c *Client) EnsureIndex(ctx context.Context, req *CreateIndexRequest) (bool, error) {
+	exists, err := c.IndexExists(ctx, req.Index)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	err = c.CreateIndex(ctx, req)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusBadRequest {
+		// resource_already_exists_exception if index was created concurrently
+		if exists, existsErr := c.IndexExists(ctx, req.Index); existsErr == nil && exists {
+			return false, nil
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// indexBody builds create index body from template variant matching client version,
+// overridden by explicit settings, mappings and aliases of request.
+func (c *Client) indexBody(req *CreateIndexRequest) (*CreateIndexRequest, error) {
+	if req.Template == "" {
+		return req, nil
+	}
+	if c.templates == nil {
+		return nil, errors.Errorf("index template %q requested but client has no template registry", req.Template)
+	}
+
+	body, err := c.templates.Body(req.Template, c.version)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := *req
+	merged.Settings = mergeTopLevel(body.Settings, req.Settings)
+	merged.Mappings = mergeTopLevel(body.Mappings, req.Mappings)
+	merged.Aliases = mergeTopLevel(body.Aliases, req.Aliases)
+	return &merged, nil
+}
+
+// mergeTopLevel returns copy of base with top-level keys of override applied.
+func mergeTopLevel(base, override map[string]any) map[string]any {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	result := deepCopyMap(base)
+	if result == nil {
+		result = make(map[string]any, len(override))
+	}
+	for k, v := range override {
+		result[k] = deepCopyValue(v)
+	}
+	return result
+}
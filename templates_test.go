@@ -0,0 +1,64 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_EnsureIndex_Template(t *testing.T) {
+	templates := NewTemplateRegistry()
+	templates.Register("orders", 0, IndexBody{
+		Mappings: map[string]any{"properties": map[string]any{"name": map[string]any{"type": "text"}}},
+	})
+	templates.Register("orders", 9, IndexBody{
+		Settings: map[string]any{"index.mapping.source.mode": "synthetic"},
+		Mappings: map[string]any{"properties": map[string]any{"name": map[string]any{"type": "match_only_text"}}},
+	})
+
+	tests := []struct {
+		name    string
+		version int
+		want    string
+	}{
+		{"v9_variant", 9, `{
+			"settings": {"index.mapping.source.mode": "synthetic", "index.number_of_shards": 2},
+			"mappings": {"properties": {"name": {"type": "match_only_text"}}}
+		}`},
+		{"default_variant", 8, `{
+			"settings": {"index.number_of_shards": 2},
+			"mappings": {"properties": {"name": {"type": "text"}}}
+		}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &scriptedES{responses: []scriptedResponse{{status: http.StatusNotFound}}}
+			client, err := NewClient(es, "http://localhost:9200", WithTemplates(templates), withVersion(tt.version))
+			require.NoError(t, err)
+
+			created, err := client.EnsureIndex(context.Background(), &CreateIndexRequest{
+				Index:    "orders_c1",
+				Template: "orders",
+				Settings: map[string]any{"index.number_of_shards": 2},
+			})
+			require.NoError(t, err)
+			assert.True(t, created)
+			assert.Equal(t, []string{"HEAD /orders_c1", "PUT /orders_c1"}, es.paths)
+			assert.JSONEq(t, tt.want, es.bodies[1])
+		})
+	}
+}
+
+func TestClient_EnsureIndex_Exists(t *testing.T) {
+	es := &fakeES{}
+	client := newTestClient(t, es)
+
+	created, err := client.EnsureIndex(context.Background(), &CreateIndexRequest{Index: "orders_c1"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Len(t, es.requests, 1)
+}
@@ -0,0 +1,135 @@
+package esclient
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTopHitsCompanies = 100
+	defaultTopHitsPerBucket = 3
+)
+
+// CrossTenantAccess authorizes search on shared index without company filter.
+// Every use is logged at warn level with actor and reason for audit.
+// Only clients created with WithCrossTenantAccess accept it.
+type CrossTenantAccess struct {
+	Actor  string // Who bypasses tenant filter (e.g., admin user ID or service name)
+	Reason string // Why tenant filter is bypassed (e.g., ticket or report name)
+}
+
+// validate returns error if access is not attributed.
+func (a *CrossTenantAccess) validate() error {
+	if strings.TrimSpace(a.Actor) == "" || strings.TrimSpace(a.Reason) == "" {
+		return errors.New("cross-tenant access requires actor and reason")
+	}
+	return nil
+}
+
+// TopHitsPerCompanyRequest represents top hits per company request.
+type TopHitsPerCompanyRequest struct {
+	Index     string            // Shared index name or pattern
+	Query     map[string]any    // Query body (JSON), optional
+	Companies int               // Max number of companies, by doc count (default: 100)
+	Size      int               // Top hits per company (default: 3)
+	Sort      []SortClause      // Sort of top hits (default: by score)
+	Source    []string          // Source fields of top hits, optional
+	Access    CrossTenantAccess // Audited tenant filter bypass, required
+}
+
+// CompanyTopHits represents top hits of a single company.
+type CompanyTopHits struct {
+	CompanyID string                   // Company ID
+	DocCount  int64                    // Number of matched documents of company
+	Hits      []map[string]interface{} // Top hits
+}
+
+// TopHitsPerCompany runs terms aggregation on company_id with top_hits per bucket across
+// shared index. It bypasses tenant filter and is intended for internal analytics only:
+// client must be created with WithCrossTenantAccess, otherwise ErrCrossTenantNotAllowed is returned.
+func (c *Client) TopHitsPerCompany(ctx context.Context, req *TopHitsPerCompanyRequest) ([]CompanyTopHits, error) {
+	if req.Index == "" {
+		return nil, errors.New("index name is required")
+	}
+
+	companies := req.Companies
+	if companies <= 0 {
+		companies = defaultTopHitsCompanies
+	}
+	size := req.Size
+	if size <= 0 {
+		size = defaultTopHitsPerBucket
+	}
+
+	topHits := map[string]any{"size": size}
+	if len(req.Sort) > 0 {
+		sort := make([]any, 0, len(req.Sort))
+		for _, clause := range req.Sort {
+			sort = append(sort, clause.body())
+		}
+		topHits["sort"] = sort
+	}
+	if len(req.Source) > 0 {
+		topHits["_source"] = req.Source
+	}
+
+	query := deepCopyMap(req.Query)
+	if query == nil {
+		query = make(map[string]any)
+	}
+	query["aggs"] = map[string]any{
+		"by_company": map[string]any{
+			"terms": map[string]any{
+				"field": "company_id.keyword",
+				"size":  companies,
+			},
+			"aggs": map[string]any{
+				"top": map[string]any{"top_hits": topHits},
+			},
+		},
+	}
+
+	zero := 0
+	access := req.Access
+	resp, err := c.Search(ctx, &SearchRequest{
+		Index:       req.Index,
+		Query:       query,
+		Size:        &zero,
+		crossTenant: &access,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byCompany, _ := resp.Aggregations["by_company"].(map[string]interface{})
+	buckets, _ := byCompany["buckets"].([]interface{})
+
+	result := make([]CompanyTopHits, 0, len(buckets))
+	for _, b := range buckets {
+		bucket, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		item := CompanyTopHits{}
+		item.CompanyID, _ = bucket["key"].(string)
+		if docCount, ok := bucket["doc_count"].(float64); ok {
+			item.DocCount = int64(docCount)
+		}
+		if top, ok := bucket["top"].(map[string]interface{}); ok {
+			if hits, ok := top["hits"].(map[string]interface{}); ok {
+				rawHits, _ := hits["hits"].([]interface{})
+				for _, h := range rawHits {
+					if hit, ok := h.(map[string]interface{}); ok {
+						item.Hits = append(item.Hits, hit)
+					}
+				}
+			}
+		}
+		result = append(result, item)
+	}
+
+	return result, nil
+}
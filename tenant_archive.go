@@ -0,0 +1,172 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// archiveMetaKey is key of archive record in index mappings _meta.
+const archiveMetaKey = "esclient_archive"
+
+// ArchiveCompanyIndexRequest represents archive of per-company index.
+type ArchiveCompanyIndexRequest struct {
+	Index              string // Per-company index name
+	SnapshotRepository string // Snapshot repository; index is snapshotted before archive if set
+	Snapshot           string // Snapshot name (default: "<index>-archive-<unix time>")
+}
+
+// ArchivedIndex is archive record stored in index mappings _meta, used to restore index.
+type ArchivedIndex struct {
+	Index      string                `json:"-"`
+	Aliases    map[string]IndexAlias `json:"aliases"`              // Detached aliases with their properties
+	Repository string                `json:"repository,omitempty"` // Snapshot repository, if snapshotted
+	Snapshot   string                `json:"snapshot,omitempty"`   // Snapshot name, if snapshotted
+	ArchivedAt time.Time             `json:"archived_at"`
+}
+
+// ArchiveCompanyIndex suspends tenant without deleting data: optionally snapshots index,
+// records and detaches its aliases, and blocks writes. Reversed by RestoreCompanyIndex.
+func (c *Client) ArchiveCompanyIndex(ctx context.Context, req *ArchiveCompanyIndexRequest) (*ArchivedIndex, error) {
+	if req.Index == "" {
+		return nil, errors.New("index name is required")
+	}
+
+	meta, err := c.GetIndex(ctx, req.Index)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Name != req.Index {
+		return nil, errors.Errorf("%q is alias of %q, concrete index is required", req.Index, meta.Name)
+	}
+	if _, ok := indexMeta(meta)[archiveMetaKey]; ok {
+		return nil, errors.Errorf("index %q is already archived", req.Index)
+	}
+
+	archived := &ArchivedIndex{
+		Index:      req.Index,
+		Aliases:    meta.Aliases,
+		ArchivedAt: c.clock.Now().UTC(),
+	}
+
+	if req.SnapshotRepository != "" {
+		name := req.Snapshot
+		if name == "" {
+			name = fmt.Sprintf("%s-archive-%d", req.Index, archived.ArchivedAt.Unix())
+		}
+		info, err := c.CreateSnapshot(ctx, &CreateSnapshotRequest{
+			Repository:        req.SnapshotRepository,
+			Snapshot:          name,
+			Indices:           []string{req.Index},
+			WaitForCompletion: true,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to snapshot index %q", req.Index)
+		}
+		if info.State != SnapshotStateSuccess {
+			return nil, errors.Errorf("snapshot %q of index %q finished with state %s", name, req.Index, info.State)
+		}
+		archived.Repository = req.SnapshotRepository
+		archived.Snapshot = name
+	}
+
+	// Record is written first, so aliases can be restored even if later steps fail
+	if err := c.putArchiveRecord(ctx, meta, archived); err != nil {
+		return nil, err
+	}
+
+	if err := c.PutSettings(ctx, req.Index, map[string]any{"index.blocks.write": true}); err != nil {
+		return nil, errors.Wrapf(err, "failed to block writes to index %q", req.Index)
+	}
+
+	actions := make([]AliasAction, 0, len(meta.Aliases))
+	for alias := range meta.Aliases {
+		actions = append(actions, AliasAction{Action: AliasActionRemove, Index: req.Index, Alias: alias})
+	}
+	if err := c.UpdateAliases(ctx, actions); err != nil {
+		return nil, errors.Wrapf(err, "failed to detach aliases of index %q", req.Index)
+	}
+
+	return archived, nil
+}
+
+// RestoreCompanyIndex reverses ArchiveCompanyIndex: unblocks writes, reattaches recorded aliases
+// and removes archive record. Returns archive record the index was restored from.
+func (c *Client) RestoreCompanyIndex(ctx context.Context, index string) (*ArchivedIndex, error) {
+	if index == "" {
+		return nil, errors.New("index name is required")
+	}
+
+	meta, err := c.GetIndex(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := indexMeta(meta)[archiveMetaKey]
+	if !ok {
+		return nil, errors.Errorf("index %q is not archived", index)
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode archive record")
+	}
+	var archived ArchivedIndex
+	if err := json.Unmarshal(b, &archived); err != nil {
+		return nil, errors.Wrapf(err, "invalid archive record of index %q", index)
+	}
+	archived.Index = index
+
+	if err := c.PutSettings(ctx, index, map[string]any{"index.blocks.write": nil}); err != nil {
+		return nil, errors.Wrapf(err, "failed to unblock writes to index %q", index)
+	}
+
+	actions := make([]AliasAction, 0, len(archived.Aliases))
+	for alias, props := range archived.Aliases {
+		actions = append(actions, AliasAction{Action: AliasActionAdd, Index: index, Alias: alias, Props: props})
+	}
+	if err := c.UpdateAliases(ctx, actions); err != nil {
+		return nil, errors.Wrapf(err, "failed to reattach aliases of index %q", index)
+	}
+
+	if err := c.putArchiveRecord(ctx, meta, nil); err != nil {
+		return nil, err
+	}
+
+	return &archived, nil
+}
+
+// putArchiveRecord sets or, if archived is nil, removes archive record in index _meta,
+// keeping other _meta keys.
+func (c *Client) putArchiveRecord(ctx context.Context, meta *IndexMetadata, archived *ArchivedIndex) error {
+	indexMetaCopy := deepCopyMap(indexMeta(meta))
+	if indexMetaCopy == nil {
+		indexMetaCopy = make(map[string]any)
+	}
+	if archived != nil {
+		indexMetaCopy[archiveMetaKey] = archived
+	} else {
+		delete(indexMetaCopy, archiveMetaKey)
+	}
+
+	path := fmt.Sprintf("/%s/_mapping", meta.Name)
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPut, path, nil, map[string]any{"_meta": indexMetaCopy}, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return &StatusError{Op: "put_mapping", StatusCode: status, body: errBody}
+	}
+
+	return nil
+}
+
+// indexMeta returns _meta of index mappings.
+func indexMeta(meta *IndexMetadata) map[string]any {
+	m, _ := meta.Mappings["_meta"].(map[string]any)
+	return m
+}
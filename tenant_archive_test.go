@@ -0,0 +1,58 @@
+package esclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ArchiveRestoreCompanyIndex(t *testing.T) {
+	index := "orders_2f1b8c4e-5d6a-4b7c-8e9f-0a1b2c3d4e5f"
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"` + index + `": {
+			"aliases": {"orders_live": {"is_write_index": true}},
+			"mappings": {"_meta": {"owner": "catalog"}}
+		}}`},
+		{}, {}, {},
+	}}
+	clock := NewFakeClock(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	client, err := NewClient(es, "http://localhost:9200", WithClock(clock))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	archived, err := client.ArchiveCompanyIndex(ctx, &ArchiveCompanyIndexRequest{Index: index})
+	require.NoError(t, err)
+	assert.Contains(t, archived.Aliases, "orders_live")
+
+	assert.Equal(t, []string{
+		"GET /" + index,
+		"PUT /" + index + "/_mapping",
+		"PUT /" + index + "/_settings",
+		"POST /_aliases",
+	}, es.paths)
+	assert.JSONEq(t, `{"_meta": {
+		"owner": "catalog",
+		"esclient_archive": {"aliases": {"orders_live": {"is_write_index": true}}, "archived_at": "2025-03-01T00:00:00Z"}
+	}}`, es.bodies[1])
+	assert.JSONEq(t, `{"index.blocks.write": true}`, es.bodies[2])
+	assert.JSONEq(t, `{"actions": [{"remove": {"index": "`+index+`", "alias": "orders_live"}}]}`, es.bodies[3])
+
+	// Restore reads record from _meta
+	es.responses = []scriptedResponse{
+		{body: `{"` + index + `": {"mappings": {"_meta": {"owner": "catalog", "esclient_archive": ` +
+			`{"aliases": {"orders_live": {"is_write_index": true}}, "archived_at": "2025-03-01T00:00:00Z"}}}}}`},
+		{}, {}, {},
+	}
+	es.paths, es.bodies = nil, nil
+
+	restored, err := client.RestoreCompanyIndex(ctx, index)
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now(), restored.ArchivedAt)
+
+	assert.JSONEq(t, `{"index.blocks.write": null}`, es.bodies[1])
+	assert.JSONEq(t, `{"actions": [{"add": {"index": "`+index+`", "alias": "orders_live", "is_write_index": true}}]}`, es.bodies[2])
+	assert.JSONEq(t, `{"_meta": {"owner": "catalog"}}`, es.bodies[3])
+}
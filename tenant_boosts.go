@@ -0,0 +1,109 @@
+package esclient
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ScoreBoost is function_score adjustment of search relevance (e.g., promote in-stock items).
+type ScoreBoost struct {
+	// Functions are function_score functions, e.g.
+	// {"filter": {"term": {"in_stock": true}}, "weight": 2}.
+	Functions []map[string]any
+	ScoreMode string   // How function scores are combined (ES default: "multiply"), optional
+	BoostMode string   // How combined function score is applied to query score (ES default: "multiply"), optional
+	MaxBoost  *float64 // Cap of combined function score, optional
+}
+
+// TenantBoosts holds score boosts per company and index prefix. Safe for concurrent use,
+// so boosts can be updated at runtime (e.g., from company settings).
+type TenantBoosts struct {
+	mu    sync.RWMutex
+	boost map[string]map[string]ScoreBoost // company ID -> index prefix -> boost
+}
+
+// NewTenantBoosts creates empty tenant boost registry.
+func NewTenantBoosts() *TenantBoosts {
+	return &TenantBoosts{boost: make(map[string]map[string]ScoreBoost)}
+}
+
+// WithTenantBoosts merges score boosts of company into every search with CompanyID.
+func WithTenantBoosts(boosts *TenantBoosts) ClientOption {
+	return func(c *Client) {
+		c.boosts = boosts
+	}
+}
+
+// Set registers boost of company for indices starting with index prefix
+// (e.g., "products" matches "products" and "products_<companyID>"); empty prefix matches all indices.
+// Boost without functions removes registration.
+func (b *TenantBoosts) Set(companyID, indexPrefix string, boost ScoreBoost) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(boost.Functions) == 0 {
+		delete(b.boost[companyID], indexPrefix)
+		if len(b.boost[companyID]) == 0 {
+			delete(b.boost, companyID)
+		}
+		return
+	}
+	if b.boost[companyID] == nil {
+		b.boost[companyID] = make(map[string]ScoreBoost)
+	}
+	b.boost[companyID][indexPrefix] = boost
+}
+
+// match returns boosts of company matching index, ordered by prefix.
+func (b *TenantBoosts) match(companyID, index string) []ScoreBoost {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	prefixes := make([]string, 0, len(b.boost[companyID]))
+	for prefix := range b.boost[companyID] {
+		if strings.HasPrefix(index, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Strings(prefixes)
+
+	result := make([]ScoreBoost, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		result = append(result, b.boost[companyID][prefix])
+	}
+	return result
+}
+
+// ApplyScoreBoosts wraps query into function_score with functions of boosts.
+// Modes and max boost of the last boost setting them win. Query without "query" is boosted match_all.
+func (m *QueryMutator) ApplyScoreBoosts(query map[string]any, boosts ...ScoreBoost) {
+	if len(boosts) == 0 {
+		return
+	}
+
+	inner, ok := query["query"]
+	if !ok {
+		inner = map[string]any{"match_all": map[string]any{}}
+	}
+
+	var functions []any
+	functionScore := map[string]any{"query": inner}
+	for _, boost := range boosts {
+		for _, fn := range boost.Functions {
+			functions = append(functions, deepCopyMap(fn))
+		}
+		if boost.ScoreMode != "" {
+			functionScore["score_mode"] = boost.ScoreMode
+		}
+		if boost.BoostMode != "" {
+			functionScore["boost_mode"] = boost.BoostMode
+		}
+		if boost.MaxBoost != nil {
+			functionScore["max_boost"] = *boost.MaxBoost
+		}
+	}
+	functionScore["functions"] = functions
+
+	query["query"] = map[string]any{"function_score": functionScore}
+}
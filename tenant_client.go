@@ -0,0 +1,338 @@
+package esclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TenantClient exposes operations scoped to one company for customer-facing services.
+// Indices are resolved from index type (e.g., "orders") and never passed by caller,
+// queries are filtered by company and written documents are stamped with company ID.
+type TenantClient struct {
+	resolver  IndexResolver
+	companyID string
+}
+
+// TenantSearchRequest is search of TenantClient. It has no index, routing, point-in-time
+// or cross-tenant parameters, so it cannot leave company scope.
+type TenantSearchRequest struct {
+	Query              map[string]any // Query body (JSON) with query, aggs, sort and _source only; queries failing isolation lint are rejected
+	Size               *int           // Number of results to return
+	From               *int           // Offset for pagination
+	Sort               []SortClause   // Sort clauses, override "sort" in Query if set
+	SearchAfter        interface{}    // Search after values for pagination
+	Highlight          *Highlight     // Highlight configuration, optional
+	WithTrackTotalHits bool           // Track total hits accurately
+}
+
+// TenantBulkOperation is single document write of TenantClient.Bulk.
+type TenantBulkOperation struct {
+	ID     string // Document ID
+	Body   any    // Document source, marshalled to JSON; ignored for Delete
+	Delete bool   // Delete document instead of indexing Body
+}
+
+// NewTenantClient creates client scoped to company. Resolver is usually *Resolver.
+func NewTenantClient(resolver IndexResolver, companyID string) (*TenantClient, error) {
+	if resolver == nil {
+		return nil, errors.New("resolver is required")
+	}
+	if companyID == "" {
+		return nil, errors.New("company ID is required")
+	}
+	return &TenantClient{resolver: resolver, companyID: companyID}, nil
+}
+
+// CompanyID returns company of client.
+func (t *TenantClient) CompanyID() string {
+	return t.companyID
+}
+
+// Search searches company index of index type.
+func (t *TenantClient) Search(ctx context.Context, indexType string, req *TenantSearchRequest) (*SearchResponse, error) {
+	target, err := t.resolveSearch(ctx, indexType)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTenantQuery(req.Query, target.Target, tenantSearchKeys); err != nil {
+		return nil, err
+	}
+
+	return target.Client.Search(ctx, &SearchRequest{
+		Index:              target.Index,
+		Target:             target.Target,
+		Query:              req.Query,
+		CompanyID:          t.companyID,
+		Size:               req.Size,
+		From:               req.From,
+		Sort:               req.Sort,
+		SearchAfter:        req.SearchAfter,
+		Highlight:          req.Highlight,
+		WithTrackTotalHits: req.WithTrackTotalHits,
+	})
+}
+
+// Count counts documents of company index of index type matching query (all documents if nil).
+func (t *TenantClient) Count(ctx context.Context, indexType string, query map[string]any) (int, error) {
+	target, err := t.resolveSearch(ctx, indexType)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkTenantQuery(query, target.Target, tenantCountKeys); err != nil {
+		return 0, err
+	}
+
+	resp, err := target.Client.Count(ctx, &CountRequest{Index: target.Index, Target: target.Target, Query: query, CompanyID: t.companyID})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// Get returns document of company by ID; Found is false if it does not exist or belongs to other company.
+// For companies split into multiple indices document is looked up with search, so version fields are not set.
+func (t *TenantClient) Get(ctx context.Context, indexType, id string) (*GetDocumentResponse, error) {
+	if id == "" {
+		return nil, errors.New("document ID is required")
+	}
+	target, err := t.resolveSearch(ctx, indexType)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.ContainsAny(target.Index, ",*") {
+		return target.Client.GetDocument(ctx, &GetDocumentRequest{Index: target.Index, DocumentID: id, CompanyID: t.companyID})
+	}
+
+	size := 1
+	resp, err := target.Client.Search(ctx, &SearchRequest{
+		Index:     target.Index,
+		Target:    target.Target,
+		Query:     map[string]any{"query": map[string]any{"ids": map[string]any{"values": []string{id}}}},
+		CompanyID: t.companyID,
+		Size:      &size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &GetDocumentResponse{ID: id}
+	if len(resp.Hits.Hits) == 0 {
+		return result, nil
+	}
+	hit := resp.Hits.Hits[0]
+	source, err := json.Marshal(hit["_source"])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode document source")
+	}
+	result.Index, _ = hit["_index"].(string)
+	result.Found = true
+	result.Source = source
+	return result, nil
+}
+
+// Index creates or replaces document of company with ID, stamping company ID into it.
+// On shared index ID of other company's document is rejected with ErrDocumentNotOwned,
+// and write fails with conflict (see IsConflict) if document changed since ownership check.
+func (t *TenantClient) Index(ctx context.Context, indexType, id string, doc any) (*CreateDocumentResponse, error) {
+	if id == "" {
+		return nil, errors.New("document ID is required")
+	}
+	client, index, err := t.resolveWrite(ctx, indexType)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to encode %s document", indexType)
+	}
+
+	req := &CreateDocumentRequest{
+		Index:          index,
+		DocumentID:     id,
+		Body:           bytes.NewReader(body),
+		CompanyID:      t.companyID,
+		StampCompanyID: true,
+	}
+	if DetectIndexTarget(index) == IndexTargetShared {
+		owners, err := t.documentOwners(ctx, client, index, []string{id})
+		if err != nil {
+			return nil, err
+		}
+		owner, exists := owners[id]
+		if exists && !owner.owned {
+			return nil, errors.Wrapf(ErrDocumentNotOwned, "document %q", id)
+		}
+		if exists {
+			req.IfSeqNo, req.IfPrimaryTerm = &owner.seqNo, &owner.primaryTerm
+		} else {
+			req.OpType = "create"
+		}
+	}
+	return client.CreateDocument(ctx, req)
+}
+
+// Bulk writes documents of company in one bulk request, stamping company ID into indexed documents.
+// Bulk body is built by client, so operations cannot target other indices. On shared index
+// IDs of other companies' documents are rejected with ErrDocumentNotOwned before sending.
+func (t *TenantClient) Bulk(ctx context.Context, indexType string, ops []TenantBulkOperation) (*BulkResponse, error) {
+	if len(ops) == 0 {
+		return nil, errors.New("bulk operations are required")
+	}
+	ids := make([]string, 0, len(ops))
+	for _, op := range ops {
+		if op.ID == "" {
+			return nil, errors.New("document ID is required")
+		}
+		ids = append(ids, op.ID)
+	}
+	client, index, err := t.resolveWrite(ctx, indexType)
+	if err != nil {
+		return nil, err
+	}
+	shared := DetectIndexTarget(index) == IndexTargetShared
+	var owners map[string]documentOwner
+	if shared {
+		if owners, err = t.documentOwners(ctx, client, index, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, op := range ops {
+		action := "index"
+		if op.Delete {
+			action = "delete"
+		}
+		meta := map[string]any{"_id": op.ID}
+		if owner, exists := owners[op.ID]; exists {
+			if !owner.owned {
+				return nil, errors.Wrapf(ErrDocumentNotOwned, "document %q", op.ID)
+			}
+			meta["if_seq_no"] = owner.seqNo
+			meta["if_primary_term"] = owner.primaryTerm
+		} else if shared && !op.Delete {
+			action = "create"
+		}
+		if err := enc.Encode(map[string]any{action: meta}); err != nil {
+			return nil, errors.Wrap(err, "failed to encode bulk action")
+		}
+		if op.Delete {
+			continue
+		}
+		if err := enc.Encode(op.Body); err != nil {
+			return nil, errors.Wrapf(err, "failed to encode document %q", op.ID)
+		}
+	}
+
+	return client.Bulk(ctx, &BulkRequest{
+		Index:          index,
+		Body:           &buf,
+		CompanyID:      t.companyID,
+		StampCompanyID: true,
+	})
+}
+
+// documentOwner is ownership of document of shared index. Writes are made conditional on it:
+// company's document is written only if unchanged since check (if_seq_no/if_primary_term),
+// missing document only if still missing (op_type=create), and other company's document never.
+// Delete of missing document is sent unconditionally, so it stays idempotent.
+type documentOwner struct {
+	owned       bool // Document belongs to company of TenantClient
+	seqNo       int64
+	primaryTerm int64
+}
+
+// documentOwners looks up documents of shared index by ID with realtime multi get, routed by
+// company as writes are, so document written next is exactly the one checked. Missing IDs are
+// absent from result.
+func (t *TenantClient) documentOwners(ctx context.Context, client *Client, index string, ids []string) (map[string]documentOwner, error) {
+	query := url.Values{}
+	setRouting(query, t.companyID)
+	query.Set("_source_includes", companyIDField)
+
+	var resp struct {
+		Docs []GetDocumentResponse `json:"docs"`
+	}
+	status, errBody, err := client.doJSONRequest(ctx, http.MethodPost, "/"+index+"/_mget", query, map[string]any{"ids": ids}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "mget", StatusCode: status, body: errBody}
+	}
+
+	owners := make(map[string]documentOwner, len(resp.Docs))
+	for _, doc := range resp.Docs {
+		if !doc.Found {
+			continue
+		}
+		var source map[string]any
+		if err := json.Unmarshal(doc.Source, &source); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode document %q", doc.ID)
+		}
+		owners[doc.ID] = documentOwner{
+			owned:       source[companyIDField] == t.companyID,
+			seqNo:       doc.SeqNo,
+			primaryTerm: doc.PrimaryTerm,
+		}
+	}
+	return owners, nil
+}
+
+// resolveSearch resolves read client, index and index target of index type.
+func (t *TenantClient) resolveSearch(ctx context.Context, indexType string) (*ResolvedTarget, error) {
+	if indexType == "" {
+		return nil, errors.New("index type is required")
+	}
+	target, err := resolveSearchTarget(ctx, t.resolver, t.companyID, indexType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve %s index", indexType)
+	}
+	return target, nil
+}
+
+// resolveWrite resolves write client and index of index type.
+func (t *TenantClient) resolveWrite(ctx context.Context, indexType string) (*Client, string, error) {
+	if indexType == "" {
+		return nil, "", errors.New("index type is required")
+	}
+	client, index, err := t.resolver.ResolveWrite(ctx, t.companyID, indexType)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to resolve %s index", indexType)
+	}
+	return client, index, nil
+}
+
+// Top-level body sections accepted from TenantClient callers. Company filter is injected into
+// "query" only, so other sections (e.g., "knn" unioned with query hits, or "suggest" ignoring
+// query filters) could return documents of other companies.
+var (
+	tenantSearchKeys = map[string]bool{"query": true, "aggs": true, "aggregations": true, "sort": true, "_source": true}
+	tenantCountKeys  = map[string]bool{"query": true}
+)
+
+// checkTenantQuery rejects query with sections other than allowed or with tenant
+// isolation errors (e.g., global aggregation).
+func checkTenantQuery(query map[string]any, target IndexTarget, allowed map[string]bool) error {
+	if query == nil {
+		return nil
+	}
+	for key := range query {
+		if !allowed[key] {
+			return errors.Wrapf(ErrUnsafeQuery, "section %q is not allowed", key)
+		}
+	}
+	for _, issue := range LintQuery(query, target) {
+		if issue.Severity == LintError && issue.Rule == LintRuleIsolation {
+			return errors.Wrap(ErrUnsafeQuery, issue.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package esclient
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultTenantUpdatePollInterval is task polling interval of UpdateByQueryAcrossTenants.
+const defaultTenantUpdatePollInterval = 5 * time.Second
+
+// TenantUpdateRequest represents the same update_by_query run against index of every company,
+// e.g. backfill of a new field.
+type TenantUpdateRequest struct {
+	Resolver  IndexResolver  // Resolves read index expression of company, covering all its indices
+	IndexType string         // Index type (e.g., "orders")
+	Companies []string       // Companies to update, in order
+	Query     map[string]any // update_by_query body with "script" and optional "query"
+
+	RequestsPerSecond *float64      // Per-index throttle in sub-requests per second, optional
+	Conflicts         string        // Version conflict handling (default: "proceed")
+	PollInterval      time.Duration // Task polling interval (default: 5s)
+
+	// CheckpointFile is path of JSON file recording completed companies and running task.
+	// Rerun with the same file skips completed companies and resumes waiting on running task. Optional.
+	CheckpointFile string
+
+	// OnProgress is called on every task poll and after every company, optional.
+	OnProgress func(TenantUpdateProgress)
+}
+
+// TenantUpdateProgress reports progress of UpdateByQueryAcrossTenants.
+type TenantUpdateProgress struct {
+	CompanyID string // Company being updated
+	Index     string // Its resolved index
+	Updated   int    // Documents updated in index so far
+	Total     int    // Documents matching query in index, 0 until reported by task
+	Completed int    // Companies completed, including skipped by checkpoint
+	Companies int    // Companies in request
+}
+
+// TenantUpdateCheckpoint is content of TenantUpdateRequest.CheckpointFile.
+type TenantUpdateCheckpoint struct {
+	Completed map[string]TenantUpdateResult `json:"completed"`         // Company ID -> result
+	Running   *TenantUpdateTask             `json:"running,omitempty"` // Task started but not yet completed
+}
+
+// TenantUpdateResult is result of update_by_query on company index.
+type TenantUpdateResult struct {
+	Index            string `json:"index"`
+	Total            int    `json:"total"`
+	Updated          int    `json:"updated"`
+	VersionConflicts int    `json:"version_conflicts"`
+}
+
+// TenantUpdateTask is running update_by_query task of company.
+type TenantUpdateTask struct {
+	CompanyID string `json:"company_id"`
+	Index     string `json:"index"`
+	TaskID    string `json:"task_id"`
+}
+
+// UpdateByQueryAcrossTenants runs update_by_query on index of every company, one index at a time,
+// as asynchronous task with per-index throttle. Company index is resolved as for search, so companies
+// split into multiple indices (e.g., monthly) are updated in all of them, not only in write index. Progress is saved to checkpoint file after every
+// company, so interrupted run can be resumed. Stops at first failed company.
+// Returns results of all completed companies, including ones completed by previous runs.
+func UpdateByQueryAcrossTenants(ctx context.Context, req *TenantUpdateRequest) (map[string]TenantUpdateResult, error) {
+	if req.Resolver == nil || req.IndexType == "" {
+		return nil, errors.New("resolver and index type are required")
+	}
+	if req.Query == nil {
+		return nil, errors.New("update by query body is required")
+	}
+	interval := req.PollInterval
+	if interval <= 0 {
+		interval = defaultTenantUpdatePollInterval
+	}
+	conflicts := req.Conflicts
+	if conflicts == "" {
+		conflicts = "proceed"
+	}
+
+	cp, err := loadTenantUpdateCheckpoint(req.CheckpointFile)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := TenantUpdateProgress{Companies: len(req.Companies)}
+	report := func() {
+		if req.OnProgress != nil {
+			req.OnProgress(progress)
+		}
+	}
+
+	for _, companyID := range req.Companies {
+		if _, ok := cp.Completed[companyID]; ok {
+			progress.Completed++
+			continue
+		}
+
+		resolved, err := resolveSearchTarget(ctx, req.Resolver, companyID, req.IndexType)
+		if err != nil {
+			return cp.Completed, errors.Wrapf(err, "failed to resolve %s index of company %s", req.IndexType, companyID)
+		}
+		client, index := resolved.Client, resolved.Index
+		progress.CompanyID, progress.Index, progress.Updated, progress.Total = companyID, index, 0, 0
+
+		taskID := ""
+		if cp.Running != nil && cp.Running.CompanyID == companyID && cp.Running.Index == index {
+			taskID = cp.Running.TaskID
+		} else {
+			wait := false
+			started, err := client.UpdateByQuery(ctx, &UpdateByQueryRequest{
+				Index:             index,
+				Target:            resolved.Target,
+				Query:             req.Query,
+				CompanyID:         companyID,
+				Conflicts:         conflicts,
+				WaitForCompletion: &wait,
+				RequestsPerSecond: req.RequestsPerSecond,
+			})
+			if err != nil {
+				return cp.Completed, errors.Wrapf(err, "failed to start update by query of company %s", companyID)
+			}
+			taskID = started.Task
+			cp.Running = &TenantUpdateTask{CompanyID: companyID, Index: index, TaskID: taskID}
+			if err := saveTenantUpdateCheckpoint(req.CheckpointFile, cp); err != nil {
+				return cp.Completed, err
+			}
+		}
+
+		var resp UpdateByQueryResponse
+		err = client.waitTask(ctx, taskID, interval, func(task *GetTaskResponse) {
+			progress.Updated, _ = toInt(task.Task.Status["updated"])
+			progress.Total, _ = toInt(task.Task.Status["total"])
+			report()
+		}, &resp)
+		if err != nil {
+			return cp.Completed, errors.Wrapf(err, "update by query of company %s", companyID)
+		}
+
+		cp.Completed[companyID] = TenantUpdateResult{
+			Index:            index,
+			Total:            resp.Total,
+			Updated:          resp.Updated,
+			VersionConflicts: resp.VersionConflicts,
+		}
+		cp.Running = nil
+		if err := saveTenantUpdateCheckpoint(req.CheckpointFile, cp); err != nil {
+			return cp.Completed, err
+		}
+
+		progress.Completed++
+		progress.Updated, progress.Total = resp.Updated, resp.Total
+		report()
+	}
+
+	return cp.Completed, nil
+}
+
+// loadTenantUpdateCheckpoint reads checkpoint file; missing file or empty path means fresh run.
+func loadTenantUpdateCheckpoint(path string) (*TenantUpdateCheckpoint, error) {
+	cp := &TenantUpdateCheckpoint{Completed: make(map[string]TenantUpdateResult)}
+	if path == "" {
+		return cp, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read tenant update checkpoint")
+	}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode tenant update checkpoint")
+	}
+	if cp.Completed == nil {
+		cp.Completed = make(map[string]TenantUpdateResult)
+	}
+	return cp, nil
+}
+
+// saveTenantUpdateCheckpoint atomically replaces checkpoint file, so crash never leaves it truncated.
+func saveTenantUpdateCheckpoint(path string, cp *TenantUpdateCheckpoint) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode tenant update checkpoint")
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return errors.Wrap(err, "failed to write tenant update checkpoint")
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck
+		return errors.Wrap(err, "failed to write tenant update checkpoint")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write tenant update checkpoint")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrap(err, "failed to write tenant update checkpoint")
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateByQueryAcrossTenants_Resume(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"task": "node:1"}`},
+		{body: `{"completed": true, "response": {"total": 5, "updated": 5}}`},
+		{body: `{"task": "node:2"}`},
+		{status: http.StatusInternalServerError},
+	}}
+	req := &TenantUpdateRequest{
+		Resolver:       &staticResolver{client: newTestClient(t, es), index: "orders_shared"},
+		IndexType:      IndexTypeOrders,
+		Companies:      []string{"c1", "c2"},
+		Query:          map[string]any{"script": map[string]any{"source": "ctx._source.currency = 'UZS'"}},
+		CheckpointFile: filepath.Join(t.TempDir(), "backfill.json"),
+	}
+
+	// Interrupted while waiting on task of c2
+	completed, err := UpdateByQueryAcrossTenants(context.Background(), req)
+	require.Error(t, err)
+	assert.Equal(t, map[string]TenantUpdateResult{"c1": {Index: "orders_shared", Total: 5, Updated: 5}}, completed)
+	assert.Contains(t, es.bodies[0], `"company_id.keyword":"c1"`)
+
+	// Resumed run skips c1 and waits on already running task of c2
+	es.responses = []scriptedResponse{{body: `{"completed": true, "response": {"total": 3, "updated": 2, "version_conflicts": 1}}`}}
+	var progress []TenantUpdateProgress
+	req.OnProgress = func(p TenantUpdateProgress) {
+		progress = append(progress, p)
+	}
+	completed, err = UpdateByQueryAcrossTenants(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "GET /_tasks/node:2", es.paths[len(es.paths)-1])
+	assert.Len(t, es.paths, 5)
+	assert.Equal(t, TenantUpdateResult{Index: "orders_shared", Total: 3, Updated: 2, VersionConflicts: 1}, completed["c2"])
+	assert.Len(t, completed, 2)
+	assert.Equal(t, TenantUpdateProgress{CompanyID: "c2", Index: "orders_shared", Updated: 2, Total: 3, Completed: 2, Companies: 2}, progress[len(progress)-1])
+
+	cp, err := loadTenantUpdateCheckpoint(req.CheckpointFile)
+	require.NoError(t, err)
+	assert.Nil(t, cp.Running)
+	assert.Len(t, cp.Completed, 2)
+}
+
+// monthlyResolver resolves company to monthly indices, writing into the current one.
+type monthlyResolver struct {
+	client *Client
+}
+
+func (r *monthlyResolver) ResolveSearch(ctx context.Context, companyID, indexType string) (*Client, string, error) {
+	return r.client, indexType + "_" + companyID + "_*", nil
+}
+
+func (r *monthlyResolver) ResolveWrite(ctx context.Context, companyID, indexType string) (*Client, string, error) {
+	return r.client, indexType + "_" + companyID + "_2025_06", nil
+}
+
+func TestUpdateByQueryAcrossTenants_AllCompanyIndices(t *testing.T) {
+	es := &scriptedES{responses: []scriptedResponse{
+		{body: `{"task": "node:1"}`},
+		{body: `{"completed": true, "response": {"total": 7, "updated": 7}}`},
+	}}
+	completed, err := UpdateByQueryAcrossTenants(context.Background(), &TenantUpdateRequest{
+		Resolver:  &monthlyResolver{client: newTestClient(t, es)},
+		IndexType: IndexTypeOrders,
+		Companies: []string{"c1"},
+		Query:     map[string]any{"script": map[string]any{"source": "ctx._source.currency = 'UZS'"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "POST /orders_c1_*/_update_by_query", es.paths[0])
+	assert.Equal(t, map[string]TenantUpdateResult{"c1": {Index: "orders_c1_*", Total: 7, Updated: 7}}, completed)
+}
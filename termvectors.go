@@ -0,0 +1,177 @@
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// TermVectorsRequest represents term vectors request of stored or artificial document.
+type TermVectorsRequest struct {
+	Index           string         // Index name
+	ID              string         // Document ID; empty if Doc is set
+	Doc             map[string]any // Artificial document analyzed instead of stored one, optional
+	Fields          []string       // Fields to return, all fields with term vectors if empty
+	CompanyID       string         // Company ID, default routing for shared indices
+	Routing         string         // Routing value, optional
+	TermStatistics  bool           // Return total term frequency and document frequency
+	FieldStatistics *bool          // Return field statistics (ES default: true)
+	Positions       *bool          // Return term positions (ES default: true)
+	Offsets         *bool          // Return term offsets (ES default: true)
+	Payloads        *bool          // Return term payloads (ES default: true)
+	Filter          map[string]any // Filter terms by tf-idf (e.g., {"max_num_terms": 25})
+}
+
+// TermVectorsResponse represents term vectors of document.
+type TermVectorsResponse struct {
+	Index       string                      `json:"_index"`
+	ID          string                      `json:"_id"`
+	Version     int                         `json:"_version"`
+	Found       bool                        `json:"found"`
+	Took        int                         `json:"took"`
+	TermVectors map[string]FieldTermVectors `json:"term_vectors"` // Field -> term vectors
+}
+
+// FieldTermVectors represents term vectors of field.
+type FieldTermVectors struct {
+	FieldStatistics *FieldStatistics      `json:"field_statistics,omitempty"`
+	Terms           map[string]TermVector `json:"terms"` // Term -> statistics
+}
+
+// FieldStatistics represents statistics of field across index.
+type FieldStatistics struct {
+	SumDocFreq int64 `json:"sum_doc_freq"`
+	DocCount   int64 `json:"doc_count"`
+	SumTTF     int64 `json:"sum_ttf"`
+}
+
+// TermVector represents statistics and occurrences of term in document.
+type TermVector struct {
+	TermFreq int         `json:"term_freq"`
+	DocFreq  int64       `json:"doc_freq,omitempty"` // Set with TermStatistics
+	TTF      int64       `json:"ttf,omitempty"`      // Total term frequency, set with TermStatistics
+	Score    float64     `json:"score,omitempty"`    // tf-idf score, set with Filter
+	Tokens   []TermToken `json:"tokens,omitempty"`
+}
+
+// TermToken represents occurrence of term.
+type TermToken struct {
+	Position    int    `json:"position"`
+	StartOffset int    `json:"start_offset"`
+	EndOffset   int    `json:"end_offset"`
+	Payload     string `json:"payload,omitempty"`
+}
+
+// TermVectors returns term vectors of document.
+func (c *Client) TermVectors(ctx context.Context, req *TermVectorsRequest) (*TermVectorsResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/%s/_termvectors", req.Index)
+	if req.ID != "" {
+		path = fmt.Sprintf("/%s/_termvectors/%s", req.Index, req.ID)
+	}
+
+	query := url.Values{}
+	setRouting(query, req.routing())
+
+	var resp TermVectorsResponse
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, path, query, req.body(), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "termvectors", StatusCode: status, body: errBody}
+	}
+
+	return &resp, nil
+}
+
+// MTermVectors returns term vectors of multiple documents in one request.
+// Responses are in request order; documents not found have Found=false.
+func (c *Client) MTermVectors(ctx context.Context, reqs []TermVectorsRequest) ([]TermVectorsResponse, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	docs := make([]map[string]any, 0, len(reqs))
+	for i := range reqs {
+		if err := reqs[i].validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid document %d", i)
+		}
+		doc := reqs[i].body()
+		doc["_index"] = reqs[i].Index
+		if reqs[i].ID != "" {
+			doc["_id"] = reqs[i].ID
+		}
+		if routing := reqs[i].routing(); routing != "" {
+			doc["routing"] = routing
+		}
+		docs = append(docs, doc)
+	}
+
+	var resp struct {
+		Docs []TermVectorsResponse `json:"docs"`
+	}
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodPost, "/_mtermvectors", nil, map[string]any{"docs": docs}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "mtermvectors", StatusCode: status, body: errBody}
+	}
+
+	return resp.Docs, nil
+}
+
+// validate checks that request targets stored or artificial document.
+func (r *TermVectorsRequest) validate() error {
+	if r.Index == "" {
+		return errors.New("index name is required")
+	}
+	if r.ID == "" && r.Doc == nil {
+		return errors.New("document ID or artificial document is required")
+	}
+	return nil
+}
+
+// routing returns routing of document; defaults to company ID for shared indices.
+func (r *TermVectorsRequest) routing() string {
+	return routingFor(r.Routing, r.CompanyID, DetectIndexTarget(r.Index))
+}
+
+// body builds term vectors request body.
+func (r *TermVectorsRequest) body() map[string]any {
+	body := map[string]any{}
+	if len(r.Fields) > 0 {
+		body["fields"] = r.Fields
+	}
+	if r.Doc != nil {
+		body["doc"] = r.Doc
+	}
+	if r.TermStatistics {
+		body["term_statistics"] = true
+	}
+	if r.FieldStatistics != nil {
+		body["field_statistics"] = *r.FieldStatistics
+	}
+	if r.Positions != nil {
+		body["positions"] = *r.Positions
+	}
+	if r.Offsets != nil {
+		body["offsets"] = *r.Offsets
+	}
+	if r.Payloads != nil {
+		body["payloads"] = *r.Payloads
+	}
+	if len(r.Filter) > 0 {
+		body["filter"] = r.Filter
+	}
+	return body
+}
@@ -0,0 +1,171 @@
+package esclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultDialKeepAlive is keep-alive period of connections dialed with ClusterConfig.DialTimeout.
+const defaultDialKeepAlive = 30 * time.Second
+
+// clusterTransport builds HTTP transport for cluster from its configuration.
+// Explicit Transport is cloned and tuning fields are applied on top of it; SigV4 signing wraps it.
+// Returns nil if cluster needs no custom transport (ES client default is used).
+func clusterTransport(cfg ClusterConfig) (http.RoundTripper, error) {
+	transport, err := httpTransport(cfg)
+	if err != nil || cfg.SigV4 == nil {
+		return transport, err
+	}
+	return newSigV4Transport(transport, *cfg.SigV4)
+}
+
+// httpTransport builds HTTP transport of cluster without request signing; returns nil if not needed.
+func httpTransport(cfg ClusterConfig) (http.RoundTripper, error) {
+	tlsConfig, err := clusterTLS(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Transport == nil && cfg.ProxyURL == "" && tlsConfig == nil && !cfg.tunesTransport() {
+		return nil, nil
+	}
+
+	var transport *http.Transport
+	if cfg.Transport != nil {
+		transport = cfg.Transport.Clone()
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := parseProxyURL(cfg.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		// Explicit proxy per cluster; process-wide HTTP_PROXY env vars are ignored
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if tlsConfig != nil {
+		if transport.TLSClientConfig != nil {
+			// Keep settings of explicit transport not covered by cluster config
+			base := transport.TLSClientConfig.Clone()
+			base.RootCAs, base.Certificates, base.InsecureSkipVerify = tlsConfig.RootCAs, tlsConfig.Certificates, tlsConfig.InsecureSkipVerify
+			tlsConfig = base
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+		// Per-host pool cannot exceed total one
+		if transport.MaxIdleConns > 0 && transport.MaxIdleConns < cfg.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = cfg.MaxIdleConnsPerHost
+		}
+	}
+	if cfg.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	if cfg.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+	}
+	if cfg.DialTimeout > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: defaultDialKeepAlive}).DialContext
+	}
+
+	return transport, nil
+}
+
+// clusterTLS builds TLS config of cluster; returns nil if cluster config sets no TLS fields.
+func clusterTLS(cfg ClusterConfig) (*tls.Config, error) {
+	if cfg.CACert == "" && cfg.ClientCert == "" && cfg.ClientKey == "" && !cfg.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // explicit opt-in for test clusters
+	}
+
+	if cfg.CACert != "" {
+		caPEM, err := readPEM(cfg.CACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CA certificate")
+		}
+		// System roots are kept, so CA of private cluster is added to them
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("CA certificate contains no valid PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, errors.New("client certificate and key must be set together")
+		}
+		certPEM, err := readPEM(cfg.ClientCert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read client certificate")
+		}
+		keyPEM, err := readPEM(cfg.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read client key")
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid client certificate or key")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// readPEM returns value as is if it holds PEM content, otherwise reads file at value path.
+func readPEM(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// tunesTransport reports whether cluster config sets any connection pool tuning.
+func (c ClusterConfig) tunesTransport() bool {
+	return c.MaxIdleConnsPerHost > 0 || c.IdleConnTimeout > 0 || c.DialTimeout > 0 || c.ResponseHeaderTimeout > 0
+}
+
+// parseProxyURL parses and validates proxy URL.
+func parseProxyURL(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		// *url.Error quotes whole URL, including credentials
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, errors.Wrap(err, "invalid proxy URL")
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, errors.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, errors.New("proxy URL must include host")
+	}
+
+	return u, nil
+}
@@ -1,6 +1,10 @@
 package esclient
 
-import "io"
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
 
 // IndexTarget represents index type
 type IndexTarget string
@@ -12,23 +16,61 @@ const (
 
 // SearchRequest represents Elasticsearch search request.
 type SearchRequest struct {
-	Index              string         // Index name or pattern
-	Query              map[string]any // Query body (JSON)
-	CompanyID          string         // Company ID for per-company index
-	Size               *int           // Number of results to return
-	From               *int           // Offset for pagination
-	WithTrackTotalHits bool           // Track total hits accurately
-	PointInTime        *string        // Point-in-time ID for pagination
-	SearchAfter        interface{}    // Search after values for pagination
+	Index               string         // Index name or pattern
+	Target              IndexTarget    // Index target; detected from Index if empty (see ResolvedTarget.Target)
+	Query               map[string]any // Query body (JSON)
+	CompanyID           string         // Company ID for per-company index
+	Size                *int           // Number of results to return
+	From                *int           // Offset for pagination
+	WithTrackTotalHits  bool           // Track total hits accurately
+	PointInTime         *string        // Point-in-time ID for pagination; Index is used only to detect index target
+	PITKeepAlive        time.Duration  // Keep alive extension of PointInTime (default: 1m)
+	SearchAfter         interface{}    // Search after values for pagination
+	Highlight           *Highlight     // Highlight configuration, optional
+	Sort                []SortClause   // Sort clauses, override "sort" in Query if set
+	Routing             string         // Routing value; defaults to CompanyID for shared indices
+	Preference          string         // Shard copy preference (e.g., "_local" or custom session string)
+	IgnoreUnavailable   bool           // Ignore missing or closed indices
+	AllowNoIndices      *bool          // Allow wildcard patterns matching no indices (ES default: true)
+	Timeout             time.Duration  // Server-side search timeout; derived from context deadline if shorter
+	AllowPartialResults *bool          // Return partial results on timeout or shard failure (ES default: true)
+	Knn                 *KnnSearch     // Approximate kNN search on dense_vector field, optional
+	TerminateAfter      int            // Max documents to collect per shard before terminating early, optional
+	MinScore            *float64       // Minimum score of returned hits, optional
+	MoreLikeThis        *MoreLikeThis  // more_like_this clause combined with Query, optional
+
+	// crossTenant skips company filter and routing on shared index. Set by cross-tenant
+	// analytics only and allowed only for clients created with WithCrossTenantAccess.
+	crossTenant *CrossTenantAccess
+}
+
+// Highlight configures highlighting of matched snippets in search hits.
+type Highlight struct {
+	Fields       []string // Fields to highlight
+	PreTags      []string // Tags inserted before highlighted text (default: "<em>")
+	PostTags     []string // Tags inserted after highlighted text (default: "</em>")
+	FragmentSize *int     // Size of highlighted fragment in characters
+}
+
+// SortClause represents a single sort criterion.
+type SortClause struct {
+	Field        string         // Field name (e.g., "created_at", "_score")
+	Order        string         // Sort order: "asc" or "desc"
+	Missing      any            // Missing value handling: "_first", "_last" or custom value
+	Mode         string         // Array value mode: "min", "max", "sum", "avg", "median"
+	NestedPath   string         // Nested object path for sorting by nested field
+	NestedFilter map[string]any // Filter for nested objects, optional
 }
 
 // SearchResponse represents Elasticsearch search response.
 type SearchResponse struct {
-	Took         int                    `json:"took"`
-	TimedOut     bool                   `json:"timed_out"`
-	Shards       map[string]interface{} `json:"_shards"`
-	Aggregations map[string]interface{} `json:"aggregations,omitempty"`
-	Hits         struct {
+	Took            int                    `json:"took"`
+	TimedOut        bool                   `json:"timed_out"`
+	TerminatedEarly bool                   `json:"terminated_early,omitempty"`
+	Shards          map[string]interface{} `json:"_shards"`
+	Aggregations    map[string]interface{} `json:"aggregations,omitempty"`
+	Suggest         map[string]interface{} `json:"suggest,omitempty"`
+	Hits            struct {
 		Total struct {
 			Value    int    `json:"value"`
 			Relation string `json:"relation"`
@@ -37,12 +79,21 @@ type SearchResponse struct {
 		Hits     []map[string]interface{} `json:"hits"`
 	} `json:"hits"`
 	PitID string `json:"pit_id,omitempty"`
+
+	ResponseMeta `json:"-"`
 }
 
 // BulkRequest represents Elasticsearch bulk request.
 type BulkRequest struct {
-	Index string    // Default index name
-	Body  io.Reader // Bulk operations body (NDJSON)
+	Index   string    // Default index name
+	Body    io.Reader // Bulk operations body (NDJSON)
+	Routing string    // Default routing for bulk items; defaults to CompanyID for shared Index
+
+	// CompanyID routes items of shared default index by company, as CreateDocument does, and is
+	// stamped into company_id field of documents written to shared indices when StampCompanyID
+	// is set, so stored documents always match query-time filter and routing.
+	CompanyID      string
+	StampCompanyID bool
 }
 
 // BulkResponse represents Elasticsearch bulk response.
@@ -50,6 +101,8 @@ type BulkResponse struct {
 	Took   int                      `json:"took"`
 	Errors bool                     `json:"errors"`
 	Items  []map[string]interface{} `json:"items"`
+
+	ResponseMeta `json:"-"`
 }
 
 // OpenPITRequest represents open point-in-time request.
@@ -65,9 +118,13 @@ type PIT struct {
 
 // DeleteByQueryRequest represents delete by query request.
 type DeleteByQueryRequest struct {
-	Index     string         // Index name
-	Query     map[string]any // Query body (JSON)
-	CompanyID string         // Company ID for per-company index
+	Index             string         // Index name
+	Target            IndexTarget    // Index target; detected from Index if empty (see ResolvedTarget.Target)
+	Query             map[string]any // Query body (JSON)
+	CompanyID         string         // Company ID for per-company index
+	IgnoreUnavailable bool           // Ignore missing or closed indices
+	AllowNoIndices    *bool          // Allow wildcard patterns matching no indices (ES default: true)
+	Routing           string         // Routing value; defaults to CompanyID for shared indices
 }
 
 // DeleteByQueryResponse represents delete by query response.
@@ -79,12 +136,19 @@ type DeleteByQueryResponse struct {
 	Batches          int                      `json:"batches"`
 	VersionConflicts int                      `json:"version_conflicts"`
 	Failures         []map[string]interface{} `json:"failures"`
+
+	ResponseMeta `json:"-"`
 }
 
 // CreateIndexRequest represents create index request.
+// Either Body or Settings/Mappings/Aliases can be used; Body takes precedence.
 type CreateIndexRequest struct {
-	Index string    // Index name
-	Body  io.Reader // Mappings and settings (JSON)
+	Index    string         // Index name
+	Body     io.Reader      // Mappings and settings (JSON)
+	Template string         // Template name in client template registry, used if Body is nil
+	Settings map[string]any // Index settings (e.g., from ILMSettings), used if Body is nil; override template
+	Mappings map[string]any // Index mappings, used if Body is nil; override template
+	Aliases  map[string]any // Index aliases, used if Body is nil; override template
 }
 
 // IndexExistsRequest represents index exists check request.
@@ -94,26 +158,53 @@ type IndexExistsRequest struct {
 
 // CountRequest represents count request.
 type CountRequest struct {
-	Index     string         // Index name or pattern
-	Query     map[string]any // Query body (JSON), optional
-	CompanyID string         // Company ID for per-company index
+	Index             string         // Index name or pattern
+	Target            IndexTarget    // Index target; detected from Index if empty (see ResolvedTarget.Target)
+	Query             map[string]any // Query body (JSON), optional
+	CompanyID         string         // Company ID for per-company index
+	IgnoreUnavailable bool           // Ignore missing or closed indices
+	AllowNoIndices    *bool          // Allow wildcard patterns matching no indices (ES default: true)
+	Routing           string         // Routing value; defaults to CompanyID for shared indices
+	TerminateAfter    int            // Max documents to count per shard before terminating early, optional
 }
 
 // CountResponse represents count response.
 type CountResponse struct {
-	Count  int                    `json:"count"`
-	Shards map[string]interface{} `json:"_shards"`
+	Count           int                    `json:"count"`
+	TerminatedEarly bool                   `json:"terminated_early,omitempty"` // Count stopped at TerminateAfter
+	Shards          map[string]interface{} `json:"_shards"`
+
+	ResponseMeta `json:"-"`
+}
+
+// ExistsRequest represents check whether any document matches query.
+type ExistsRequest struct {
+	Index             string         // Index name or pattern
+	Target            IndexTarget    // Index target; detected from Index if empty (see ResolvedTarget.Target)
+	Query             map[string]any // Query body (JSON), optional
+	CompanyID         string         // Company ID for per-company index
+	IgnoreUnavailable bool           // Ignore missing or closed indices
+	AllowNoIndices    *bool          // Allow wildcard patterns matching no indices (ES default: true)
+	Routing           string         // Routing value; defaults to CompanyID for shared indices
 }
 
 // UpdateByQueryRequest represents update by query request.
 type UpdateByQueryRequest struct {
-	Index     string         // Index name
-	Query     map[string]any // Query body (JSON)
-	CompanyID string         // Company ID for per-company index
+	Index             string         // Index name
+	Target            IndexTarget    // Index target; detected from Index if empty (see ResolvedTarget.Target)
+	Query             map[string]any // Query body (JSON)
+	CompanyID         string         // Company ID for per-company index
+	Routing           string         // Routing value; defaults to CompanyID for shared indices
+	Conflicts         string         // Version conflict handling: "abort" (default) or "proceed"
+	WaitForCompletion *bool          // If false, runs asynchronously and response contains task ID
+	Slices            string         // Number of parallel slices or "auto", optional
+	RequestsPerSecond *float64       // Throttle in sub-requests per second; -1 disables throttling
 }
 
 // UpdateByQueryResponse represents update by query response.
+// When started with WaitForCompletion=false only Task is set.
 type UpdateByQueryResponse struct {
+	Task             string                   `json:"task,omitempty"`
 	Took             int                      `json:"took"`
 	TimedOut         bool                     `json:"timed_out"`
 	Total            int                      `json:"total"`
@@ -121,24 +212,62 @@ type UpdateByQueryResponse struct {
 	Batches          int                      `json:"batches"`
 	VersionConflicts int                      `json:"version_conflicts"`
 	Failures         []map[string]interface{} `json:"failures"`
+
+	ResponseMeta `json:"-"`
 }
 
 // CreateDocumentRequest represents create document request.
 type CreateDocumentRequest struct {
-	Index      string    // Index name
-	DocumentID string    // Document ID
-	Body       io.Reader // Document body (JSON)
+	Index         string    // Index name
+	DocumentID    string    // Document ID
+	Body          io.Reader // Document body (JSON)
+	Routing       string    // Routing value, optional
+	Refresh       string    // Refresh policy: "true", "false" or "wait_for", optional
+	OpType        string    // Operation type: "index" (default) or "create" (fail if document exists)
+	Version       *int64    // External document version (version_type=external), optional
+	IfSeqNo       *int64    // Write only if document has this sequence number
+	IfPrimaryTerm *int64    // Write only if document has this primary term
+
+	// CompanyID is stamped into company_id field of document written to shared index
+	// when StampCompanyID is set. It is also default routing for shared indices.
+	CompanyID      string
+	StampCompanyID bool
+}
+
+// GetDocumentRequest represents get document request.
+type GetDocumentRequest struct {
+	Index      string // Index name
+	DocumentID string // Document ID
+	Routing    string // Routing value; defaults to CompanyID for shared indices
+
+	// CompanyID is required for shared index; document of other company is reported as not found.
+	CompanyID string
+}
+
+// GetDocumentResponse represents get document response.
+type GetDocumentResponse struct {
+	Index       string          `json:"_index"`
+	ID          string          `json:"_id"`
+	Version     int64           `json:"_version"`
+	SeqNo       int64           `json:"_seq_no"`
+	PrimaryTerm int64           `json:"_primary_term"`
+	Found       bool            `json:"found"`
+	Source      json.RawMessage `json:"_source"`
 }
 
 // CreateDocumentResponse represents create document response.
 type CreateDocumentResponse struct {
-	Index   string `json:"_index"`
-	ID      string `json:"_id"`
-	Version int    `json:"_version"`
-	Result  string `json:"result"` // "created" or "updated"
-	Shards  struct {
+	Index       string `json:"_index"`
+	ID          string `json:"_id"`
+	Version     int    `json:"_version"`
+	Result      string `json:"result"` // "created" or "updated"
+	SeqNo       int64  `json:"_seq_no"`
+	PrimaryTerm int64  `json:"_primary_term"`
+	Shards      struct {
 		Total      int `json:"total"`
 		Successful int `json:"successful"`
 		Failed     int `json:"failed"`
 	} `json:"_shards"`
+
+	ResponseMeta `json:"-"`
 }
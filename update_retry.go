@@ -0,0 +1,92 @@
+package esclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// UpdateWithRetry updates document with read-modify-write: gets document, applies fn to its source
+// and writes result only if document still has read _seq_no and _primary_term. If document was
+// changed meanwhile, it is read and fn is applied again, at most maxRetries more times; then
+// conflict error (IsConflict) is returned. Missing document is passed to fn as zero T and
+// created, failing with conflict if created concurrently. Error of fn stops update as is.
+// Use it for counters (e.g., product stock) instead of blind overwrites. Shared indices require
+// company, see Repository.UpdateWithRetry.
+func UpdateWithRetry[T any](ctx context.Context, c *Client, index, id string, fn func(current T) (T, error), maxRetries int) (T, error) {
+	return updateWithRetry(ctx, c, index, id, "", fn, maxRetries)
+}
+
+// UpdateWithRetry updates document with ID in company index with read-modify-write,
+// retrying on version conflict. See UpdateWithRetry.
+func (r *Repository[T]) UpdateWithRetry(ctx context.Context, companyID, id string, fn func(current T) (T, error), maxRetries int) (T, error) {
+	client, index, err := r.resolver.ResolveWrite(ctx, companyID, r.indexType)
+	if err != nil {
+		var zero T
+		return zero, errors.Wrapf(err, "failed to resolve %s index", r.indexType)
+	}
+	return updateWithRetry(ctx, client, index, id, companyID, fn, maxRetries)
+}
+
+// updateWithRetry implements UpdateWithRetry; company ID is stamped into document if set.
+func updateWithRetry[T any](ctx context.Context, c *Client, index, id, companyID string, fn func(current T) (T, error), maxRetries int) (T, error) {
+	var zero T
+	if fn == nil {
+		return zero, errors.New("update function is required")
+	}
+
+	for attempt := 0; ; attempt++ {
+		current, err := c.GetDocument(ctx, &GetDocumentRequest{Index: index, DocumentID: id, CompanyID: companyID})
+		if err != nil {
+			return zero, err
+		}
+
+		var doc T
+		if current.Found {
+			if err := json.Unmarshal(current.Source, &doc); err != nil {
+				return zero, errors.Wrapf(err, "failed to decode document %q", id)
+			}
+		}
+		updated, err := fn(doc)
+		if err != nil {
+			return zero, err
+		}
+		body, err := json.Marshal(updated)
+		if err != nil {
+			return zero, errors.Wrapf(err, "failed to encode document %q", id)
+		}
+
+		req := &CreateDocumentRequest{
+			Index:          index,
+			DocumentID:     id,
+			Body:           bytes.NewReader(body),
+			CompanyID:      companyID,
+			StampCompanyID: companyID != "",
+		}
+		if current.Found {
+			req.IfSeqNo = &current.SeqNo
+			req.IfPrimaryTerm = &current.PrimaryTerm
+		} else {
+			req.OpType = "create"
+		}
+
+		_, err = c.CreateDocument(ctx, req)
+		if err == nil {
+			return updated, nil
+		}
+		if !IsConflict(err) {
+			return zero, err
+		}
+		if attempt >= maxRetries {
+			return zero, errors.Wrapf(err, "document %q kept changing after %d attempts", id, attempt+1)
+		}
+
+		c.log.DebugWithCtx(ctx, "elasticsearch update conflict, retrying", map[string]interface{}{
+			"index":   index,
+			"id":      id,
+			"attempt": attempt + 1,
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Deprecation issue levels.
+const (
+	DeprecationWarning  = "warning"  // Deprecated, still works after upgrade
+	DeprecationCritical = "critical" // Breaks after upgrade, must be resolved before it
+)
+
+// DeprecationIssue represents single issue reported by deprecation info API.
+type DeprecationIssue struct {
+	Level                       string `json:"level"` // "warning" or "critical"
+	Message                     string `json:"message"`
+	URL                         string `json:"url"`
+	Details                     string `json:"details"`
+	ResolveDuringRollingUpgrade bool   `json:"resolve_during_rolling_upgrade"`
+}
+
+// DeprecationInfo represents deprecation info API response.
+type DeprecationInfo struct {
+	ClusterSettings []DeprecationIssue            `json:"cluster_settings"`
+	NodeSettings    []DeprecationIssue            `json:"node_settings"`
+	MLSettings      []DeprecationIssue            `json:"ml_settings"`
+	IndexSettings   map[string][]DeprecationIssue `json:"index_settings"` // Index name -> issues of its settings and mappings
+	DataStreams     map[string][]DeprecationIssue `json:"data_streams"`
+	Templates       map[string][]DeprecationIssue `json:"templates"`
+	ILMPolicies     map[string][]DeprecationIssue `json:"ilm_policies"`
+}
+
+// UpgradeReport is upgrade readiness of cluster.
+type UpgradeReport struct {
+	Cluster         string           // Cluster name in registry
+	Version         int              // Current ES major version
+	Ready           bool             // No critical issues
+	Critical        int              // Number of critical issues
+	Warnings        int              // Number of warning issues
+	CriticalIndices []string         // Indices with critical issues, sorted
+	Info            *DeprecationInfo // All reported issues
+}
+
+// Deprecations returns deprecated cluster, node, index, template and ILM settings
+// that must or should be resolved before upgrade to next major version.
+func (c *Client) Deprecations(ctx context.Context) (*DeprecationInfo, error) {
+	var resp DeprecationInfo
+	status, errBody, err := c.doJSONRequest(ctx, http.MethodGet, "/_migration/deprecations", nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, &StatusError{Op: "deprecations", StatusCode: status, body: errBody}
+	}
+	return &resp, nil
+}
+
+// UpgradeReadiness scans settings and mappings of all indices of registered cluster with
+// deprecation info API and reports whether cluster can be upgraded to next major version.
+func (r *Registry) UpgradeReadiness(ctx context.Context, clusterName string) (*UpgradeReport, error) {
+	entry, err := r.GetEntry(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	client, err := r.GetTypedClient(entry.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := client.Deprecations(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get deprecations of cluster %q", entry.Name)
+	}
+
+	report := &UpgradeReport{Cluster: entry.Name, Version: entry.Version, Info: info}
+	count := func(issues []DeprecationIssue) bool {
+		critical := false
+		for _, issue := range issues {
+			switch issue.Level {
+			case DeprecationCritical:
+				report.Critical++
+				critical = true
+			case DeprecationWarning:
+				report.Warnings++
+			}
+		}
+		return critical
+	}
+
+	for _, issues := range [][]DeprecationIssue{info.ClusterSettings, info.NodeSettings, info.MLSettings} {
+		count(issues)
+	}
+	for _, byName := range []map[string][]DeprecationIssue{info.DataStreams, info.Templates, info.ILMPolicies} {
+		for _, issues := range byName {
+			count(issues)
+		}
+	}
+	for index, issues := range info.IndexSettings {
+		if count(issues) {
+			report.CriticalIndices = append(report.CriticalIndices, index)
+		}
+	}
+	sort.Strings(report.CriticalIndices)
+	report.Ready = report.Critical == 0
+
+	return report, nil
+}
@@ -0,0 +1,70 @@
+package esclient
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ClusterUsage represents resource usage snapshot of a single cluster.
+type ClusterUsage struct {
+	Cluster            string  // Cluster name in registry
+	IndexCount         int     // Number of indices
+	ShardCount         int     // Total number of shards (primaries and replicas)
+	DiskUsedBytes      int64   // Store size of all indices
+	DiskTotalBytes     int64   // Total disk space on data nodes
+	DiskAvailBytes     int64   // Available disk space on data nodes
+	JVMHeapUsedBytes   int64   // Heap used across nodes
+	JVMHeapMaxBytes    int64   // Heap max across nodes
+	JVMHeapUsedPercent float64 // Heap used / heap max * 100
+}
+
+// UsageSnapshot returns resource usage of every registered cluster sorted by cluster name.
+func (r *Registry) UsageSnapshot(ctx context.Context) ([]ClusterUsage, error) {
+	names := r.ListClusters()
+	sort.Strings(names)
+
+	result := make([]ClusterUsage, 0, len(names))
+	for _, name := range names {
+		client, err := r.GetTypedClient(name)
+		if IsDegraded(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get client for cluster %q", name)
+		}
+
+		usage, err := client.usage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get usage of cluster %q", name)
+		}
+		usage.Cluster = name
+		result = append(result, *usage)
+	}
+
+	return result, nil
+}
+
+// usage fetches cluster stats and converts them to usage snapshot.
+func (c *Client) usage(ctx context.Context) (*ClusterUsage, error) {
+	stats, err := c.ClusterStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &ClusterUsage{
+		IndexCount:       stats.Indices.Count,
+		ShardCount:       stats.Indices.Shards.Total,
+		DiskUsedBytes:    stats.Indices.Store.SizeInBytes,
+		DiskTotalBytes:   stats.Nodes.FS.TotalInBytes,
+		DiskAvailBytes:   stats.Nodes.FS.AvailableInBytes,
+		JVMHeapUsedBytes: stats.Nodes.JVM.Mem.HeapUsedInBytes,
+		JVMHeapMaxBytes:  stats.Nodes.JVM.Mem.HeapMaxInBytes,
+	}
+	if usage.JVMHeapMaxBytes > 0 {
+		usage.JVMHeapUsedPercent = float64(usage.JVMHeapUsedBytes) / float64(usage.JVMHeapMaxBytes) * 100
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,246 @@
+package esclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Group operation types.
+const (
+	GroupIndex  = "index"  // Create or replace document
+	GroupDelete = "delete" // Delete document
+)
+
+// GroupOperation is single document write of write group.
+type GroupOperation struct {
+	Type    string // GroupIndex or GroupDelete
+	Index   string // Index name
+	ID      string // Document ID
+	Body    any    // Document source of GroupIndex, marshalled to JSON
+	Routing string // Routing value; defaults to company ID of group for shared indices
+
+	// IfSeqNo and IfPrimaryTerm make write conditional on document version, optional.
+	IfSeqNo       *int64
+	IfPrimaryTerm *int64
+}
+
+// WriteGroupRequest represents related document writes (e.g., order and its items)
+// executed as one best-effort unit.
+type WriteGroupRequest struct {
+	Operations []GroupOperation
+	CompanyID  string // Company of documents; stamped into documents of shared indices and used as routing
+	Rollback   bool   // Execute compensating operations on partial failure instead of only returning them
+	Refresh    string // Refresh policy of writes: "true", "false" or "wait_for" (default: client refresh policy)
+}
+
+// WriteGroupResult is result of WriteGroup.
+type WriteGroupResult struct {
+	Succeeded []GroupOperation // Operations applied
+	Failed    map[int]string   // Position of failed operation -> failure reason
+
+	// Compensations restore documents changed by succeeded operations to their state before group,
+	// in reverse order: created documents are deleted, replaced and deleted ones are restored.
+	// Every compensation is conditional on the document still being the version written by group.
+	Compensations []GroupOperation
+	RolledBack    bool // Compensations were executed successfully
+}
+
+// WriteGroup executes related document writes in one bulk request. Previous versions of documents
+// are captured with GetDocument first, so on partial failure compensating operations can be produced
+// and, with Rollback, executed. This is best-effort saga, not a transaction: concurrent readers may see
+// partial group, and compensations skip documents changed by others since the group was written.
+// Returns ErrGroupWriteFailed with result if any operation failed.
+func (c *Client) WriteGroup(ctx context.Context, req *WriteGroupRequest) (*WriteGroupResult, error) {
+	if len(req.Operations) == 0 {
+		return nil, errors.New("group operations are required")
+	}
+
+	// First state of every document in group is what compensation restores
+	previous := make(map[[2]string]*GetDocumentResponse)
+	for i, op := range req.Operations {
+		if err := op.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid group operation %d", i)
+		}
+		key := [2]string{op.Index, op.ID}
+		if _, ok := previous[key]; ok {
+			continue
+		}
+		doc, err := c.GetDocument(ctx, &GetDocumentRequest{Index: op.Index, DocumentID: op.ID, Routing: op.Routing, CompanyID: req.CompanyID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to capture previous version of %s/%s", op.Index, op.ID)
+		}
+		previous[key] = doc
+	}
+
+	resp, err := c.bulkGroup(ctx, req.Operations, req.CompanyID, req.Refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WriteGroupResult{Failed: make(map[int]string)}
+	written := make(map[[2]string]groupWrite)
+	for i, op := range req.Operations {
+		item := groupItem(resp.Items[i])
+		if item == nil {
+			result.Failed[i] = "unexpected bulk item response"
+			continue
+		}
+		if errVal, hasErr := item["error"]; hasErr && errVal != nil {
+			result.Failed[i] = bulkItemErrorReason(errVal)
+			continue
+		}
+		seqNo, _ := item["_seq_no"].(float64)
+		primaryTerm, _ := item["_primary_term"].(float64)
+		written[[2]string{op.Index, op.ID}] = groupWrite{op: op, seqNo: int64(seqNo), primaryTerm: int64(primaryTerm)}
+		result.Succeeded = append(result.Succeeded, op)
+	}
+
+	if len(result.Failed) == 0 {
+		return result, nil
+	}
+
+	// Reverse order, so documents written last are compensated first
+	compensated := make(map[[2]string]bool)
+	for i := len(result.Succeeded) - 1; i >= 0; i-- {
+		key := [2]string{result.Succeeded[i].Index, result.Succeeded[i].ID}
+		if compensated[key] {
+			continue
+		}
+		compensated[key] = true
+		if comp, ok := written[key].compensation(previous[key]); ok {
+			result.Compensations = append(result.Compensations, comp)
+		}
+	}
+
+	if req.Rollback && len(result.Compensations) > 0 {
+		if err := c.rollbackGroup(ctx, req, result.Compensations); err != nil {
+			return result, errors.Wrap(err, "failed to roll back write group")
+		}
+		result.RolledBack = true
+	}
+
+	return result, ErrGroupWriteFailed
+}
+
+// groupWrite is succeeded group operation with version it produced.
+type groupWrite struct {
+	op          GroupOperation
+	seqNo       int64
+	primaryTerm int64
+}
+
+// compensation returns operation restoring document to previous state,
+// conditional on document still having version written by group.
+func (w groupWrite) compensation(prev *GetDocumentResponse) (GroupOperation, bool) {
+	comp := GroupOperation{
+		Index:         w.op.Index,
+		ID:            w.op.ID,
+		Routing:       w.op.Routing,
+		IfSeqNo:       &w.seqNo,
+		IfPrimaryTerm: &w.primaryTerm,
+	}
+	switch {
+	case prev != nil && prev.Found:
+		comp.Type, comp.Body = GroupIndex, prev.Source
+	case w.op.Type == GroupIndex:
+		comp.Type = GroupDelete
+	default:
+		// Deleting missing document changed nothing
+		return GroupOperation{}, false
+	}
+	return comp, true
+}
+
+// rollbackGroup executes compensations and fails if any of them is not applied.
+func (c *Client) rollbackGroup(ctx context.Context, req *WriteGroupRequest, compensations []GroupOperation) error {
+	resp, err := c.bulkGroup(ctx, compensations, req.CompanyID, req.Refresh)
+	if err != nil {
+		return err
+	}
+	errs := &MultiError{}
+	for i, raw := range resp.Items {
+		item := groupItem(raw)
+		if item == nil {
+			continue
+		}
+		if errVal, hasErr := item["error"]; hasErr && errVal != nil {
+			errs.Errors = append(errs.Errors, errors.Errorf("%s %s/%s: %s",
+				compensations[i].Type, compensations[i].Index, compensations[i].ID, bulkItemErrorReason(errVal)))
+		}
+	}
+	return errs.errOrNil()
+}
+
+// bulkGroup writes operations in one bulk request.
+func (c *Client) bulkGroup(ctx context.Context, ops []GroupOperation, companyID, refresh string) (*BulkResponse, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, op := range ops {
+		meta := map[string]any{"_index": op.Index, "_id": op.ID}
+		if routing := routingFor(op.Routing, companyID, DetectIndexTarget(op.Index)); routing != "" {
+			meta["routing"] = routing
+		}
+		if op.IfSeqNo != nil {
+			meta["if_seq_no"] = *op.IfSeqNo
+			meta["if_primary_term"] = *op.IfPrimaryTerm
+		}
+		if err := enc.Encode(map[string]any{op.Type: meta}); err != nil {
+			return nil, errors.Wrap(err, "failed to encode bulk action")
+		}
+		if op.Type == GroupIndex {
+			if err := enc.Encode(op.Body); err != nil {
+				return nil, errors.Wrapf(err, "failed to encode document %q", op.ID)
+			}
+		}
+	}
+
+	client := c
+	if refresh != "" {
+		client = c.With(WithRefresh(refresh))
+	}
+	resp, err := client.Bulk(ctx, &BulkRequest{
+		Body:           &buf,
+		CompanyID:      companyID,
+		StampCompanyID: companyID != "",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "write group bulk request failed")
+	}
+	if len(resp.Items) != len(ops) {
+		return nil, errors.Errorf("write group bulk returned %d items for %d operations", len(resp.Items), len(ops))
+	}
+	return resp, nil
+}
+
+// groupItem returns action result of bulk item.
+func groupItem(item map[string]interface{}) map[string]interface{} {
+	for _, val := range item {
+		if action, ok := val.(map[string]interface{}); ok {
+			return action
+		}
+	}
+	return nil
+}
+
+// validate checks group operation.
+func (op GroupOperation) validate() error {
+	if op.Index == "" || op.ID == "" {
+		return errors.New("index and document ID are required")
+	}
+	if (op.IfSeqNo == nil) != (op.IfPrimaryTerm == nil) {
+		return errors.New("if_seq_no and if_primary_term must be set together")
+	}
+	switch op.Type {
+	case GroupIndex:
+		if op.Body == nil {
+			return errors.New("document body is required")
+		}
+	case GroupDelete:
+	default:
+		return errors.Errorf("unknown operation type %q", op.Type)
+	}
+	return nil
+}